@@ -1,13 +1,14 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"log"
 	"net/http"
 	"time"
 
 	"github.com/EduardoMark/my-finance-api/internal/api"
-	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore"
 	"github.com/EduardoMark/my-finance-api/pkg/config"
 	"github.com/EduardoMark/my-finance-api/pkg/database"
 	"github.com/EduardoMark/my-finance-api/pkg/token"
@@ -25,14 +26,16 @@ func main() {
 	}
 	defer dbPool.Close()
 
-	db := db.New(dbPool)
+	store := pgstore.NewStore(dbPool)
 
 	token := token.NewTokenManager(*cfg)
 
-	apiInstance := api.NewApi(cfg, db, token)
+	apiInstance := api.NewApi(cfg, store, token)
 	apiInstance.SetupApi()
 	apiInstance.BindRoutes()
 
+	go apiInstance.Scheduler.Run(context.Background())
+
 	srv := http.Server{
 		Addr:              ":3000",
 		Handler:           apiInstance.Router,
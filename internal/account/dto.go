@@ -4,41 +4,145 @@ import (
 	"context"
 	"time"
 
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
 	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
 )
 
+var typeOptions = map[string]bool{
+	string(db.AccountTypeChecking):   true,
+	string(db.AccountTypeSavings):    true,
+	string(db.AccountTypeCash):       true,
+	string(db.AccountTypeCreditCard): true,
+	string(db.AccountTypeLoan):       true,
+	string(db.AccountTypeInvestment): true,
+	string(db.AccountTypeWallet):     true,
+}
+
+const typeMessage = "this field must be checking, savings, cash, credit_card, loan, investment or wallet"
+
+// IsLiability reports whether accounts of type t hold money owed. Their
+// balance is negative while there is debt and they count negatively towards
+// the net worth; only they may have a negative balance.
+func IsLiability(t db.AccountType) bool {
+	return t == db.AccountTypeCreditCard || t == db.AccountTypeLoan
+}
+
 type AccountCreateRequest struct {
-	Name    string   `json:"name" validate:"required"`
-	Type    string   `json:"type"`
-	Balance *float64 `json:"balance"`
+	Name    string       `json:"name" validate:"required"`
+	Type    string       `json:"type"`
+	Balance *money.Money `json:"balance"`
+
+	// Currency defaults to the user's base currency and cannot change later,
+	// since balances and transactions are stored in it.
+	Currency string `json:"currency,omitempty"`
+
+	// Only credit cards have a limit and statement days; closing_day and
+	// due_day are required for them.
+	CreditLimit *money.Money `json:"credit_limit,omitempty"`
+	ClosingDay  *int         `json:"closing_day,omitempty"`
+	DueDay      *int         `json:"due_day,omitempty"`
 }
 
 type AccountResponse struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Name      string    `json:"name"`
-	Type      string    `json:"type"`
-	Balance   float64   `json:"balance"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              string       `json:"id"`
+	UserID          string       `json:"user_id"`
+	Name            string       `json:"name"`
+	Type            string       `json:"type"`
+	Balance         money.Money  `json:"balance"`
+	Currency        string       `json:"currency"`
+	Liability       bool         `json:"liability"`
+	CreditLimit     *money.Money `json:"credit_limit,omitempty"`
+	AvailableCredit *money.Money `json:"available_credit,omitempty"`
+	ClosingDay      *int         `json:"closing_day,omitempty"`
+	DueDay          *int         `json:"due_day,omitempty"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+func AccountToResponse(record *db.Account) AccountResponse {
+	response := AccountResponse{
+		ID:        record.ID.String(),
+		UserID:    record.UserID.String(),
+		Name:      record.Name,
+		Type:      string(record.Type),
+		Balance:   record.Balance,
+		Currency:  record.Currency,
+		Liability: record.Liability,
+		CreatedAt: record.CreatedAt.Time,
+		UpdatedAt: record.UpdatedAt.Time,
+	}
+
+	if record.CreditLimit.Valid {
+		limit := money.FromCents(record.CreditLimit.Int64)
+		// The balance of a card is negative while there is debt.
+		available := limit + record.Balance
+		response.CreditLimit = &limit
+		response.AvailableCredit = &available
+	}
+
+	if record.ClosingDay.Valid {
+		day := int(record.ClosingDay.Int16)
+		response.ClosingDay = &day
+	}
+
+	if record.DueDay.Valid {
+		day := int(record.DueDay.Int16)
+		response.DueDay = &day
+	}
+
+	return response
 }
 
 func (r *AccountCreateRequest) Valid(ctx context.Context) validator.Evaluator {
 	var eval validator.Evaluator
 
 	eval.CheckField(validator.NotBlank(r.Name), "name", "this field cannot be empty")
-	eval.CheckField(validator.NotBlank(r.Type), "type", "this field cannot be empty")
+	eval.CheckField(typeOptions[r.Type], "type", typeMessage)
+
+	if r.Currency != "" {
+		eval.CheckField(validator.Currency(r.Currency), "currency", "this field must be an upper case ISO 4217 code such as BRL")
+	}
 
-	if r.Balance != nil {
+	if r.Balance != nil && !IsLiability(db.AccountType(r.Type)) {
 		eval.CheckField(validator.CheckBalance(*r.Balance), "balance", "this field must be bigger than or equal to 0")
 	}
 
+	if db.AccountType(r.Type) == db.AccountTypeCreditCard {
+		eval.CheckField(r.ClosingDay != nil, "closing_day", "this field is required for credit_card accounts")
+		eval.CheckField(r.DueDay != nil, "due_day", "this field is required for credit_card accounts")
+		checkCardFields(&eval, r.CreditLimit, r.ClosingDay, r.DueDay)
+	} else {
+		eval.CheckField(r.CreditLimit == nil, "credit_limit", "this field is only allowed on credit_card accounts")
+		eval.CheckField(r.ClosingDay == nil, "closing_day", "this field is only allowed on credit_card accounts")
+		eval.CheckField(r.DueDay == nil, "due_day", "this field is only allowed on credit_card accounts")
+	}
+
 	return eval
 }
 
+func checkCardFields(eval *validator.Evaluator, limit *money.Money, closingDay, dueDay *int) {
+	if limit != nil {
+		eval.CheckField(*limit >= 0, "credit_limit", "this field must be bigger than or equal to 0")
+	}
+
+	if closingDay != nil {
+		eval.CheckField(*closingDay >= 1 && *closingDay <= 31, "closing_day", "this field must be between 1 and 31")
+	}
+
+	if dueDay != nil {
+		eval.CheckField(*dueDay >= 1 && *dueDay <= 31, "due_day", "this field must be between 1 and 31")
+	}
+}
+
 type AccountUpdateAccountReq struct {
 	Name string `json:"name"`
 	Type string `json:"type"`
+
+	// Changing the type away from credit_card clears the card fields.
+	CreditLimit *money.Money `json:"credit_limit,omitempty"`
+	ClosingDay  *int         `json:"closing_day,omitempty"`
+	DueDay      *int         `json:"due_day,omitempty"`
 }
 
 func (r *AccountUpdateAccountReq) Valid(ctx context.Context) validator.Evaluator {
@@ -46,9 +150,16 @@ func (r *AccountUpdateAccountReq) Valid(ctx context.Context) validator.Evaluator
 
 	eval.CheckField(
 		validator.NotBlank(r.Name) ||
-			validator.NotBlank(r.Type),
+			validator.NotBlank(r.Type) ||
+			r.CreditLimit != nil || r.ClosingDay != nil || r.DueDay != nil,
 		"fields", "at least one field must be sent to update",
 	)
 
+	if r.Type != "" {
+		eval.CheckField(typeOptions[r.Type], "type", typeMessage)
+	}
+
+	checkCardFields(&eval, r.CreditLimit, r.ClosingDay, r.DueDay)
+
 	return eval
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/EduardoMark/my-finance-api/internal/books"
 	"github.com/EduardoMark/my-finance-api/internal/middlewares"
 	"github.com/EduardoMark/my-finance-api/pkg/httputils"
 	"github.com/EduardoMark/my-finance-api/pkg/token"
@@ -62,10 +63,16 @@ func (h *AccountHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
 
-	record, err := h.svc.GetAccount(ctx, id)
+	userId, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userId == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	record, err := h.svc.GetAccount(ctx, userId, id)
 	if err != nil {
 		if errors.Is(err, ErrAccountNotFound) {
-			httputils.Error(w, r, http.StatusBadRequest, "account not found")
+			httputils.Error(w, r, http.StatusNotFound, "account not found")
 			return
 		}
 
@@ -73,15 +80,7 @@ func (h *AccountHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := AccountResponse{
-		ID:        record.ID.String(),
-		UserID:    record.UserID.String(),
-		Name:      record.Name,
-		Type:      record.Type,
-		Balance:   record.Balance.Float64,
-		CreatedAt: record.CreatedAt.Time,
-		UpdatedAt: record.UpdatedAt.Time,
-	}
+	response := AccountToResponse(record)
 
 	httputils.EncodeJson(w, r, http.StatusOK, response)
 }
@@ -108,15 +107,7 @@ func (h *AccountHandler) GetAllAccountsPerUser(w http.ResponseWriter, r *http.Re
 
 	response := make([]AccountResponse, len(records))
 	for i, record := range records {
-		response[i] = AccountResponse{
-			ID:        record.ID.String(),
-			UserID:    record.UserID.String(),
-			Name:      record.Name,
-			Type:      record.Type,
-			Balance:   record.Balance.Float64,
-			CreatedAt: record.CreatedAt.Time,
-			UpdatedAt: record.UpdatedAt.Time,
-		}
+		response[i] = AccountToResponse(record)
 	}
 
 	httputils.EncodeJson(w, r, http.StatusOK, response)
@@ -126,6 +117,12 @@ func (h *AccountHandler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
 
+	userId, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userId == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
 	data, problems, err := httputils.DecodeValidJson[*AccountUpdateAccountReq](r)
 	if err != nil {
 		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
@@ -133,9 +130,19 @@ func (h *AccountHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
-	if err := h.svc.UpdateAccount(ctx, id, *data); err != nil {
-		if err == ErrAccountNotFound {
-			httputils.Error(w, r, http.StatusBadRequest, ErrAccountNotFound.Error())
+	if err := h.svc.UpdateAccount(ctx, userId, id, *data); err != nil {
+		if errors.Is(err, ErrAccountNotFound) {
+			httputils.Error(w, r, http.StatusNotFound, ErrAccountNotFound.Error())
+			return
+		}
+
+		if errors.Is(err, ErrNegativeBalance) {
+			httputils.Error(w, r, http.StatusConflict, err.Error())
+			return
+		}
+
+		if errors.Is(err, ErrCreditCardFields) || errors.Is(err, ErrStatementDaysRequired) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"fields": err.Error()})
 			return
 		}
 
@@ -156,9 +163,14 @@ func (h *AccountHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.svc.Delete(ctx, id); err != nil {
+	if err := h.svc.Delete(ctx, userId, id); err != nil {
 		if errors.Is(err, ErrAccountNotFound) {
-			httputils.Error(w, r, http.StatusInternalServerError, err.Error())
+			httputils.Error(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+
+		if errors.Is(err, ErrReconciledTransactions) || errors.Is(err, ErrHasTransfers) || errors.Is(err, books.ErrClosed) {
+			httputils.Error(w, r, http.StatusConflict, err.Error())
 			return
 		}
 
@@ -6,15 +6,19 @@ import (
 	"errors"
 
 	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
 	"github.com/google/uuid"
 )
 
 type Repository interface {
 	Create(ctx context.Context, args db.CreateAccountParams) error
-	GetAccount(ctx context.Context, id uuid.UUID) (*db.Account, error)
+	GetAccount(ctx context.Context, id, userID uuid.UUID) (*db.Account, error)
 	GetAccountByUserID(ctx context.Context, userID uuid.UUID) ([]*db.Account, error)
 	UpdateAccount(ctx context.Context, args db.UpdateAccountParams) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+	AdjustBalance(ctx context.Context, id uuid.UUID, delta money.Money) error
+	GetLedgerSummary(ctx context.Context, id uuid.UUID) (*db.GetAccountLedgerSummaryRow, error)
+	WithTx(q *db.Queries) Repository
 }
 
 type accountRepository struct {
@@ -27,6 +31,11 @@ func NewAccountRepo(db *db.Queries) Repository {
 
 var ErrAccountNotFound = errors.New("account not found")
 var ErrNoAccountsFound = errors.New("accounts not found")
+var ErrNegativeBalance = errors.New("only credit card and loan accounts can have a negative balance")
+var ErrCreditCardFields = errors.New("credit_limit, closing_day and due_day are only allowed on credit_card accounts")
+var ErrStatementDaysRequired = errors.New("closing_day and due_day are required for credit_card accounts")
+var ErrReconciledTransactions = errors.New("account has reconciled transactions and cannot be deleted")
+var ErrHasTransfers = errors.New("account has transfers, delete them before deleting the account")
 
 func (r *accountRepository) Create(ctx context.Context, args db.CreateAccountParams) error {
 	err := r.db.CreateAccount(ctx, args)
@@ -37,8 +46,8 @@ func (r *accountRepository) Create(ctx context.Context, args db.CreateAccountPar
 	return nil
 }
 
-func (r *accountRepository) GetAccount(ctx context.Context, id uuid.UUID) (*db.Account, error) {
-	record, err := r.db.GetAccount(ctx, id)
+func (r *accountRepository) GetAccount(ctx context.Context, id, userID uuid.UUID) (*db.Account, error) {
+	record, err := r.db.GetAccount(ctx, db.GetAccountParams{ID: id, UserID: userID})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrAccountNotFound
@@ -67,14 +76,53 @@ func (r *accountRepository) UpdateAccount(ctx context.Context, args db.UpdateAcc
 	return nil
 }
 
-func (r *accountRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	_, err := r.GetAccount(ctx, id)
+func (r *accountRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	_, err := r.GetAccount(ctx, id, userID)
 	if err != nil {
 		return err
 	}
 
-	if err := r.db.DeleteAccount(ctx, id); err != nil {
+	if err := r.db.DeleteAccount(ctx, db.DeleteAccountParams{ID: id, UserID: userID}); err != nil {
 		return err
 	}
 	return nil
 }
+
+// AdjustBalance adds delta to the balance. It returns ErrNegativeBalance
+// when a withdrawal would leave an asset account below zero, and
+// ErrAccountNotFound when the account no longer exists.
+func (r *accountRepository) AdjustBalance(ctx context.Context, id uuid.UUID, delta money.Money) error {
+	args := db.AdjustAccountBalanceParams{
+		ID:    id,
+		Delta: delta.Cents(),
+	}
+
+	rows, err := r.db.AdjustAccountBalance(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		exists, err := r.db.AccountExists(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrAccountNotFound
+		}
+		return ErrNegativeBalance
+	}
+	return nil
+}
+
+func (r *accountRepository) GetLedgerSummary(ctx context.Context, id uuid.UUID) (*db.GetAccountLedgerSummaryRow, error) {
+	summary, err := r.db.GetAccountLedgerSummary(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func (r *accountRepository) WithTx(q *db.Queries) Repository {
+	return &accountRepository{db: q}
+}
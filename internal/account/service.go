@@ -4,25 +4,30 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/EduardoMark/my-finance-api/internal/books"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore"
 	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
-	"github.com/EduardoMark/my-finance-api/pkg/converter"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Service interface {
 	Create(ctx context.Context, userID string, dto AccountCreateRequest) error
-	GetAccount(ctx context.Context, id string) (*db.Account, error)
+	GetAccount(ctx context.Context, userID, id string) (*db.Account, error)
 	GetAllAccountsByUserID(ctx context.Context, userID string) ([]*db.Account, error)
-	UpdateAccount(ctx context.Context, id string, args AccountUpdateAccountReq) error
-	Delete(ctx context.Context, id string) error
+	UpdateAccount(ctx context.Context, userID, id string, args AccountUpdateAccountReq) error
+	Delete(ctx context.Context, userID, id string) error
 }
 
 type accountService struct {
-	repo Repository
+	repo  Repository
+	books books.Repository
+	uow   pgstore.UnitOfWork
 }
 
-func NewAccountService(repo Repository) Service {
-	return &accountService{repo: repo}
+func NewAccountService(repo Repository, books books.Repository, uow pgstore.UnitOfWork) Service {
+	return &accountService{repo: repo, books: books, uow: uow}
 }
 
 func (s *accountService) Create(ctx context.Context, userID string, dto AccountCreateRequest) error {
@@ -34,17 +39,21 @@ func (s *accountService) Create(ctx context.Context, userID string, dto AccountC
 	params := db.CreateAccountParams{
 		UserID: userUUID,
 		Name:   dto.Name,
+		Type:   db.AccountType(dto.Type),
 	}
 
-	if dto.Type != "" {
-		params.Type = dto.Type
+	if dto.CreditLimit != nil {
+		params.CreditLimit = pgtype.Int8{Int64: dto.CreditLimit.Cents(), Valid: true}
 	}
+	params.ClosingDay = dayParam(dto.ClosingDay)
+	params.DueDay = dayParam(dto.DueDay)
+	params.Currency = pgtype.Text{String: dto.Currency, Valid: dto.Currency != ""}
 
 	// Define balance como 0 por padrão se não informado
 	if dto.Balance != nil {
-		params.Balance = converter.ToFloat8(*dto.Balance)
+		params.Balance = *dto.Balance
 	} else {
-		params.Balance = converter.ToFloat8(0)
+		params.Balance = money.FromCents(0)
 	}
 
 	if err := s.repo.Create(ctx, params); err != nil {
@@ -54,13 +63,18 @@ func (s *accountService) Create(ctx context.Context, userID string, dto AccountC
 	return nil
 }
 
-func (s *accountService) GetAccount(ctx context.Context, id string) (*db.Account, error) {
-	idUUID, err := uuid.Parse(id)
+func (s *accountService) GetAccount(ctx context.Context, userID, id string) (*db.Account, error) {
+	userUUID, err := uuid.Parse(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	record, err := s.repo.GetAccount(ctx, idUUID)
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrAccountNotFound
+	}
+
+	record, err := s.repo.GetAccount(ctx, idUUID, userUUID)
 	if err != nil {
 		return nil, err
 	}
@@ -82,21 +96,30 @@ func (s *accountService) GetAllAccountsByUserID(ctx context.Context, userID stri
 	return records, nil
 }
 
-func (s *accountService) UpdateAccount(ctx context.Context, id string, args AccountUpdateAccountReq) error {
-	idUUID, err := uuid.Parse(id)
+func (s *accountService) UpdateAccount(ctx context.Context, userID, id string, args AccountUpdateAccountReq) error {
+	userUUID, err := uuid.Parse(userID)
 	if err != nil {
 		return err
 	}
 
-	record, err := s.repo.GetAccount(ctx, idUUID)
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrAccountNotFound
+	}
+
+	record, err := s.repo.GetAccount(ctx, idUUID, userUUID)
 	if err != nil {
 		return err
 	}
 
 	updateParams := db.UpdateAccountParams{
-		ID:   record.ID,
-		Name: record.Name,
-		Type: record.Type,
+		ID:          record.ID,
+		Name:        record.Name,
+		Type:        record.Type,
+		CreditLimit: record.CreditLimit,
+		ClosingDay:  record.ClosingDay,
+		DueDay:      record.DueDay,
+		UserID:      userUUID,
 	}
 
 	if args.Name != "" {
@@ -104,7 +127,37 @@ func (s *accountService) UpdateAccount(ctx context.Context, id string, args Acco
 	}
 
 	if args.Type != "" {
-		updateParams.Type = args.Type
+		updateParams.Type = db.AccountType(args.Type)
+	}
+
+	if args.CreditLimit != nil {
+		updateParams.CreditLimit = pgtype.Int8{Int64: args.CreditLimit.Cents(), Valid: true}
+	}
+
+	if args.ClosingDay != nil {
+		updateParams.ClosingDay = dayParam(args.ClosingDay)
+	}
+
+	if args.DueDay != nil {
+		updateParams.DueDay = dayParam(args.DueDay)
+	}
+
+	if updateParams.Type == db.AccountTypeCreditCard {
+		if !updateParams.ClosingDay.Valid || !updateParams.DueDay.Valid {
+			return ErrStatementDaysRequired
+		}
+	} else {
+		if args.CreditLimit != nil || args.ClosingDay != nil || args.DueDay != nil {
+			return ErrCreditCardFields
+		}
+		updateParams.CreditLimit = pgtype.Int8{}
+		updateParams.ClosingDay = pgtype.Int2{}
+		updateParams.DueDay = pgtype.Int2{}
+	}
+
+	// A card or loan with debt cannot become an asset account.
+	if record.Balance < 0 && !IsLiability(updateParams.Type) {
+		return ErrNegativeBalance
 	}
 
 	if err := s.repo.UpdateAccount(ctx, updateParams); err != nil {
@@ -114,15 +167,52 @@ func (s *accountService) UpdateAccount(ctx context.Context, id string, args Acco
 	return nil
 }
 
-func (s *accountService) Delete(ctx context.Context, id string) error {
-	idUUID, err := uuid.Parse(id)
+func dayParam(day *int) pgtype.Int2 {
+	if day == nil {
+		return pgtype.Int2{}
+	}
+	return pgtype.Int2{Int16: int16(*day), Valid: true}
+}
+
+func (s *accountService) Delete(ctx context.Context, userID, id string) error {
+	userUUID, err := uuid.Parse(userID)
 	if err != nil {
 		return err
 	}
 
-	if err := s.repo.Delete(ctx, idUUID); err != nil {
-		return err
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrAccountNotFound
 	}
 
-	return nil
+	// Its transactions go with the account, so the account can only be
+	// deleted while none of them is reconciled or in a closed period. A
+	// transfer would lose only the leg in this account, leaving the other
+	// one in the balance of the counterpart account.
+	return s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+
+		if _, err := repo.GetAccount(ctx, idUUID, userUUID); err != nil {
+			return err
+		}
+
+		summary, err := repo.GetLedgerSummary(ctx, idUUID)
+		if err != nil {
+			return err
+		}
+
+		if summary.Reconciled {
+			return ErrReconciledTransactions
+		}
+
+		if summary.Transfers {
+			return ErrHasTransfers
+		}
+
+		if err := s.books.WithTx(q).CheckOpen(ctx, userUUID, summary.FirstDate); err != nil {
+			return err
+		}
+
+		return repo.Delete(ctx, idUUID, userUUID)
+	})
 }
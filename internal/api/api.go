@@ -1,10 +1,26 @@
 package api
 
 import (
+	"time"
+
 	"github.com/EduardoMark/my-finance-api/internal/account"
+	"github.com/EduardoMark/my-finance-api/internal/books"
+	"github.com/EduardoMark/my-finance-api/internal/budget"
 	"github.com/EduardoMark/my-finance-api/internal/category"
+	"github.com/EduardoMark/my-finance-api/internal/creditcard"
+	"github.com/EduardoMark/my-finance-api/internal/exchangerate"
+	"github.com/EduardoMark/my-finance-api/internal/goal"
+	"github.com/EduardoMark/my-finance-api/internal/imports"
+	"github.com/EduardoMark/my-finance-api/internal/payee"
+	"github.com/EduardoMark/my-finance-api/internal/reconciliation"
+	"github.com/EduardoMark/my-finance-api/internal/recurring"
+	"github.com/EduardoMark/my-finance-api/internal/report"
+	"github.com/EduardoMark/my-finance-api/internal/rule"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore"
 	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/tag"
 	"github.com/EduardoMark/my-finance-api/internal/transaction"
+	"github.com/EduardoMark/my-finance-api/internal/transfer"
 	"github.com/EduardoMark/my-finance-api/internal/user"
 	"github.com/EduardoMark/my-finance-api/pkg/config"
 	"github.com/EduardoMark/my-finance-api/pkg/token"
@@ -12,50 +28,136 @@ import (
 )
 
 type Handler struct {
-	User        *user.UserHandler
-	Account     *account.AccountHandler
-	Category    *category.CategoryHandler
-	Transaction *transaction.TransactionHandler
+	User           *user.UserHandler
+	Account        *account.AccountHandler
+	Category       *category.CategoryHandler
+	Transaction    *transaction.TransactionHandler
+	Transfer       *transfer.TransferHandler
+	Budget         *budget.BudgetHandler
+	Recurring      *recurring.RecurringHandler
+	Import         *imports.ImportHandler
+	Tag            *tag.TagHandler
+	Report         *report.ReportHandler
+	CreditCard     *creditcard.CreditCardHandler
+	Goal           *goal.GoalHandler
+	ExchangeRate   *exchangerate.ExchangeRateHandler
+	Payee          *payee.PayeeHandler
+	Rule           *rule.RuleHandler
+	Reconciliation *reconciliation.ReconciliationHandler
+	Books          *books.BooksHandler
 }
 
 type Api struct {
-	Router  *chi.Mux
-	Cfg     *config.Env
-	Db      *db.Queries
-	Token   *token.TokenManager
-	Handler *Handler
+	Router    *chi.Mux
+	Cfg       *config.Env
+	Db        *db.Queries
+	Store     *pgstore.Store
+	Token     *token.TokenManager
+	Handler   *Handler
+	Scheduler *recurring.Scheduler
 }
 
-func NewApi(cfg *config.Env, db *db.Queries, token *token.TokenManager) *Api {
+func NewApi(cfg *config.Env, store *pgstore.Store, token *token.TokenManager) *Api {
 	return &Api{
 		Router: chi.NewRouter(),
 		Cfg:    cfg,
-		Db:     db,
+		Db:     store.Queries,
+		Store:  store,
 		Token:  token,
 	}
 }
 
 func (api *Api) SetupApi() {
 	userRepo := user.NewUserRepository(api.Db)
-	userSvc := user.NewUserService(userRepo)
+	userSvc := user.NewUserService(userRepo, api.Store)
+	api.Token.UseRevocationList(userRepo)
 	userHandler := user.NewUserHandler(userSvc, api.Token)
 
+	booksRepo := books.NewBooksRepository(api.Db)
+
 	accRepo := account.NewAccountRepo(api.Db)
-	accSvc := account.NewAccountService(accRepo)
+	accSvc := account.NewAccountService(accRepo, booksRepo, api.Store)
 	accHandler := account.NewAccountHandler(accSvc, api.Token)
 
 	ctRepo := category.NewCategoryRepository(api.Db)
 	ctSvc := category.NewCategoryService(ctRepo)
 	ctHandler := category.NewCategoryHandler(ctSvc, api.Token)
 
+	booksSvc := books.NewBooksService(booksRepo, api.Store)
+	booksHandler := books.NewBooksHandler(booksSvc, api.Token)
+
+	rateRepo := exchangerate.NewExchangeRateRepository(api.Db)
+	rateSvc := exchangerate.NewExchangeRateService(rateRepo, api.Store)
+	rateHandler := exchangerate.NewExchangeRateHandler(rateSvc, api.Token)
+
+	trfRepo := transfer.NewTransferRepo(api.Db)
+	trfSvc := transfer.NewTransferService(trfRepo, accRepo, rateRepo, booksRepo, api.Store)
+	trfHandler := transfer.NewTransferHandler(trfSvc, api.Token)
+
+	payeeRepo := payee.NewPayeeRepository(api.Db)
+	payeeSvc := payee.NewPayeeService(payeeRepo, ctRepo, api.Store)
+	payeeHandler := payee.NewPayeeHandler(payeeSvc, api.Token)
+
+	ruleRepo := rule.NewRuleRepository(api.Db)
+	ruleSvc := rule.NewRuleService(ruleRepo, accRepo, ctRepo, payeeRepo)
+
 	transRepo := transaction.NewTransactionRepo(api.Db)
-	transSvc := transaction.NewTransactionService(transRepo)
+	transSvc := transaction.NewTransactionService(transRepo, accRepo, ctRepo, payeeRepo, ruleRepo, booksRepo, trfSvc, api.Store)
 	transHandler := transaction.NewTransactionHandler(transSvc, api.Token)
 
+	ruleHandler := rule.NewRuleHandler(ruleSvc, transSvc, api.Token)
+
+	budgetRepo := budget.NewBudgetRepository(api.Db)
+	budgetSvc := budget.NewBudgetService(budgetRepo, ctRepo)
+	budgetHandler := budget.NewBudgetHandler(budgetSvc, api.Token)
+
+	recurringRepo := recurring.NewRecurringRepo(api.Db)
+	recurringSvc := recurring.NewRecurringService(recurringRepo, accRepo, ctRepo, booksRepo, api.Store)
+	recurringHandler := recurring.NewRecurringHandler(recurringSvc, api.Token)
+
+	api.Scheduler = recurring.NewScheduler(recurringSvc, time.Hour)
+
+	importRepo := imports.NewImportRepository(api.Db)
+	importSvc := imports.NewImportService(importRepo, accRepo, ctRepo, transSvc)
+	importHandler := imports.NewImportHandler(importSvc, api.Token)
+
+	tagRepo := tag.NewTagRepository(api.Db)
+	tagSvc := tag.NewTagService(tagRepo)
+	tagHandler := tag.NewTagHandler(tagSvc, api.Token)
+
+	reportRepo := report.NewReportRepository(api.Db)
+	reportSvc := report.NewReportService(reportRepo, accRepo)
+	reportHandler := report.NewReportHandler(reportSvc, api.Token)
+
+	cardRepo := creditcard.NewCreditCardRepository(api.Db)
+	cardSvc := creditcard.NewCreditCardService(cardRepo, accRepo, rateRepo, trfSvc)
+	cardHandler := creditcard.NewCreditCardHandler(cardSvc, api.Token)
+
+	goalRepo := goal.NewGoalRepository(api.Db)
+	goalSvc := goal.NewGoalService(goalRepo, accRepo, trfSvc)
+	goalHandler := goal.NewGoalHandler(goalSvc, api.Token)
+
+	reconRepo := reconciliation.NewReconciliationRepository(api.Db)
+	reconSvc := reconciliation.NewReconciliationService(reconRepo, accRepo, api.Store)
+	reconHandler := reconciliation.NewReconciliationHandler(reconSvc, api.Token)
+
 	api.Handler = &Handler{
-		User:        userHandler,
-		Account:     &accHandler,
-		Category:    &ctHandler,
-		Transaction: &transHandler,
+		User:           userHandler,
+		Account:        &accHandler,
+		Category:       &ctHandler,
+		Transaction:    &transHandler,
+		Transfer:       &trfHandler,
+		Budget:         &budgetHandler,
+		Recurring:      &recurringHandler,
+		Import:         &importHandler,
+		Tag:            &tagHandler,
+		Report:         &reportHandler,
+		CreditCard:     &cardHandler,
+		Goal:           &goalHandler,
+		ExchangeRate:   &rateHandler,
+		Payee:          &payeeHandler,
+		Rule:           &ruleHandler,
+		Reconciliation: &reconHandler,
+		Books:          &booksHandler,
 	}
 }
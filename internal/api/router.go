@@ -14,6 +14,19 @@ func (api *Api) BindRoutes() {
 			api.Handler.Account.RegisterAccountRoutes(r)
 			api.Handler.Category.RegisterCategoryRoutes(r)
 			api.Handler.Transaction.RegisterRoutes(r)
+			api.Handler.Transfer.RegisterRoutes(r)
+			api.Handler.Budget.RegisterBudgetRoutes(r)
+			api.Handler.Recurring.RegisterRoutes(r)
+			api.Handler.Import.RegisterImportRoutes(r)
+			api.Handler.Tag.RegisterTagRoutes(r)
+			api.Handler.Report.RegisterReportRoutes(r)
+			api.Handler.CreditCard.RegisterCreditCardRoutes(r)
+			api.Handler.Goal.RegisterGoalRoutes(r)
+			api.Handler.ExchangeRate.RegisterExchangeRateRoutes(r)
+			api.Handler.Payee.RegisterPayeeRoutes(r)
+			api.Handler.Rule.RegisterRuleRoutes(r)
+			api.Handler.Reconciliation.RegisterReconciliationRoutes(r)
+			api.Handler.Books.RegisterBooksRoutes(r)
 		})
 
 	})
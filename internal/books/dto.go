@@ -0,0 +1,87 @@
+package books
+
+import (
+	"context"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const maxReasonChars = 500
+
+// CloseReq moves the closing date forward. Closing a future date is
+// rejected, since it would lock transactions still to come.
+type CloseReq struct {
+	ClosedThrough string `json:"closed_through"` // formato: YYYY-MM-DD
+	Reason        string `json:"reason,omitempty"`
+}
+
+func (r *CloseReq) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.Date(r.ClosedThrough), "closed_through", "this field must be a date in the format YYYY-MM-DD")
+	if date, err := time.Parse("2006-01-02", r.ClosedThrough); err == nil {
+		eval.CheckField(!date.After(time.Now()), "closed_through", "this field cannot be a future date")
+	}
+	eval.CheckField(validator.MaxChars(r.Reason, maxReasonChars), "reason", "this field must have at most 500 characters")
+
+	return eval
+}
+
+// ReopenReq moves the closing date back, or removes it when ClosedThrough
+// is null. The reason is kept in the history of the books.
+type ReopenReq struct {
+	ClosedThrough *string `json:"closed_through"` // formato: YYYY-MM-DD
+	Reason        string  `json:"reason"`
+}
+
+func (r *ReopenReq) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	if r.ClosedThrough != nil {
+		eval.CheckField(validator.Date(*r.ClosedThrough), "closed_through", "this field must be a date in the format YYYY-MM-DD")
+	}
+	eval.CheckField(validator.NotBlank(r.Reason), "reason", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(r.Reason, maxReasonChars), "reason", "this field must have at most 500 characters")
+
+	return eval
+}
+
+type BooksRes struct {
+	ClosedThrough *string `json:"closed_through"`
+}
+
+type EventRes struct {
+	ID            string    `json:"id"`
+	Action        string    `json:"action"` // "close" ou "reopen"
+	PreviousDate  *string   `json:"previous_date"`
+	ClosedThrough *string   `json:"closed_through"`
+	Reason        *string   `json:"reason,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func EventToResponse(record *db.BookClosingEvent) EventRes {
+	res := EventRes{
+		ID:            record.ID.String(),
+		Action:        string(record.Action),
+		PreviousDate:  dateString(record.PreviousDate),
+		ClosedThrough: dateString(record.ClosedThrough),
+		CreatedAt:     record.CreatedAt.Time,
+	}
+
+	if record.Reason.Valid {
+		res.Reason = &record.Reason.String
+	}
+
+	return res
+}
+
+func dateString(date pgtype.Date) *string {
+	if !date.Valid {
+		return nil
+	}
+	value := date.Time.Format("2006-01-02")
+	return &value
+}
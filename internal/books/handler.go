@@ -0,0 +1,131 @@
+package books
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EduardoMark/my-finance-api/internal/middlewares"
+	"github.com/EduardoMark/my-finance-api/pkg/httputils"
+	"github.com/EduardoMark/my-finance-api/pkg/token"
+	"github.com/go-chi/chi/v5"
+)
+
+type BooksHandler struct {
+	svc   Service
+	token *token.TokenManager
+}
+
+func NewBooksHandler(svc Service, token *token.TokenManager) BooksHandler {
+	return BooksHandler{
+		svc:   svc,
+		token: token,
+	}
+}
+
+func (h *BooksHandler) RegisterBooksRoutes(r chi.Router) {
+	r.Route("/books", func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(h.token))
+
+		r.Get("/", h.Get)
+		r.Post("/close", h.Close)
+		r.Post("/reopen", h.Reopen)
+		r.Get("/events", h.GetEvents)
+	})
+}
+
+func (h *BooksHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	res, err := h.svc.Get(ctx, userID)
+	if err != nil {
+		writeBooksError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *BooksHandler) Close(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*CloseReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	res, err := h.svc.Close(ctx, userID, data)
+	if err != nil {
+		writeBooksError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *BooksHandler) Reopen(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*ReopenReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	res, err := h.svc.Reopen(ctx, userID, data)
+	if err != nil {
+		writeBooksError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *BooksHandler) GetEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	res, err := h.svc.GetEvents(ctx, userID)
+	if err != nil {
+		writeBooksError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func writeBooksError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrNotClosed):
+		_ = httputils.EncodeJson(w, r, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrNotAfter), errors.Is(err, ErrNotBefore):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"closed_through": err.Error()})
+	default:
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
@@ -0,0 +1,84 @@
+package books
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Repository interface {
+	GetClosedThrough(ctx context.Context, userID uuid.UUID) (pgtype.Date, error)
+	GetClosedThroughForUpdate(ctx context.Context, userID uuid.UUID) (pgtype.Date, error)
+	SetClosedThrough(ctx context.Context, userID uuid.UUID, date pgtype.Date) error
+	CreateEvent(ctx context.Context, arg db.CreateBookClosingEventParams) (*db.BookClosingEvent, error)
+	GetEvents(ctx context.Context, userID uuid.UUID) ([]*db.BookClosingEvent, error)
+	CheckOpen(ctx context.Context, userID uuid.UUID, dates ...pgtype.Date) error
+	WithTx(q *db.Queries) Repository
+}
+
+type booksRepository struct {
+	db *db.Queries
+}
+
+func NewBooksRepository(db *db.Queries) Repository {
+	return &booksRepository{db: db}
+}
+
+// ErrClosed is returned by every change to a transaction dated on or before
+// the closing date. The books must be reopened first.
+var ErrClosed = errors.New("books are closed")
+var ErrNotClosed = errors.New("books are not closed")
+var ErrNotAfter = errors.New("closed_through must be after the current closing date, reopen the books to move it back")
+var ErrNotBefore = errors.New("closed_through must be before the current closing date")
+
+// GetClosedThrough returns an invalid date while the user has no closed
+// period.
+func (r *booksRepository) GetClosedThrough(ctx context.Context, userID uuid.UUID) (pgtype.Date, error) {
+	return r.db.GetBooksClosedThrough(ctx, userID)
+}
+
+func (r *booksRepository) GetClosedThroughForUpdate(ctx context.Context, userID uuid.UUID) (pgtype.Date, error) {
+	return r.db.GetBooksClosedThroughForUpdate(ctx, userID)
+}
+
+func (r *booksRepository) SetClosedThrough(ctx context.Context, userID uuid.UUID, date pgtype.Date) error {
+	return r.db.SetBooksClosedThrough(ctx, db.SetBooksClosedThroughParams{UserID: userID, ClosedThrough: date})
+}
+
+func (r *booksRepository) CreateEvent(ctx context.Context, arg db.CreateBookClosingEventParams) (*db.BookClosingEvent, error) {
+	return r.db.CreateBookClosingEvent(ctx, arg)
+}
+
+func (r *booksRepository) GetEvents(ctx context.Context, userID uuid.UUID) ([]*db.BookClosingEvent, error) {
+	return r.db.GetBookClosingEvents(ctx, userID)
+}
+
+// CheckOpen fails with ErrClosed when any of the dates is on or before the
+// closing date of the user; invalid dates are skipped. Run inside a
+// transaction, it also keeps the books from being closed until it ends.
+func (r *booksRepository) CheckOpen(ctx context.Context, userID uuid.UUID, dates ...pgtype.Date) error {
+	closed, err := r.db.GetBooksClosedThroughForShare(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !closed.Valid {
+		return nil
+	}
+
+	for _, date := range dates {
+		if date.Valid && !date.Time.After(closed.Time) {
+			return fmt.Errorf("%w through %s, reopen them to change transactions up to that date", ErrClosed, closed.Time.Format("2006-01-02"))
+		}
+	}
+
+	return nil
+}
+
+func (r *booksRepository) WithTx(q *db.Queries) Repository {
+	return &booksRepository{db: q}
+}
@@ -0,0 +1,138 @@
+package books
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Service interface {
+	Get(ctx context.Context, userID string) (*BooksRes, error)
+	Close(ctx context.Context, userID string, req *CloseReq) (*BooksRes, error)
+	Reopen(ctx context.Context, userID string, req *ReopenReq) (*BooksRes, error)
+	GetEvents(ctx context.Context, userID string) ([]EventRes, error)
+}
+
+type booksService struct {
+	repo Repository
+	uow  pgstore.UnitOfWork
+}
+
+func NewBooksService(repo Repository, uow pgstore.UnitOfWork) Service {
+	return &booksService{
+		repo: repo,
+		uow:  uow,
+	}
+}
+
+func (s *booksService) Get(ctx context.Context, userID string) (*BooksRes, error) {
+	closed, err := s.repo.GetClosedThrough(ctx, uuid.MustParse(userID))
+	if err != nil {
+		return nil, fmt.Errorf("service get books: %w", err)
+	}
+
+	return &BooksRes{ClosedThrough: dateString(closed)}, nil
+}
+
+// Close only moves the closing date forward; moving it back is a reopen,
+// which must give a reason.
+func (s *booksService) Close(ctx context.Context, userID string, req *CloseReq) (*BooksRes, error) {
+	date, _ := time.Parse("2006-01-02", req.ClosedThrough)
+	closed := pgtype.Date{Time: date, Valid: true}
+	reason := strings.TrimSpace(req.Reason)
+
+	err := s.change(ctx, uuid.MustParse(userID), db.BookClosingActionClose, closed, reason, func(previous pgtype.Date) error {
+		if previous.Valid && !date.After(previous.Time) {
+			return ErrNotAfter
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotAfter) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service close books: %w", err)
+	}
+
+	return &BooksRes{ClosedThrough: dateString(closed)}, nil
+}
+
+// Reopen moves the closing date back, or removes it when the request has
+// no date, so transactions after the new date can be changed again.
+func (s *booksService) Reopen(ctx context.Context, userID string, req *ReopenReq) (*BooksRes, error) {
+	var closed pgtype.Date
+	if req.ClosedThrough != nil {
+		date, _ := time.Parse("2006-01-02", *req.ClosedThrough)
+		closed = pgtype.Date{Time: date, Valid: true}
+	}
+
+	err := s.change(ctx, uuid.MustParse(userID), db.BookClosingActionReopen, closed, strings.TrimSpace(req.Reason), func(previous pgtype.Date) error {
+		if !previous.Valid {
+			return ErrNotClosed
+		}
+		if closed.Valid && !closed.Time.Before(previous.Time) {
+			return ErrNotBefore
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotClosed) || errors.Is(err, ErrNotBefore) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service reopen books: %w", err)
+	}
+
+	return &BooksRes{ClosedThrough: dateString(closed)}, nil
+}
+
+func (s *booksService) GetEvents(ctx context.Context, userID string) ([]EventRes, error) {
+	records, err := s.repo.GetEvents(ctx, uuid.MustParse(userID))
+	if err != nil {
+		return nil, fmt.Errorf("service get book events: %w", err)
+	}
+
+	res := make([]EventRes, len(records))
+	for i, record := range records {
+		res[i] = EventToResponse(record)
+	}
+
+	return res, nil
+}
+
+// change sets the closing date and records the event in one transaction.
+// The user row stays locked until it ends, so check sees the current date
+// and no change to a transaction is saved in between.
+func (s *booksService) change(ctx context.Context, userID uuid.UUID, action db.BookClosingAction, closed pgtype.Date, reason string, check func(previous pgtype.Date) error) error {
+	return s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+
+		previous, err := repo.GetClosedThroughForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := check(previous); err != nil {
+			return err
+		}
+
+		if err := repo.SetClosedThrough(ctx, userID, closed); err != nil {
+			return err
+		}
+
+		_, err = repo.CreateEvent(ctx, db.CreateBookClosingEventParams{
+			UserID:        userID,
+			Action:        action,
+			PreviousDate:  previous,
+			ClosedThrough: closed,
+			Reason:        pgtype.Text{String: reason, Valid: reason != ""},
+		})
+		return err
+	})
+}
@@ -0,0 +1,62 @@
+package budget
+
+import (
+	"context"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+)
+
+type CreateBudgetReq struct {
+	CategoryID string      `json:"category_id"`
+	Period     string      `json:"period"` // formato: YYYY-MM
+	Amount     money.Money `json:"amount"`
+	Rollover   bool        `json:"rollover"`
+}
+
+type BudgetRes struct {
+	ID         string      `json:"id"`
+	CategoryID string      `json:"category_id"`
+	Period     string      `json:"period"`
+	Amount     money.Money `json:"amount"`
+	Rollover   bool        `json:"rollover"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+type BudgetProgressRes struct {
+	BudgetRes
+	Carryover   money.Money `json:"carryover"`
+	Available   money.Money `json:"available"`
+	Spent       money.Money `json:"spent"`
+	Remaining   money.Money `json:"remaining"`
+	PercentUsed float64     `json:"percent_used"`
+}
+
+func (r *CreateBudgetReq) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.UUID(r.CategoryID), "category_id", "this field must be a valid UUID")
+	eval.CheckField(validator.Period(r.Period), "period", "this field must be a month in the format YYYY-MM")
+	eval.CheckField(r.Amount > 0, "amount", "this field must be greater than 0")
+
+	return eval
+}
+
+type UpdateBudgetReq struct {
+	Amount   *money.Money `json:"amount"`
+	Rollover *bool        `json:"rollover"`
+}
+
+func (r *UpdateBudgetReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(r.Amount != nil || r.Rollover != nil, "fields", "at least one field must be sent to update")
+
+	if r.Amount != nil {
+		eval.CheckField(*r.Amount > 0, "amount", "this field must be greater than 0")
+	}
+
+	return eval
+}
@@ -0,0 +1,147 @@
+package budget
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EduardoMark/my-finance-api/internal/exchangerate"
+	"github.com/EduardoMark/my-finance-api/internal/middlewares"
+	"github.com/EduardoMark/my-finance-api/pkg/httputils"
+	"github.com/EduardoMark/my-finance-api/pkg/token"
+	"github.com/go-chi/chi/v5"
+)
+
+type BudgetHandler struct {
+	svc   Service
+	token *token.TokenManager
+}
+
+func NewBudgetHandler(svc Service, token *token.TokenManager) BudgetHandler {
+	return BudgetHandler{
+		svc:   svc,
+		token: token,
+	}
+}
+
+func (h *BudgetHandler) RegisterBudgetRoutes(r chi.Router) {
+	r.Route("/budgets", func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(h.token))
+
+		r.Post("/", h.Create)
+		r.Get("/{period}", h.GetBudgetsProgress)
+		r.Put("/{id}", h.Update)
+		r.Delete("/{id}", h.Delete)
+	})
+}
+
+func (h *BudgetHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*CreateBudgetReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.svc.Create(ctx, userID, data); err != nil {
+		if errors.Is(err, ErrInvalidCategory) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"category_id": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrDuplicatedBudget) {
+			_ = httputils.EncodeJson(w, r, http.StatusConflict, map[string]string{"error": err.Error()})
+			return
+		}
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	httputils.Created(w)
+}
+
+func (h *BudgetHandler) GetBudgetsProgress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	period := chi.URLParam(r, "period")
+
+	userId, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userId == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	res, err := h.svc.GetBudgetsProgress(ctx, userId, period)
+	if err != nil {
+		if errors.Is(err, ErrInvalidPeriod) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"period": err.Error()})
+			return
+		}
+		if errors.Is(err, exchangerate.ErrMissingRate) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+			return
+		}
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *BudgetHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	userId, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userId == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*UpdateBudgetReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.svc.Update(ctx, userId, id, *data); err != nil {
+		if errors.Is(err, ErrBudgetNotFound) {
+			_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+func (h *BudgetHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	userId, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userId == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	if err := h.svc.Delete(ctx, userId, id); err != nil {
+		if errors.Is(err, ErrBudgetNotFound) {
+			_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	httputils.NoContent(w)
+}
@@ -0,0 +1,92 @@
+package budget
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EduardoMark/my-finance-api/internal/exchangerate"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Repository interface {
+	Create(ctx context.Context, arg db.CreateBudgetParams) error
+	GetBudget(ctx context.Context, id, userID uuid.UUID) (*db.Budget, error)
+	GetBudgetsProgress(ctx context.Context, userID uuid.UUID, period pgtype.Date) ([]*db.GetBudgetsProgressRow, error)
+	GetBudgetsHistory(ctx context.Context, userID uuid.UUID, before pgtype.Date) ([]*db.GetBudgetsHistoryRow, error)
+	Update(ctx context.Context, arg db.UpdateBudgetParams) error
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+}
+
+type budgetRepository struct {
+	db *db.Queries
+}
+
+func NewBudgetRepository(db *db.Queries) Repository {
+	return &budgetRepository{db: db}
+}
+
+var ErrBudgetNotFound = errors.New("budget not found")
+var ErrDuplicatedBudget = errors.New("budget already exist for this category and period")
+var ErrInvalidCategory = errors.New("category must be an expense category of the user")
+
+func (r *budgetRepository) Create(ctx context.Context, arg db.CreateBudgetParams) error {
+	var pgErr *pgconn.PgError
+
+	if err := r.db.CreateBudget(ctx, arg); err != nil {
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrDuplicatedBudget
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *budgetRepository) GetBudget(ctx context.Context, id, userID uuid.UUID) (*db.Budget, error) {
+	record, err := r.db.GetBudget(ctx, db.GetBudgetParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrBudgetNotFound
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+func (r *budgetRepository) GetBudgetsProgress(ctx context.Context, userID uuid.UUID, period pgtype.Date) ([]*db.GetBudgetsProgressRow, error) {
+	records, err := r.db.GetBudgetsProgress(ctx, db.GetBudgetsProgressParams{UserID: userID, Period: period})
+	if err != nil {
+		return nil, exchangerate.MissingRate(err)
+	}
+	return records, nil
+}
+
+func (r *budgetRepository) GetBudgetsHistory(ctx context.Context, userID uuid.UUID, before pgtype.Date) ([]*db.GetBudgetsHistoryRow, error) {
+	records, err := r.db.GetBudgetsHistory(ctx, db.GetBudgetsHistoryParams{UserID: userID, Period: before})
+	if err != nil {
+		return nil, exchangerate.MissingRate(err)
+	}
+	return records, nil
+}
+
+func (r *budgetRepository) Update(ctx context.Context, arg db.UpdateBudgetParams) error {
+	if err := r.db.UpdateBudget(ctx, arg); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *budgetRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	if _, err := r.GetBudget(ctx, id, userID); err != nil {
+		return err
+	}
+
+	if err := r.db.DeleteBudget(ctx, db.DeleteBudgetParams{ID: id, UserID: userID}); err != nil {
+		return err
+	}
+	return nil
+}
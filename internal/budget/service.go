@@ -0,0 +1,210 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/category"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Service interface {
+	Create(ctx context.Context, userID string, req *CreateBudgetReq) error
+	GetBudgetsProgress(ctx context.Context, userID, period string) ([]BudgetProgressRes, error)
+	Update(ctx context.Context, userID, id string, req UpdateBudgetReq) error
+	Delete(ctx context.Context, userID, id string) error
+}
+
+type budgetService struct {
+	repo       Repository
+	categories category.Repository
+}
+
+func NewBudgetService(repo Repository, categories category.Repository) Service {
+	return &budgetService{
+		repo:       repo,
+		categories: categories,
+	}
+}
+
+var ErrInvalidPeriod = errors.New("period must be in the format YYYY-MM")
+
+func (s *budgetService) Create(ctx context.Context, userID string, req *CreateBudgetReq) error {
+	userUUID := uuid.MustParse(userID)
+	categoryUUID := uuid.MustParse(req.CategoryID)
+
+	period, err := parsePeriod(req.Period)
+	if err != nil {
+		return err
+	}
+
+	record, err := s.categories.GetCategory(ctx, categoryUUID, userUUID)
+	if err != nil {
+		if errors.Is(err, category.ErrCategoryNotFound) {
+			return ErrInvalidCategory
+		}
+		return fmt.Errorf("service create: %w", err)
+	}
+
+	if record.Type != db.TransactionTypeExpense {
+		return ErrInvalidCategory
+	}
+
+	arg := db.CreateBudgetParams{
+		UserID:     userUUID,
+		CategoryID: categoryUUID,
+		Period:     pgtype.Date{Time: period, Valid: true},
+		Amount:     req.Amount,
+		Rollover:   req.Rollover,
+	}
+
+	if err := s.repo.Create(ctx, arg); err != nil {
+		if errors.Is(err, ErrDuplicatedBudget) {
+			return err
+		}
+		return fmt.Errorf("service create: %w", err)
+	}
+
+	return nil
+}
+
+// GetBudgetsProgress reports the budgets of a month. Budgets with rollover
+// receive what was left available in the same category's budget of the
+// previous month, so unspent money keeps accumulating while every month in
+// between has a rollover budget.
+func (s *budgetService) GetBudgetsProgress(ctx context.Context, userID, period string) ([]BudgetProgressRes, error) {
+	userUUID := uuid.MustParse(userID)
+
+	start, err := parsePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.repo.GetBudgetsProgress(ctx, userUUID, pgtype.Date{Time: start, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("service get budgets progress: %w", err)
+	}
+
+	history, err := s.repo.GetBudgetsHistory(ctx, userUUID, pgtype.Date{Time: start, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("service get budgets progress: %w", err)
+	}
+
+	// leftovers holds what each category had left in the month before
+	// start, built up month by month from the oldest budget.
+	leftovers := make(map[uuid.UUID]money.Money)
+	periods := make(map[uuid.UUID]time.Time)
+	for _, record := range history {
+		available := record.Amount
+		if record.Rollover && periods[record.CategoryID].Equal(record.Period.Time.AddDate(0, -1, 0)) {
+			available += leftovers[record.CategoryID]
+		}
+
+		leftovers[record.CategoryID] = max(available-money.FromCents(record.Spent), 0)
+		periods[record.CategoryID] = record.Period.Time
+	}
+
+	res := make([]BudgetProgressRes, len(records))
+	for i, record := range records {
+		item := BudgetProgressRes{
+			BudgetRes: BudgetRes{
+				ID:         record.ID.String(),
+				CategoryID: record.CategoryID.String(),
+				Period:     record.Period.Time.Format("2006-01"),
+				Amount:     record.Amount,
+				Rollover:   record.Rollover,
+				CreatedAt:  record.CreatedAt.Time,
+				UpdatedAt:  record.UpdatedAt.Time,
+			},
+			Spent: money.FromCents(record.Spent),
+		}
+
+		if record.Rollover && periods[record.CategoryID].Equal(start.AddDate(0, -1, 0)) {
+			item.Carryover = leftovers[record.CategoryID]
+		}
+
+		item.Available = item.Amount + item.Carryover
+		item.Remaining = item.Available - item.Spent
+		item.PercentUsed = percent(item.Spent, item.Available)
+
+		res[i] = item
+	}
+
+	return res, nil
+}
+
+func (s *budgetService) Update(ctx context.Context, userID, id string, req UpdateBudgetReq) error {
+	userUUID := uuid.MustParse(userID)
+
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrBudgetNotFound
+	}
+
+	record, err := s.repo.GetBudget(ctx, idUUID, userUUID)
+	if err != nil {
+		return err
+	}
+
+	updateParams := db.UpdateBudgetParams{
+		ID:       record.ID,
+		Amount:   record.Amount,
+		Rollover: record.Rollover,
+		UserID:   userUUID,
+	}
+
+	if req.Amount != nil {
+		updateParams.Amount = *req.Amount
+	}
+
+	if req.Rollover != nil {
+		updateParams.Rollover = *req.Rollover
+	}
+
+	if err := s.repo.Update(ctx, updateParams); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *budgetService) Delete(ctx context.Context, userID, id string) error {
+	userUUID := uuid.MustParse(userID)
+
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrBudgetNotFound
+	}
+
+	if err := s.repo.Delete(ctx, idUUID, userUUID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// parsePeriod returns the first day of a YYYY-MM month.
+func parsePeriod(period string) (time.Time, error) {
+	start, err := time.Parse("2006-01", period)
+	if err != nil {
+		return time.Time{}, ErrInvalidPeriod
+	}
+	return start, nil
+}
+
+func percent(part, total money.Money) float64 {
+	if total <= 0 {
+		if part > 0 {
+			return 100
+		}
+		return 0
+	}
+
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
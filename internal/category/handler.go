@@ -68,7 +68,7 @@ func (h *CategoryHandler) GetCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	record, err := h.svc.GetCategory(ctx, id)
+	record, err := h.svc.GetCategory(ctx, userId, id)
 	if err != nil {
 		if errors.Is(err, ErrCategoryNotFound) {
 			_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": ErrCategoryNotFound.Error()})
@@ -128,6 +128,12 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
 
+	userId, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userId == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
 	data, problems, err := httputils.DecodeValidJson[*UpdateCategoryReq](r)
 	if err != nil {
 		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
@@ -135,9 +141,9 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
-	if err := h.svc.Update(ctx, id, *data); err != nil {
+	if err := h.svc.Update(ctx, userId, id, *data); err != nil {
 		if errors.Is(err, ErrCategoryNotFound) {
-			_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
 			return
 		}
 
@@ -152,9 +158,20 @@ func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
 
-	if err := h.svc.Delete(ctx, id); err != nil {
+	userId, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userId == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	if err := h.svc.Delete(ctx, userId, id); err != nil {
 		if errors.Is(err, ErrCategoryNotFound) {
-			_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+
+		if errors.Is(err, ErrCategoryInUse) {
+			_ = httputils.EncodeJson(w, r, http.StatusConflict, map[string]string{"error": err.Error()})
 			return
 		}
 
@@ -7,14 +7,15 @@ import (
 
 	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type Repository interface {
 	Create(ctx context.Context, arg db.CreateCategoryParams) error
-	GetCategory(ctx context.Context, id uuid.UUID) (*db.Category, error)
+	GetCategory(ctx context.Context, id, userID uuid.UUID) (*db.Category, error)
 	GetAllCategoriesByUserId(ctx context.Context, userID uuid.UUID) ([]*db.Category, error)
 	Update(ctx context.Context, arg db.UpdateCategoryParams) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id, userID uuid.UUID) error
 }
 
 type categoryRepository struct {
@@ -27,6 +28,7 @@ func NewCategoryRepository(db *db.Queries) Repository {
 
 var ErrCategoryNotFound = errors.New("category not found")
 var ErrCategoriesNotFound = errors.New("categories not found")
+var ErrCategoryInUse = errors.New("category has transactions or splits, move them to another category before deleting it")
 
 func (r *categoryRepository) Create(ctx context.Context, arg db.CreateCategoryParams) error {
 	err := r.db.CreateCategory(ctx, arg)
@@ -38,8 +40,8 @@ func (r *categoryRepository) Create(ctx context.Context, arg db.CreateCategoryPa
 	return nil
 }
 
-func (r *categoryRepository) GetCategory(ctx context.Context, id uuid.UUID) (*db.Category, error) {
-	record, err := r.db.GetCategory(ctx, id)
+func (r *categoryRepository) GetCategory(ctx context.Context, id, userID uuid.UUID) (*db.Category, error) {
+	record, err := r.db.GetCategory(ctx, db.GetCategoryParams{ID: id, UserID: userID})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrCategoryNotFound
@@ -67,8 +69,8 @@ func (r *categoryRepository) Update(ctx context.Context, arg db.UpdateCategoryPa
 	return nil
 }
 
-func (r *categoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	_, err := r.db.GetCategory(ctx, id)
+func (r *categoryRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	_, err := r.db.GetCategory(ctx, db.GetCategoryParams{ID: id, UserID: userID})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return ErrCategoryNotFound
@@ -76,7 +78,12 @@ func (r *categoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
-	if err := r.db.DeleteCategory(ctx, id); err != nil {
+	var pgErr *pgconn.PgError
+
+	if err := r.db.DeleteCategory(ctx, db.DeleteCategoryParams{ID: id, UserID: userID}); err != nil {
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrCategoryInUse
+		}
 		return err
 	}
 	return nil
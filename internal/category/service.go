@@ -12,10 +12,10 @@ import (
 
 type Service interface {
 	Create(ctx context.Context, userID string, arg *CreateCategoryReq) error
-	GetCategory(ctx context.Context, id string) (*db.Category, error)
+	GetCategory(ctx context.Context, userID, id string) (*db.Category, error)
 	GetAllCategoriesByUserId(ctx context.Context, userId string) ([]*db.Category, error)
-	Update(ctx context.Context, id string, ags UpdateCategoryReq) error
-	Delete(ctx context.Context, id string) error
+	Update(ctx context.Context, userID, id string, ags UpdateCategoryReq) error
+	Delete(ctx context.Context, userID, id string) error
 }
 
 type categoryService struct {
@@ -42,10 +42,15 @@ func (s *categoryService) Create(ctx context.Context, userID string, req *Create
 	return nil
 }
 
-func (s *categoryService) GetCategory(ctx context.Context, id string) (*db.Category, error) {
-	idUUID := uuid.MustParse(id)
+func (s *categoryService) GetCategory(ctx context.Context, userID, id string) (*db.Category, error) {
+	userUUID := uuid.MustParse(userID)
 
-	record, err := s.repo.GetCategory(ctx, idUUID)
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrCategoryNotFound
+	}
+
+	record, err := s.repo.GetCategory(ctx, idUUID, userUUID)
 	if err != nil {
 		if errors.Is(err, ErrCategoryNotFound) {
 			return nil, err
@@ -70,21 +75,24 @@ func (s *categoryService) GetAllCategoriesByUserId(ctx context.Context, userId s
 	return records, nil
 }
 
-func (s *categoryService) Update(ctx context.Context, id string, ags UpdateCategoryReq) error {
+func (s *categoryService) Update(ctx context.Context, userID, id string, ags UpdateCategoryReq) error {
+	userUUID := uuid.MustParse(userID)
+
 	idUUID, err := uuid.Parse(id)
 	if err != nil {
-		return err
+		return ErrCategoryNotFound
 	}
 
-	record, err := s.repo.GetCategory(ctx, idUUID)
+	record, err := s.repo.GetCategory(ctx, idUUID, userUUID)
 	if err != nil {
 		return err
 	}
 
 	updateParams := db.UpdateCategoryParams{
-		ID:   record.ID,
-		Name: record.Name,
-		Type: record.Type,
+		ID:     record.ID,
+		Name:   record.Name,
+		Type:   record.Type,
+		UserID: userUUID,
 	}
 
 	if validator.NotBlank(ags.Name) {
@@ -102,10 +110,15 @@ func (s *categoryService) Update(ctx context.Context, id string, ags UpdateCateg
 	return nil
 }
 
-func (s *categoryService) Delete(ctx context.Context, id string) error {
-	idUUID := uuid.MustParse(id)
+func (s *categoryService) Delete(ctx context.Context, userID, id string) error {
+	userUUID := uuid.MustParse(userID)
+
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrCategoryNotFound
+	}
 
-	if err := s.repo.Delete(ctx, idUUID); err != nil {
+	if err := s.repo.Delete(ctx, idUUID, userUUID); err != nil {
 		return err
 	}
 
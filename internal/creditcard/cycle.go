@@ -0,0 +1,66 @@
+package creditcard
+
+import "time"
+
+// cycle is one statement of a card: the charges dated after the previous
+// closing date up to and including Closing.
+type cycle struct {
+	Start   time.Time // first day of the cycle
+	Closing time.Time
+	Due     time.Time
+}
+
+// dayIn returns the given day of the month, clamped to its last day so that
+// a card closing on the 31st closes on the 30th in April.
+func dayIn(year int, month time.Month, day int) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// cycleClosingOn builds the cycle that closes in the month of closing. The
+// due date falls in the same month when due_day comes after closing_day and
+// in the following month otherwise.
+func cycleClosingOn(closing time.Time, closingDay, dueDay int) cycle {
+	year, month := closing.Year(), closing.Month()
+	previous := dayIn(year, month-1, closingDay)
+
+	due := dayIn(year, month, dueDay)
+	if dueDay <= closingDay {
+		due = dayIn(year, month+1, dueDay)
+	}
+
+	return cycle{
+		Start:   previous.AddDate(0, 0, 1),
+		Closing: dayIn(year, month, closingDay),
+		Due:     due,
+	}
+}
+
+// openCycle returns the cycle that contains today.
+func openCycle(today time.Time, closingDay, dueDay int) cycle {
+	closing := dayIn(today.Year(), today.Month(), closingDay)
+	if today.After(closing) {
+		closing = dayIn(today.Year(), today.Month()+1, closingDay)
+	}
+	return cycleClosingOn(closing, closingDay, dueDay)
+}
+
+// lastCycles returns n cycles ending with the open one, newest first.
+func lastCycles(today time.Time, n, closingDay, dueDay int) []cycle {
+	open := openCycle(today, closingDay, dueDay)
+
+	cycles := make([]cycle, n)
+	for i := range cycles {
+		month := time.Date(open.Closing.Year(), open.Closing.Month()-time.Month(i), 1, 0, 0, 0, 0, time.UTC)
+		cycles[i] = cycleClosingOn(dayIn(month.Year(), month.Month(), closingDay), closingDay, dueDay)
+	}
+	return cycles
+}
+
+// isClosingDate reports whether date is the closing date of a cycle.
+func isClosingDate(date time.Time, closingDay int) bool {
+	return date.Equal(dayIn(date.Year(), date.Month(), closingDay))
+}
@@ -0,0 +1,115 @@
+package creditcard
+
+import (
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	d, _ := time.Parse("2006-01-02", s)
+	return d
+}
+
+func checkCycle(t *testing.T, got cycle, start, closing, due string) {
+	t.Helper()
+
+	if !got.Start.Equal(date(start)) || !got.Closing.Equal(date(closing)) || !got.Due.Equal(date(due)) {
+		t.Errorf("cycle = %s..%s due %s, want %s..%s due %s",
+			got.Start.Format("2006-01-02"), got.Closing.Format("2006-01-02"), got.Due.Format("2006-01-02"),
+			start, closing, due)
+	}
+}
+
+func TestCycleClosingOn(t *testing.T) {
+	tests := []struct {
+		name       string
+		closing    string
+		closingDay int
+		dueDay     int
+		start      string
+		wantClose  string
+		due        string
+	}{
+		{"January starts in December", "2025-01-10", 10, 20, "2024-12-11", "2025-01-10", "2025-01-20"},
+		{"due before closing falls next month", "2025-01-25", 25, 5, "2024-12-26", "2025-01-25", "2025-02-05"},
+		{"due on the closing day falls next month", "2025-01-10", 10, 10, "2024-12-11", "2025-01-10", "2025-02-10"},
+		{"December due in January", "2024-12-25", 25, 5, "2024-11-26", "2024-12-25", "2025-01-05"},
+		{"closing day 31 in February", "2025-02-28", 31, 10, "2025-02-01", "2025-02-28", "2025-03-10"},
+		{"closing day 31 in a leap February", "2024-02-29", 31, 10, "2024-02-01", "2024-02-29", "2024-03-10"},
+		{"closing day 31 after February", "2025-03-31", 31, 10, "2025-03-01", "2025-03-31", "2025-04-10"},
+		{"closing and due day 31 in April", "2025-04-30", 31, 31, "2025-04-01", "2025-04-30", "2025-05-31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cycleClosingOn(date(tt.closing), tt.closingDay, tt.dueDay)
+			checkCycle(t, got, tt.start, tt.wantClose, tt.due)
+		})
+	}
+}
+
+func TestOpenCycle(t *testing.T) {
+	tests := []struct {
+		name       string
+		today      string
+		closingDay int
+		dueDay     int
+		start      string
+		closing    string
+		due        string
+	}{
+		{"today is the closing day", "2025-03-10", 10, 20, "2025-02-11", "2025-03-10", "2025-03-20"},
+		{"day after closing opens the next cycle", "2025-03-11", 10, 20, "2025-03-11", "2025-04-10", "2025-04-20"},
+		{"December after closing wraps to January", "2024-12-15", 10, 20, "2024-12-11", "2025-01-10", "2025-01-20"},
+		{"short month closes on its last day", "2025-02-28", 31, 10, "2025-02-01", "2025-02-28", "2025-03-10"},
+		{"first day of a cycle", "2025-03-01", 31, 10, "2025-03-01", "2025-03-31", "2025-04-10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := openCycle(date(tt.today), tt.closingDay, tt.dueDay)
+			checkCycle(t, got, tt.start, tt.closing, tt.due)
+		})
+	}
+}
+
+func TestLastCycles(t *testing.T) {
+	tests := []struct {
+		name       string
+		today      string
+		closingDay int
+		dueDay     int
+		want       [][3]string // start, closing, due
+	}{
+		{
+			name:  "back across the year",
+			today: "2025-01-15", closingDay: 10, dueDay: 20,
+			want: [][3]string{
+				{"2025-01-11", "2025-02-10", "2025-02-20"},
+				{"2024-12-11", "2025-01-10", "2025-01-20"},
+				{"2024-11-11", "2024-12-10", "2024-12-20"},
+			},
+		},
+		{
+			name:  "closing day 31",
+			today: "2025-03-15", closingDay: 31, dueDay: 5,
+			want: [][3]string{
+				{"2025-03-01", "2025-03-31", "2025-04-05"},
+				{"2025-02-01", "2025-02-28", "2025-03-05"},
+				{"2025-01-01", "2025-01-31", "2025-02-05"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lastCycles(date(tt.today), len(tt.want), tt.closingDay, tt.dueDay)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d cycles, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				checkCycle(t, got[i], want[0], want[1], want[2])
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package creditcard
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+)
+
+const (
+	defaultCycles = 6
+	maxCycles     = 24
+
+	// minimumPaymentRate is the share of the statement total due as the
+	// minimum payment.
+	minimumPaymentRate = 0.15
+)
+
+const (
+	StatusOpen          = "open"
+	StatusClosed        = "closed"
+	StatusPartiallyPaid = "partially_paid"
+	StatusPaid          = "paid"
+	StatusOverdue       = "overdue"
+)
+
+type StatementFilters struct {
+	Cycles *string `json:"cycles,omitempty"` // inclui a fatura aberta
+}
+
+func (f *StatementFilters) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	if f.Cycles != nil {
+		cycles, err := strconv.Atoi(*f.Cycles)
+		eval.CheckField(err == nil && cycles > 0 && cycles <= maxCycles, "cycles", fmt.Sprintf("this field must be between 1 and %d", maxCycles))
+	}
+
+	return eval
+}
+
+func (f *StatementFilters) cycles() int {
+	if f.Cycles == nil {
+		return defaultCycles
+	}
+
+	cycles, _ := strconv.Atoi(*f.Cycles)
+	return cycles
+}
+
+type PayStatementRequest struct {
+	FromAccountID string       `json:"from_account_id" validate:"required"`
+	Amount        *money.Money `json:"amount,omitempty"` // padrão: o saldo restante da fatura
+	Date          string       `json:"date,omitempty"`   // formato: YYYY-MM-DD, padrão: hoje
+}
+
+func (r *PayStatementRequest) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.UUID(r.FromAccountID), "from_account_id", "this field must be a valid UUID")
+
+	if r.Amount != nil {
+		eval.CheckField(*r.Amount > 0, "amount", "this field must be greater than 0")
+	}
+
+	if r.Date != "" {
+		eval.CheckField(validator.Date(r.Date), "date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	return eval
+}
+
+// StatementRes is one statement cycle. Total is what the cycle's charges add
+// up to after refunds; Remaining is what is still owed on it.
+type StatementRes struct {
+	StartDate      string      `json:"start_date"`
+	ClosingDate    string      `json:"closing_date"`
+	DueDate        string      `json:"due_date"`
+	Status         string      `json:"status"`
+	Total          money.Money `json:"total"`
+	MinimumPayment money.Money `json:"minimum_payment"`
+	Paid           money.Money `json:"paid"`
+	Remaining      money.Money `json:"remaining"`
+	Charges        []ChargeRes `json:"charges"`
+}
+
+type ChargeRes struct {
+	ID          string      `json:"id"`
+	Date        string      `json:"date"`
+	Description string      `json:"description"`
+	Amount      money.Money `json:"amount"`
+	Type        string      `json:"type"`
+}
+
+func formatDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
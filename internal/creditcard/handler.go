@@ -0,0 +1,111 @@
+package creditcard
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EduardoMark/my-finance-api/internal/account"
+	"github.com/EduardoMark/my-finance-api/internal/books"
+	"github.com/EduardoMark/my-finance-api/internal/exchangerate"
+	"github.com/EduardoMark/my-finance-api/internal/middlewares"
+	"github.com/EduardoMark/my-finance-api/pkg/httputils"
+	"github.com/EduardoMark/my-finance-api/pkg/token"
+	"github.com/go-chi/chi/v5"
+)
+
+type CreditCardHandler struct {
+	svc   Service
+	token *token.TokenManager
+}
+
+func NewCreditCardHandler(svc Service, token *token.TokenManager) CreditCardHandler {
+	return CreditCardHandler{
+		svc:   svc,
+		token: token,
+	}
+}
+
+func (h *CreditCardHandler) RegisterCreditCardRoutes(r chi.Router) {
+	r.Route("/credit-cards", func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(h.token))
+
+		r.Get("/{id}/statements", h.GetStatements)
+		r.Post("/{id}/statements/{closing_date}/pay", h.PayStatement)
+	})
+}
+
+func (h *CreditCardHandler) GetStatements(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	filters := &StatementFilters{}
+	if cycles := r.URL.Query().Get("cycles"); cycles != "" {
+		filters.Cycles = &cycles
+	}
+
+	if problems := filters.Valid(ctx); len(problems) > 0 {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	res, err := h.svc.GetStatements(ctx, userID, chi.URLParam(r, "id"), filters)
+	if err != nil {
+		if errors.Is(err, ErrCardNotFound) {
+			httputils.Error(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *CreditCardHandler) PayStatement(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*PayStatementRequest](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	err = h.svc.PayStatement(ctx, userID, chi.URLParam(r, "id"), chi.URLParam(r, "closing_date"), *data)
+	if err != nil {
+		if errors.Is(err, ErrCardNotFound) {
+			httputils.Error(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, ErrInvalidStatement) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"closing_date": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrInvalidSourceAccount) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"from_account_id": err.Error()})
+			return
+		}
+		if errors.Is(err, exchangerate.ErrMissingRate) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"from_account_id": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrStatementPaid) || errors.Is(err, account.ErrNegativeBalance) || errors.Is(err, books.ErrClosed) {
+			httputils.Error(w, r, http.StatusConflict, err.Error())
+			return
+		}
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
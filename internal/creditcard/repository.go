@@ -0,0 +1,34 @@
+package creditcard
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+)
+
+type Repository interface {
+	GetCardTransactions(ctx context.Context, arg db.GetCardTransactionsParams) ([]*db.GetCardTransactionsRow, error)
+	GetStatementPayments(ctx context.Context, arg db.GetStatementPaymentsParams) ([]*db.GetStatementPaymentsRow, error)
+}
+
+type creditCardRepository struct {
+	db *db.Queries
+}
+
+func NewCreditCardRepository(db *db.Queries) Repository {
+	return &creditCardRepository{db: db}
+}
+
+var ErrCardNotFound = errors.New("credit card not found")
+var ErrInvalidStatement = errors.New("this date is not a closing date of the card")
+var ErrStatementPaid = errors.New("statement already paid")
+var ErrInvalidSourceAccount = errors.New("this field must be a checking account")
+
+func (r *creditCardRepository) GetCardTransactions(ctx context.Context, arg db.GetCardTransactionsParams) ([]*db.GetCardTransactionsRow, error) {
+	return r.db.GetCardTransactions(ctx, arg)
+}
+
+func (r *creditCardRepository) GetStatementPayments(ctx context.Context, arg db.GetStatementPaymentsParams) ([]*db.GetStatementPaymentsRow, error) {
+	return r.db.GetStatementPayments(ctx, arg)
+}
@@ -0,0 +1,245 @@
+package creditcard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/account"
+	"github.com/EduardoMark/my-finance-api/internal/exchangerate"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/transfer"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Service interface {
+	GetStatements(ctx context.Context, userID, cardID string, filters *StatementFilters) ([]StatementRes, error)
+	PayStatement(ctx context.Context, userID, cardID, closingDate string, dto PayStatementRequest) error
+}
+
+type creditCardService struct {
+	repo      Repository
+	accounts  account.Repository
+	rates     exchangerate.Repository
+	transfers transfer.Service
+}
+
+func NewCreditCardService(repo Repository, accounts account.Repository, rates exchangerate.Repository, transfers transfer.Service) Service {
+	return &creditCardService{
+		repo:      repo,
+		accounts:  accounts,
+		rates:     rates,
+		transfers: transfers,
+	}
+}
+
+// GetStatements returns the open statement followed by the previous ones,
+// newest first.
+func (s *creditCardService) GetStatements(ctx context.Context, userID, cardID string, filters *StatementFilters) ([]StatementRes, error) {
+	card, err := s.card(ctx, userID, cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	closingDay, dueDay := int(card.ClosingDay.Int16), int(card.DueDay.Int16)
+	today := today()
+	cycles := lastCycles(today, filters.cycles(), closingDay, dueDay)
+
+	return s.statements(ctx, card.ID, cycles, today)
+}
+
+// PayStatement transfers the payment from a checking account into the card
+// and ties it to the statement that closed on closingDate.
+func (s *creditCardService) PayStatement(ctx context.Context, userID, cardID, closingDate string, dto PayStatementRequest) error {
+	card, err := s.card(ctx, userID, cardID)
+	if err != nil {
+		return err
+	}
+
+	closing, err := time.Parse("2006-01-02", closingDate)
+	if err != nil || !isClosingDate(closing, int(card.ClosingDay.Int16)) {
+		return ErrInvalidStatement
+	}
+
+	source, err := s.accounts.GetAccount(ctx, uuid.MustParse(dto.FromAccountID), card.UserID)
+	if err != nil {
+		if errors.Is(err, account.ErrAccountNotFound) {
+			return ErrInvalidSourceAccount
+		}
+		return err
+	}
+
+	if source.Type != db.AccountTypeChecking {
+		return ErrInvalidSourceAccount
+	}
+
+	statements, err := s.statements(ctx, card.ID, []cycle{cycleClosingOn(closing, int(card.ClosingDay.Int16), int(card.DueDay.Int16))}, today())
+	if err != nil {
+		return err
+	}
+
+	amount := statements[0].Remaining
+	if dto.Amount != nil {
+		amount = *dto.Amount
+	}
+
+	if amount <= 0 {
+		return ErrStatementPaid
+	}
+
+	date := dto.Date
+	if date == "" {
+		date = formatDate(today())
+	}
+
+	req := transfer.TransferCreateRequest{
+		Description:   "Statement payment " + closingDate,
+		Amount:        amount,
+		Date:          date,
+		FromAccountID: source.ID.String(),
+		ToAccountID:   card.ID.String(),
+		StatementDate: closingDate,
+	}
+
+	// The amount is in the card currency, so the source account pays its
+	// converted value.
+	if source.Currency != card.Currency {
+		paid, _ := time.Parse("2006-01-02", date)
+		converted, err := s.rates.Convert(ctx, card.UserID, amount, card.Currency, source.Currency, pgtype.Date{Time: paid, Valid: true})
+		if err != nil {
+			return err
+		}
+		req.Amount = converted
+		req.ToAmount = &amount
+	}
+
+	return s.transfers.Create(ctx, userID, req)
+}
+
+// card returns the account when it is a credit card of the user.
+func (s *creditCardService) card(ctx context.Context, userID, cardID string) (*db.Account, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cardUUID, err := uuid.Parse(cardID)
+	if err != nil {
+		return nil, ErrCardNotFound
+	}
+
+	card, err := s.accounts.GetAccount(ctx, cardUUID, userUUID)
+	if err != nil {
+		if errors.Is(err, account.ErrAccountNotFound) {
+			return nil, ErrCardNotFound
+		}
+		return nil, err
+	}
+
+	if card.Type != db.AccountTypeCreditCard {
+		return nil, ErrCardNotFound
+	}
+
+	return card, nil
+}
+
+// statements loads the charges and payments of every cycle with two queries.
+// cycles must be ordered newest first.
+func (s *creditCardService) statements(ctx context.Context, cardID uuid.UUID, cycles []cycle, today time.Time) ([]StatementRes, error) {
+	first, last := cycles[len(cycles)-1], cycles[0]
+
+	charges, err := s.repo.GetCardTransactions(ctx, db.GetCardTransactionsParams{
+		AccountID: cardID,
+		StartDate: pgtype.Date{Time: first.Start, Valid: true},
+		EndDate:   pgtype.Date{Time: last.Closing, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service get statements: %w", err)
+	}
+
+	payments, err := s.repo.GetStatementPayments(ctx, db.GetStatementPaymentsParams{
+		AccountID: cardID,
+		StartDate: pgtype.Date{Time: first.Closing, Valid: true},
+		EndDate:   pgtype.Date{Time: last.Closing, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service get statements: %w", err)
+	}
+
+	paid := make(map[string]money.Money, len(payments))
+	for _, payment := range payments {
+		paid[formatDate(payment.StatementDate.Time)] = money.FromCents(payment.Paid)
+	}
+
+	response := make([]StatementRes, len(cycles))
+	for i, c := range cycles {
+		statement := StatementRes{
+			StartDate:   formatDate(c.Start),
+			ClosingDate: formatDate(c.Closing),
+			DueDate:     formatDate(c.Due),
+			Paid:        paid[formatDate(c.Closing)],
+			Charges:     []ChargeRes{},
+		}
+
+		for _, charge := range charges {
+			if charge.Date.Time.Before(c.Start) || charge.Date.Time.After(c.Closing) {
+				continue
+			}
+
+			// Refunds and other income on the card lower the statement.
+			if charge.Type == db.TransactionTypeIncome {
+				statement.Total -= charge.Amount
+			} else {
+				statement.Total += charge.Amount
+			}
+
+			statement.Charges = append(statement.Charges, ChargeRes{
+				ID:          charge.ID.String(),
+				Date:        formatDate(charge.Date.Time),
+				Description: charge.Description,
+				Amount:      charge.Amount,
+				Type:        string(charge.Type),
+			})
+		}
+
+		statement.Remaining = max(statement.Total-statement.Paid, 0)
+		statement.MinimumPayment = minimumPayment(statement.Total)
+		statement.Status = status(c, statement, today)
+
+		response[i] = statement
+	}
+
+	return response, nil
+}
+
+// minimumPayment rounds the minimum payment up to the next cent.
+func minimumPayment(total money.Money) money.Money {
+	if total <= 0 {
+		return 0
+	}
+	return money.FromCents(int64(math.Ceil(float64(total.Cents()) * minimumPaymentRate)))
+}
+
+func status(c cycle, statement StatementRes, today time.Time) string {
+	switch {
+	case !today.After(c.Closing):
+		return StatusOpen
+	case statement.Remaining == 0:
+		return StatusPaid
+	case today.After(c.Due):
+		return StatusOverdue
+	case statement.Paid > 0:
+		return StatusPartiallyPaid
+	default:
+		return StatusClosed
+	}
+}
+
+func today() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,101 @@
+package exchangerate
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const maxRows = 5000
+
+var ErrInvalidFile = errors.New("invalid file")
+
+var csvColumns = []string{"date", "from", "to", "rate"}
+
+// readCSV reads a file with the header date,from,to,rate, in any column
+// order and separated by commas or semicolons. Unlike statement imports a
+// single invalid line rejects the whole file, naming the line and field.
+func readCSV(ctx context.Context, r io.Reader) ([]RateReq, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	reader := csv.NewReader(strings.NewReader(string(content)))
+	reader.TrimLeadingSpace = true
+	if header, _, _ := strings.Cut(string(content), "\n"); strings.Count(header, ";") > strings.Count(header, ",") {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read header: %v", ErrInvalidFile, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range csvColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: column %q not found", ErrInvalidFile, name)
+		}
+	}
+
+	var rates []RateReq
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+
+		if len(rates) == maxRows {
+			return nil, fmt.Errorf("%w: more than %d lines", ErrInvalidFile, maxRows)
+		}
+
+		rate, err := strconv.ParseFloat(strings.ReplaceAll(record[columns["rate"]], ",", "."), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: rate: invalid number", ErrInvalidFile, line)
+		}
+
+		req := RateReq{
+			From: strings.ToUpper(strings.TrimSpace(record[columns["from"]])),
+			To:   strings.ToUpper(strings.TrimSpace(record[columns["to"]])),
+			Date: strings.TrimSpace(record[columns["date"]]),
+			Rate: rate,
+		}
+
+		if problems := req.Valid(ctx); len(problems) > 0 {
+			return nil, fmt.Errorf("%w: line %d: %s", ErrInvalidFile, line, firstProblem(problems))
+		}
+
+		rates = append(rates, req)
+	}
+
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("%w: no rates found", ErrInvalidFile)
+	}
+
+	return rates, nil
+}
+
+// firstProblem formats one field error, picked by name so the message does
+// not change between uploads of the same file.
+func firstProblem(problems map[string]any) string {
+	fields := make([]string, 0, len(problems))
+	for field := range problems {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	return fmt.Sprintf("%s: %v", fields[0], problems[fields[0]])
+}
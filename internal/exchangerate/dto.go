@@ -0,0 +1,105 @@
+package exchangerate
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxRate keeps rates within the NUMERIC(20, 10) column.
+const maxRate = 1e10
+
+const currencyMessage = "this field must be an upper case ISO 4217 code such as BRL"
+
+// RateReq sets the rate of one unit of From in To on Date. The rate holds
+// until the next date with a rate for the pair, and also converts To back
+// into From when that pair has no rate of its own.
+type RateReq struct {
+	From string  `json:"from"`
+	To   string  `json:"to"`
+	Date string  `json:"date"` // formato: YYYY-MM-DD
+	Rate float64 `json:"rate"`
+}
+
+func (r *RateReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.Currency(r.From), "from", currencyMessage)
+	eval.CheckField(validator.Currency(r.To), "to", currencyMessage)
+	eval.CheckField(r.From != r.To, "to", "this field must differ from from")
+	eval.CheckField(validator.Date(r.Date), "date", "this field must be a date in the format YYYY-MM-DD")
+	eval.CheckField(r.Rate > 0 && r.Rate < maxRate, "rate", "this field must be greater than 0 and less than 10000000000")
+
+	return eval
+}
+
+// rateNumeric converts a rate into a NUMERIC through its shortest decimal
+// form, so 5.1 is stored as 5.1 and not as its binary approximation.
+func rateNumeric(rate float64) pgtype.Numeric {
+	var n pgtype.Numeric
+	_ = n.Scan(strconv.FormatFloat(rate, 'f', -1, 64))
+	return n
+}
+
+type RateFilters struct {
+	From      *string `json:"from,omitempty"`
+	To        *string `json:"to,omitempty"`
+	StartDate *string `json:"start_date,omitempty"`
+	EndDate   *string `json:"end_date,omitempty"`
+}
+
+func (f *RateFilters) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	if f.From != nil {
+		eval.CheckField(validator.Currency(*f.From), "from", currencyMessage)
+	}
+
+	if f.To != nil {
+		eval.CheckField(validator.Currency(*f.To), "to", currencyMessage)
+	}
+
+	if f.StartDate != nil {
+		eval.CheckField(validator.Date(*f.StartDate), "start_date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	if f.EndDate != nil {
+		eval.CheckField(validator.Date(*f.EndDate), "end_date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	return eval
+}
+
+type RateRes struct {
+	ID        string    `json:"id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Date      string    `json:"date"`
+	Rate      float64   `json:"rate"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func RateToResponse(record *db.ExchangeRate) RateRes {
+	rate, _ := record.Rate.Float64Value()
+
+	return RateRes{
+		ID:        record.ID.String(),
+		From:      record.FromCurrency,
+		To:        record.ToCurrency,
+		Date:      record.Date.Time.Format("2006-01-02"),
+		Rate:      rate.Float64,
+		CreatedAt: record.CreatedAt.Time,
+		UpdatedAt: record.UpdatedAt.Time,
+	}
+}
+
+// ImportRes is the result of a CSV upload. The file is saved as a whole, so
+// Saved is the number of lines in it.
+type ImportRes struct {
+	Saved int `json:"saved"`
+}
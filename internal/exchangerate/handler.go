@@ -0,0 +1,160 @@
+package exchangerate
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/EduardoMark/my-finance-api/internal/middlewares"
+	"github.com/EduardoMark/my-finance-api/pkg/httputils"
+	"github.com/EduardoMark/my-finance-api/pkg/token"
+	"github.com/go-chi/chi/v5"
+)
+
+const maxUploadSize = 5 << 20
+
+type ExchangeRateHandler struct {
+	svc   Service
+	token *token.TokenManager
+}
+
+func NewExchangeRateHandler(svc Service, token *token.TokenManager) ExchangeRateHandler {
+	return ExchangeRateHandler{
+		svc:   svc,
+		token: token,
+	}
+}
+
+func (h *ExchangeRateHandler) RegisterExchangeRateRoutes(r chi.Router) {
+	r.Route("/exchange-rates", func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(h.token))
+
+		r.Post("/", h.Upsert)
+		r.Get("/", h.List)
+		r.Post("/import", h.Import)
+		r.Delete("/{id}", h.Delete)
+	})
+}
+
+// Upsert creates the rate of a pair on a date or replaces the existing one.
+func (h *ExchangeRateHandler) Upsert(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*RateReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	res, err := h.svc.Upsert(ctx, userID, data)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *ExchangeRateHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	query := r.URL.Query()
+	filters := &RateFilters{
+		From:      queryParam(query, "from"),
+		To:        queryParam(query, "to"),
+		StartDate: queryParam(query, "start_date"),
+		EndDate:   queryParam(query, "end_date"),
+	}
+
+	if problems := filters.Valid(ctx); len(problems) > 0 {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	res, err := h.svc.List(ctx, userID, filters)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *ExchangeRateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	if err := h.svc.Delete(ctx, userID, chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, ErrRateNotFound) {
+			_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+// Import expects a multipart form with the CSV in "file".
+func (h *ExchangeRateHandler) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusBadRequest, map[string]string{"error": "invalid multipart form or file larger than 5MB"})
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"file": "a CSV file must be sent in this field"})
+		return
+	}
+	defer file.Close()
+
+	res, err := h.svc.Import(ctx, userID, file)
+	if err != nil {
+		if errors.Is(err, ErrInvalidFile) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"file": err.Error()})
+			return
+		}
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusCreated, res)
+}
+
+// queryParam returns nil for absent or empty query parameters.
+func queryParam(query url.Values, key string) *string {
+	value := query.Get(key)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
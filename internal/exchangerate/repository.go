@@ -0,0 +1,99 @@
+package exchangerate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Repository interface {
+	Upsert(ctx context.Context, arg db.UpsertExchangeRateParams) (*db.ExchangeRate, error)
+	List(ctx context.Context, arg db.ListExchangeRatesParams) ([]*db.ExchangeRate, error)
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+	Convert(ctx context.Context, userID uuid.UUID, amount money.Money, from, to string, date pgtype.Date) (money.Money, error)
+	WithTx(q *db.Queries) Repository
+}
+
+type exchangeRateRepository struct {
+	db *db.Queries
+}
+
+func NewExchangeRateRepository(db *db.Queries) Repository {
+	return &exchangeRateRepository{db: db}
+}
+
+var ErrRateNotFound = errors.New("exchange rate not found")
+var ErrMissingRate = errors.New("missing exchange rate")
+
+func (r *exchangeRateRepository) Upsert(ctx context.Context, arg db.UpsertExchangeRateParams) (*db.ExchangeRate, error) {
+	record, err := r.db.UpsertExchangeRate(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("repository upsert exchange rate: %w", err)
+	}
+
+	return record, nil
+}
+
+func (r *exchangeRateRepository) List(ctx context.Context, arg db.ListExchangeRatesParams) ([]*db.ExchangeRate, error) {
+	records, err := r.db.ListExchangeRates(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("repository list exchange rates: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *exchangeRateRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	rows, err := r.db.DeleteExchangeRate(ctx, db.DeleteExchangeRateParams{ID: id, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("repository delete exchange rate: %w", err)
+	}
+
+	if rows == 0 {
+		return ErrRateNotFound
+	}
+
+	return nil
+}
+
+// Convert converts amount at the latest rate of the pair on or before date.
+func (r *exchangeRateRepository) Convert(ctx context.Context, userID uuid.UUID, amount money.Money, from, to string, date pgtype.Date) (money.Money, error) {
+	converted, err := r.db.ConvertAmount(ctx, db.ConvertAmountParams{
+		Amount:       amount.Cents(),
+		UserID:       userID,
+		FromCurrency: from,
+		ToCurrency:   to,
+		Date:         date,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%w: from %s to %s on or before %s", ErrMissingRate, from, to, date.Time.Format("2006-01-02"))
+		}
+		return 0, fmt.Errorf("repository convert amount: %w", err)
+	}
+
+	return money.FromCents(converted), nil
+}
+
+func (r *exchangeRateRepository) WithTx(q *db.Queries) Repository {
+	return &exchangeRateRepository{db: q}
+}
+
+// MissingRate turns the no_data_found error raised by to_base_currency into
+// ErrMissingRate, keeping the pair and date from its message. Other errors
+// are returned unchanged.
+func MissingRate(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "P0002" {
+		return fmt.Errorf("%w: %s", ErrMissingRate, pgErr.Message)
+	}
+
+	return err
+}
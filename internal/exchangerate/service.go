@@ -0,0 +1,127 @@
+package exchangerate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Service interface {
+	Upsert(ctx context.Context, userID string, dto *RateReq) (*RateRes, error)
+	List(ctx context.Context, userID string, filters *RateFilters) ([]RateRes, error)
+	Delete(ctx context.Context, userID, id string) error
+	Import(ctx context.Context, userID string, file io.Reader) (*ImportRes, error)
+}
+
+type exchangeRateService struct {
+	repo Repository
+	uow  pgstore.UnitOfWork
+}
+
+func NewExchangeRateService(repo Repository, uow pgstore.UnitOfWork) Service {
+	return &exchangeRateService{
+		repo: repo,
+		uow:  uow,
+	}
+}
+
+func (s *exchangeRateService) Upsert(ctx context.Context, userID string, dto *RateReq) (*RateRes, error) {
+	record, err := s.repo.Upsert(ctx, upsertParams(uuid.MustParse(userID), dto))
+	if err != nil {
+		return nil, fmt.Errorf("service upsert exchange rate: %w", err)
+	}
+
+	res := RateToResponse(record)
+	return &res, nil
+}
+
+func (s *exchangeRateService) List(ctx context.Context, userID string, filters *RateFilters) ([]RateRes, error) {
+	params := db.ListExchangeRatesParams{UserID: uuid.MustParse(userID)}
+
+	if filters.From != nil {
+		params.FromCurrency = pgtype.Text{String: *filters.From, Valid: true}
+	}
+
+	if filters.To != nil {
+		params.ToCurrency = pgtype.Text{String: *filters.To, Valid: true}
+	}
+
+	if filters.StartDate != nil {
+		params.StartDate = dateParam(*filters.StartDate)
+	}
+
+	if filters.EndDate != nil {
+		params.EndDate = dateParam(*filters.EndDate)
+	}
+
+	records, err := s.repo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("service list exchange rates: %w", err)
+	}
+
+	res := make([]RateRes, len(records))
+	for i, record := range records {
+		res[i] = RateToResponse(record)
+	}
+
+	return res, nil
+}
+
+func (s *exchangeRateService) Delete(ctx context.Context, userID, id string) error {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrRateNotFound
+	}
+
+	return s.repo.Delete(ctx, idUUID, uuid.MustParse(userID))
+}
+
+// Import saves every rate of a CSV file in one transaction, replacing the
+// rates already set for the same pair and date.
+func (s *exchangeRateService) Import(ctx context.Context, userID string, file io.Reader) (*ImportRes, error) {
+	rates, err := readCSV(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	userUUID := uuid.MustParse(userID)
+
+	err = s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+
+		for i := range rates {
+			if _, err := repo.Upsert(ctx, upsertParams(userUUID, &rates[i])); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service import exchange rates: %w", err)
+	}
+
+	return &ImportRes{Saved: len(rates)}, nil
+}
+
+// upsertParams expects a request that already passed Valid.
+func upsertParams(userID uuid.UUID, dto *RateReq) db.UpsertExchangeRateParams {
+	return db.UpsertExchangeRateParams{
+		UserID:       userID,
+		FromCurrency: dto.From,
+		ToCurrency:   dto.To,
+		Date:         dateParam(dto.Date),
+		Rate:         rateNumeric(dto.Rate),
+	}
+}
+
+func dateParam(value string) pgtype.Date {
+	date, _ := time.Parse("2006-01-02", value)
+	return pgtype.Date{Time: date, Valid: true}
+}
@@ -0,0 +1,146 @@
+package goal
+
+import (
+	"context"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+)
+
+type CreateGoalReq struct {
+	Name         string      `json:"name"`
+	TargetAmount money.Money `json:"target_amount"`
+	TargetDate   string      `json:"target_date,omitempty"` // formato: YYYY-MM-DD
+	AccountID    string      `json:"account_id,omitempty"`
+}
+
+func (r *CreateGoalReq) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(r.Name), "name", "this field cannot be empty")
+	eval.CheckField(r.TargetAmount > 0, "target_amount", "this field must be greater than 0")
+
+	if r.TargetDate != "" {
+		eval.CheckField(validator.Date(r.TargetDate), "target_date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	if r.AccountID != "" {
+		eval.CheckField(validator.UUID(r.AccountID), "account_id", "this field must be a valid UUID")
+	}
+
+	return eval
+}
+
+// UpdateGoalReq changes only the fields sent. An empty target_date or
+// account_id removes it from the goal.
+type UpdateGoalReq struct {
+	Name         *string      `json:"name"`
+	TargetAmount *money.Money `json:"target_amount"`
+	TargetDate   *string      `json:"target_date"`
+	AccountID    *string      `json:"account_id"`
+}
+
+func (r *UpdateGoalReq) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	hasAtLeastOneField := r.Name != nil || r.TargetAmount != nil || r.TargetDate != nil || r.AccountID != nil
+	eval.CheckField(hasAtLeastOneField, "fields", "at least one field must be sent to update")
+
+	if r.Name != nil {
+		eval.CheckField(validator.NotBlank(*r.Name), "name", "this field cannot be empty")
+	}
+
+	if r.TargetAmount != nil {
+		eval.CheckField(*r.TargetAmount > 0, "target_amount", "this field must be greater than 0")
+	}
+
+	if r.TargetDate != nil && *r.TargetDate != "" {
+		eval.CheckField(validator.Date(*r.TargetDate), "target_date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	if r.AccountID != nil && *r.AccountID != "" {
+		eval.CheckField(validator.UUID(*r.AccountID), "account_id", "this field must be a valid UUID")
+	}
+
+	return eval
+}
+
+// ContributionReq links an existing transaction or transfer to the goal, or
+// transfers amount from from_account_id into the goal's account. Exactly one
+// of transaction_id, transfer_id and from_account_id must be sent.
+type ContributionReq struct {
+	TransactionID string       `json:"transaction_id,omitempty"`
+	TransferID    string       `json:"transfer_id,omitempty"`
+	FromAccountID string       `json:"from_account_id,omitempty"`
+	Amount        *money.Money `json:"amount,omitempty"`
+	Date          string       `json:"date,omitempty"` // formato: YYYY-MM-DD, padrão: hoje
+}
+
+func (r *ContributionReq) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	sent := 0
+	for _, id := range []string{r.TransactionID, r.TransferID, r.FromAccountID} {
+		if id != "" {
+			sent++
+		}
+	}
+	eval.CheckField(sent == 1, "fields", "send exactly one of transaction_id, transfer_id or from_account_id")
+
+	if r.TransactionID != "" {
+		eval.CheckField(validator.UUID(r.TransactionID), "transaction_id", "this field must be a valid UUID")
+	}
+
+	if r.TransferID != "" {
+		eval.CheckField(validator.UUID(r.TransferID), "transfer_id", "this field must be a valid UUID")
+	}
+
+	if r.FromAccountID != "" {
+		eval.CheckField(validator.UUID(r.FromAccountID), "from_account_id", "this field must be a valid UUID")
+		eval.CheckField(r.Amount != nil && *r.Amount > 0, "amount", "this field must be greater than 0")
+	} else {
+		eval.CheckField(r.Amount == nil, "amount", "this field is only used with from_account_id")
+	}
+
+	if r.Date != "" {
+		eval.CheckField(r.FromAccountID != "", "date", "this field is only used with from_account_id")
+		eval.CheckField(validator.Date(r.Date), "date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	return eval
+}
+
+// GoalRes reports the progress of a goal. MonthlyAverage is what was saved
+// per month since the first contribution; ProjectedDate extends that pace
+// until the target amount is reached. MonthlyNeeded is what still has to be
+// saved each month to reach it by the target date.
+type GoalRes struct {
+	ID             string       `json:"id"`
+	Name           string       `json:"name"`
+	TargetAmount   money.Money  `json:"target_amount"`
+	TargetDate     string       `json:"target_date,omitempty"`
+	AccountID      string       `json:"account_id,omitempty"`
+	Saved          money.Money  `json:"saved"`
+	Remaining      money.Money  `json:"remaining"`
+	Percent        float64      `json:"percent"`
+	Completed      bool         `json:"completed"`
+	Contributions  int64        `json:"contributions"`
+	MonthlyAverage money.Money  `json:"monthly_average"`
+	ProjectedDate  *string      `json:"projected_date"`
+	MonthlyNeeded  *money.Money `json:"monthly_needed,omitempty"`
+	OnTrack        *bool        `json:"on_track,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// ContributionRes has a negative amount for withdrawals.
+type ContributionRes struct {
+	TransactionID string      `json:"transaction_id"`
+	Date          string      `json:"date"`
+	Description   string      `json:"description"`
+	Amount        money.Money `json:"amount"`
+	Type          string      `json:"type"`
+	AccountID     string      `json:"account_id"`
+	TransferID    string      `json:"transfer_id,omitempty"`
+}
@@ -0,0 +1,254 @@
+package goal
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EduardoMark/my-finance-api/internal/account"
+	"github.com/EduardoMark/my-finance-api/internal/books"
+	"github.com/EduardoMark/my-finance-api/internal/exchangerate"
+	"github.com/EduardoMark/my-finance-api/internal/middlewares"
+	"github.com/EduardoMark/my-finance-api/internal/transfer"
+	"github.com/EduardoMark/my-finance-api/pkg/httputils"
+	"github.com/EduardoMark/my-finance-api/pkg/token"
+	"github.com/go-chi/chi/v5"
+)
+
+type GoalHandler struct {
+	svc   Service
+	token *token.TokenManager
+}
+
+func NewGoalHandler(svc Service, token *token.TokenManager) GoalHandler {
+	return GoalHandler{
+		svc:   svc,
+		token: token,
+	}
+}
+
+func (h *GoalHandler) RegisterGoalRoutes(r chi.Router) {
+	r.Route("/goals", func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(h.token))
+
+		r.Post("/", h.Create)
+		r.Get("/", h.GetGoals)
+		r.Get("/{id}", h.GetGoal)
+		r.Put("/{id}", h.Update)
+		r.Delete("/{id}", h.Delete)
+		r.Get("/{id}/contributions", h.GetContributions)
+		r.Post("/{id}/contributions", h.AddContribution)
+		r.Delete("/{id}/contributions/{transaction_id}", h.RemoveContribution)
+	})
+}
+
+func (h *GoalHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*CreateGoalReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.svc.Create(ctx, userID, data); err != nil {
+		if errors.Is(err, ErrInvalidAccount) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"account_id": err.Error()})
+			return
+		}
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	httputils.Created(w)
+}
+
+func (h *GoalHandler) GetGoals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	res, err := h.svc.GetGoals(ctx, userID)
+	if err != nil {
+		if errors.Is(err, exchangerate.ErrMissingRate) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+			return
+		}
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *GoalHandler) GetGoal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	res, err := h.svc.GetGoal(ctx, userID, chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, ErrGoalNotFound) {
+			_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, exchangerate.ErrMissingRate) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+			return
+		}
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *GoalHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*UpdateGoalReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.svc.Update(ctx, userID, chi.URLParam(r, "id"), *data); err != nil {
+		if errors.Is(err, ErrGoalNotFound) {
+			_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrInvalidAccount) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"account_id": err.Error()})
+			return
+		}
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+func (h *GoalHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	if err := h.svc.Delete(ctx, userID, chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, ErrGoalNotFound) {
+			_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+func (h *GoalHandler) GetContributions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	res, err := h.svc.GetContributions(ctx, userID, chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, ErrGoalNotFound) {
+			_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *GoalHandler) AddContribution(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*ContributionReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.svc.AddContribution(ctx, userID, chi.URLParam(r, "id"), *data); err != nil {
+		switch {
+		case errors.Is(err, ErrGoalNotFound):
+			_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
+		case errors.Is(err, ErrTransactionNotFound):
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"transaction_id": err.Error()})
+		case errors.Is(err, ErrTransferNotFound):
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"transfer_id": err.Error()})
+		case errors.Is(err, ErrNoGoalAccount), errors.Is(err, transfer.ErrInvalidAccount), errors.Is(err, transfer.ErrSameAccount):
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"from_account_id": err.Error()})
+		case errors.Is(err, exchangerate.ErrMissingRate):
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"amount": err.Error()})
+		case errors.Is(err, ErrAlreadyContributed), errors.Is(err, account.ErrNegativeBalance), errors.Is(err, books.ErrClosed):
+			_ = httputils.EncodeJson(w, r, http.StatusConflict, map[string]string{"error": err.Error()})
+		default:
+			_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		return
+	}
+
+	httputils.Created(w)
+}
+
+func (h *GoalHandler) RemoveContribution(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	err := h.svc.RemoveContribution(ctx, userID, chi.URLParam(r, "id"), chi.URLParam(r, "transaction_id"))
+	if err != nil {
+		if errors.Is(err, ErrGoalNotFound) || errors.Is(err, ErrContributionNotFound) {
+			_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	httputils.NoContent(w)
+}
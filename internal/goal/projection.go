@@ -0,0 +1,61 @@
+package goal
+
+import (
+	"math"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+)
+
+// daysPerMonth is the average length of a month.
+const daysPerMonth = 365.25 / 12
+
+// monthlyAverage spreads what was saved over the months since the first
+// contribution, counting at least one month so that a single recent
+// contribution is not extrapolated.
+func monthlyAverage(saved money.Money, first, today time.Time) money.Money {
+	if saved <= 0 {
+		return 0
+	}
+
+	months := math.Max(today.Sub(first).Hours()/24/daysPerMonth, 1)
+	return money.FromCents(int64(math.Round(float64(saved.Cents()) / months)))
+}
+
+// projectedDate is when remaining is reached at the given monthly pace.
+func projectedDate(remaining, perMonth money.Money, today time.Time) time.Time {
+	days := math.Ceil(float64(remaining.Cents()) / float64(perMonth.Cents()) * daysPerMonth)
+	return today.AddDate(0, 0, int(days))
+}
+
+// monthsUntil counts the monthly contributions that still fit before date,
+// at least one.
+func monthsUntil(today, date time.Time) int {
+	months := (date.Year()-today.Year())*12 + int(date.Month()-today.Month())
+	if date.Day() < today.Day() {
+		months--
+	}
+	return max(months, 1)
+}
+
+// monthlyNeeded rounds up, so saving it every month reaches the target.
+func monthlyNeeded(remaining money.Money, today, targetDate time.Time) money.Money {
+	if targetDate.Before(today) {
+		return remaining
+	}
+
+	months := int64(monthsUntil(today, targetDate))
+	return money.FromCents((remaining.Cents() + months - 1) / months)
+}
+
+func percent(part, total money.Money) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
+
+func today() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,128 @@
+package goal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EduardoMark/my-finance-api/internal/exchangerate"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Repository interface {
+	Create(ctx context.Context, arg db.CreateGoalParams) error
+	GetGoal(ctx context.Context, id, userID uuid.UUID) (*db.Goal, error)
+	GetGoalsProgress(ctx context.Context, userID uuid.UUID, goalID pgtype.UUID) ([]*db.GetGoalsProgressRow, error)
+	Update(ctx context.Context, arg db.UpdateGoalParams) error
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+	CreateContribution(ctx context.Context, arg db.CreateGoalContributionParams) error
+	CreateTransferContribution(ctx context.Context, arg db.CreateGoalTransferContributionParams) error
+	GetContributions(ctx context.Context, goalID uuid.UUID) ([]*db.GetGoalContributionsRow, error)
+	DeleteContribution(ctx context.Context, goalID, transactionID uuid.UUID) error
+}
+
+type goalRepository struct {
+	db *db.Queries
+}
+
+func NewGoalRepository(db *db.Queries) Repository {
+	return &goalRepository{db: db}
+}
+
+var ErrGoalNotFound = errors.New("goal not found")
+var ErrInvalidAccount = errors.New("account not found")
+var ErrTransactionNotFound = errors.New("transaction not found")
+var ErrTransferNotFound = errors.New("transfer not found")
+var ErrContributionNotFound = errors.New("contribution not found")
+var ErrAlreadyContributed = errors.New("transaction already contributes to a goal")
+var ErrNoGoalAccount = errors.New("the goal has no account to transfer to")
+
+func (r *goalRepository) Create(ctx context.Context, arg db.CreateGoalParams) error {
+	return r.db.CreateGoal(ctx, arg)
+}
+
+func (r *goalRepository) GetGoal(ctx context.Context, id, userID uuid.UUID) (*db.Goal, error) {
+	record, err := r.db.GetGoal(ctx, db.GetGoalParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (r *goalRepository) GetGoalsProgress(ctx context.Context, userID uuid.UUID, goalID pgtype.UUID) ([]*db.GetGoalsProgressRow, error) {
+	rows, err := r.db.GetGoalsProgress(ctx, db.GetGoalsProgressParams{UserID: userID, GoalID: goalID})
+	return rows, exchangerate.MissingRate(err)
+}
+
+func (r *goalRepository) Update(ctx context.Context, arg db.UpdateGoalParams) error {
+	return r.db.UpdateGoal(ctx, arg)
+}
+
+func (r *goalRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	if _, err := r.GetGoal(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return r.db.DeleteGoal(ctx, db.DeleteGoalParams{ID: id, UserID: userID})
+}
+
+func (r *goalRepository) CreateContribution(ctx context.Context, arg db.CreateGoalContributionParams) error {
+	rows, err := r.db.CreateGoalContribution(ctx, arg)
+	if err != nil {
+		return contributionError(err)
+	}
+
+	if rows == 0 {
+		return ErrTransactionNotFound
+	}
+
+	return nil
+}
+
+func (r *goalRepository) CreateTransferContribution(ctx context.Context, arg db.CreateGoalTransferContributionParams) error {
+	rows, err := r.db.CreateGoalTransferContribution(ctx, arg)
+	if err != nil {
+		return contributionError(err)
+	}
+
+	if rows == 0 {
+		return ErrTransferNotFound
+	}
+
+	return nil
+}
+
+func (r *goalRepository) GetContributions(ctx context.Context, goalID uuid.UUID) ([]*db.GetGoalContributionsRow, error) {
+	return r.db.GetGoalContributions(ctx, goalID)
+}
+
+func (r *goalRepository) DeleteContribution(ctx context.Context, goalID, transactionID uuid.UUID) error {
+	rows, err := r.db.DeleteGoalContribution(ctx, db.DeleteGoalContributionParams{
+		GoalID:        goalID,
+		TransactionID: transactionID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrContributionNotFound
+	}
+
+	return nil
+}
+
+func contributionError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrAlreadyContributed
+	}
+	return err
+}
@@ -0,0 +1,347 @@
+package goal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/account"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/transfer"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Service interface {
+	Create(ctx context.Context, userID string, req *CreateGoalReq) error
+	GetGoals(ctx context.Context, userID string) ([]GoalRes, error)
+	GetGoal(ctx context.Context, userID, id string) (*GoalRes, error)
+	Update(ctx context.Context, userID, id string, req UpdateGoalReq) error
+	Delete(ctx context.Context, userID, id string) error
+	GetContributions(ctx context.Context, userID, id string) ([]ContributionRes, error)
+	AddContribution(ctx context.Context, userID, id string, req ContributionReq) error
+	RemoveContribution(ctx context.Context, userID, id, transactionID string) error
+}
+
+type goalService struct {
+	repo      Repository
+	accounts  account.Repository
+	transfers transfer.Service
+}
+
+func NewGoalService(repo Repository, accounts account.Repository, transfers transfer.Service) Service {
+	return &goalService{
+		repo:      repo,
+		accounts:  accounts,
+		transfers: transfers,
+	}
+}
+
+func (s *goalService) Create(ctx context.Context, userID string, req *CreateGoalReq) error {
+	userUUID := uuid.MustParse(userID)
+
+	arg := db.CreateGoalParams{
+		UserID:       userUUID,
+		Name:         req.Name,
+		TargetAmount: req.TargetAmount,
+	}
+
+	var err error
+	if arg.TargetDate, err = parseDate(req.TargetDate); err != nil {
+		return err
+	}
+
+	if arg.AccountID, err = s.ownedAccount(ctx, userUUID, req.AccountID); err != nil {
+		return err
+	}
+
+	if err := s.repo.Create(ctx, arg); err != nil {
+		return fmt.Errorf("service create: %w", err)
+	}
+
+	return nil
+}
+
+func (s *goalService) GetGoals(ctx context.Context, userID string) ([]GoalRes, error) {
+	records, err := s.repo.GetGoalsProgress(ctx, uuid.MustParse(userID), pgtype.UUID{})
+	if err != nil {
+		return nil, fmt.Errorf("service get goals: %w", err)
+	}
+
+	now := today()
+	res := make([]GoalRes, len(records))
+	for i, record := range records {
+		res[i] = goalToResponse(record, now)
+	}
+
+	return res, nil
+}
+
+func (s *goalService) GetGoal(ctx context.Context, userID, id string) (*GoalRes, error) {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrGoalNotFound
+	}
+
+	records, err := s.repo.GetGoalsProgress(ctx, uuid.MustParse(userID), pgtype.UUID{Bytes: idUUID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("service get goal: %w", err)
+	}
+
+	if len(records) == 0 {
+		return nil, ErrGoalNotFound
+	}
+
+	res := goalToResponse(records[0], today())
+	return &res, nil
+}
+
+func (s *goalService) Update(ctx context.Context, userID, id string, req UpdateGoalReq) error {
+	userUUID := uuid.MustParse(userID)
+
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrGoalNotFound
+	}
+
+	record, err := s.repo.GetGoal(ctx, idUUID, userUUID)
+	if err != nil {
+		return err
+	}
+
+	arg := db.UpdateGoalParams{
+		ID:           record.ID,
+		Name:         record.Name,
+		TargetAmount: record.TargetAmount,
+		TargetDate:   record.TargetDate,
+		AccountID:    record.AccountID,
+		UserID:       userUUID,
+	}
+
+	if req.Name != nil {
+		arg.Name = *req.Name
+	}
+
+	if req.TargetAmount != nil {
+		arg.TargetAmount = *req.TargetAmount
+	}
+
+	if req.TargetDate != nil {
+		if arg.TargetDate, err = parseDate(*req.TargetDate); err != nil {
+			return err
+		}
+	}
+
+	if req.AccountID != nil {
+		if arg.AccountID, err = s.ownedAccount(ctx, userUUID, *req.AccountID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.Update(ctx, arg); err != nil {
+		return fmt.Errorf("service update: %w", err)
+	}
+
+	return nil
+}
+
+func (s *goalService) Delete(ctx context.Context, userID, id string) error {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrGoalNotFound
+	}
+
+	return s.repo.Delete(ctx, idUUID, uuid.MustParse(userID))
+}
+
+func (s *goalService) GetContributions(ctx context.Context, userID, id string) ([]ContributionRes, error) {
+	goal, err := s.goal(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.repo.GetContributions(ctx, goal.ID)
+	if err != nil {
+		return nil, fmt.Errorf("service get contributions: %w", err)
+	}
+
+	res := make([]ContributionRes, len(records))
+	for i, record := range records {
+		item := ContributionRes{
+			TransactionID: record.ID.String(),
+			Date:          record.Date.Time.Format("2006-01-02"),
+			Description:   record.Description,
+			Amount:        contributed(record.Type, record.Amount),
+			Type:          string(record.Type),
+			AccountID:     record.AccountID.String(),
+		}
+
+		if record.TransferID.Valid {
+			item.TransferID = uuid.UUID(record.TransferID.Bytes).String()
+		}
+
+		res[i] = item
+	}
+
+	return res, nil
+}
+
+// AddContribution links an existing transaction or transfer to the goal, or
+// creates a transfer into the goal's account.
+func (s *goalService) AddContribution(ctx context.Context, userID, id string, req ContributionReq) error {
+	goal, err := s.goal(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case req.TransactionID != "":
+		return s.repo.CreateContribution(ctx, db.CreateGoalContributionParams{
+			GoalID:        goal.ID,
+			TransactionID: uuid.MustParse(req.TransactionID),
+			UserID:        goal.UserID,
+		})
+	case req.TransferID != "":
+		return s.repo.CreateTransferContribution(ctx, db.CreateGoalTransferContributionParams{
+			GoalID:     goal.ID,
+			TransferID: pgtype.UUID{Bytes: uuid.MustParse(req.TransferID), Valid: true},
+			UserID:     goal.UserID,
+		})
+	}
+
+	if !goal.AccountID.Valid {
+		return ErrNoGoalAccount
+	}
+
+	if uuid.MustParse(req.FromAccountID) == uuid.UUID(goal.AccountID.Bytes) {
+		return transfer.ErrSameAccount
+	}
+
+	date := req.Date
+	if date == "" {
+		date = today().Format("2006-01-02")
+	}
+
+	return s.transfers.Create(ctx, userID, transfer.TransferCreateRequest{
+		Description:   "Contribution to " + goal.Name,
+		Amount:        *req.Amount,
+		Date:          date,
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   uuid.UUID(goal.AccountID.Bytes).String(),
+		GoalID:        goal.ID.String(),
+	})
+}
+
+// RemoveContribution unlinks a transaction from the goal; the transaction
+// itself is kept.
+func (s *goalService) RemoveContribution(ctx context.Context, userID, id, transactionID string) error {
+	goal, err := s.goal(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	transactionUUID, err := uuid.Parse(transactionID)
+	if err != nil {
+		return ErrContributionNotFound
+	}
+
+	return s.repo.DeleteContribution(ctx, goal.ID, transactionUUID)
+}
+
+func (s *goalService) goal(ctx context.Context, userID, id string) (*db.Goal, error) {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrGoalNotFound
+	}
+
+	return s.repo.GetGoal(ctx, idUUID, uuid.MustParse(userID))
+}
+
+// ownedAccount returns a NULL UUID for an empty id.
+func (s *goalService) ownedAccount(ctx context.Context, userID uuid.UUID, id string) (pgtype.UUID, error) {
+	if id == "" {
+		return pgtype.UUID{}, nil
+	}
+
+	accountUUID := uuid.MustParse(id)
+	if _, err := s.accounts.GetAccount(ctx, accountUUID, userID); err != nil {
+		if errors.Is(err, account.ErrAccountNotFound) {
+			return pgtype.UUID{}, ErrInvalidAccount
+		}
+		return pgtype.UUID{}, fmt.Errorf("service check account: %w", err)
+	}
+
+	return pgtype.UUID{Bytes: accountUUID, Valid: true}, nil
+}
+
+// parseDate returns a NULL date for an empty string.
+func parseDate(value string) (pgtype.Date, error) {
+	if value == "" {
+		return pgtype.Date{}, nil
+	}
+
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return pgtype.Date{}, fmt.Errorf("invalid date format: %w", err)
+	}
+
+	return pgtype.Date{Time: date, Valid: true}, nil
+}
+
+// contributed returns how much a transaction adds to a goal.
+func contributed(t db.TransactionType, amount money.Money) money.Money {
+	if t == db.TransactionTypeIncome || t == db.TransactionTypeTransferIn {
+		return amount
+	}
+	return -amount
+}
+
+func goalToResponse(record *db.GetGoalsProgressRow, today time.Time) GoalRes {
+	saved := money.FromCents(record.Saved)
+
+	res := GoalRes{
+		ID:            record.ID.String(),
+		Name:          record.Name,
+		TargetAmount:  record.TargetAmount,
+		Saved:         saved,
+		Remaining:     max(record.TargetAmount-saved, 0),
+		Percent:       percent(saved, record.TargetAmount),
+		Completed:     saved >= record.TargetAmount,
+		Contributions: record.Contributions,
+		CreatedAt:     record.CreatedAt.Time,
+		UpdatedAt:     record.UpdatedAt.Time,
+	}
+
+	if record.AccountID.Valid {
+		res.AccountID = uuid.UUID(record.AccountID.Bytes).String()
+	}
+
+	if record.FirstContribution.Valid {
+		res.MonthlyAverage = monthlyAverage(saved, record.FirstContribution.Time, today)
+	}
+
+	// Without a positive pace there is no projection.
+	var projected *time.Time
+	if !res.Completed && res.MonthlyAverage > 0 {
+		date := projectedDate(res.Remaining, res.MonthlyAverage, today)
+		formatted := date.Format("2006-01-02")
+		projected, res.ProjectedDate = &date, &formatted
+	}
+
+	if record.TargetDate.Valid {
+		res.TargetDate = record.TargetDate.Time.Format("2006-01-02")
+
+		var needed money.Money
+		if !res.Completed {
+			needed = monthlyNeeded(res.Remaining, today, record.TargetDate.Time)
+		}
+		res.MonthlyNeeded = &needed
+
+		onTrack := res.Completed || (projected != nil && !projected.After(record.TargetDate.Time))
+		res.OnTrack = &onTrack
+	}
+
+	return res
+}
@@ -0,0 +1,233 @@
+package imports
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+)
+
+const maxRows = 5000
+
+const (
+	signNegativeExpense = "negative_expense"
+	signPositiveExpense = "positive_expense"
+)
+
+var ErrInvalidFile = errors.New("invalid file")
+
+// row is a statement line read from a file, before it becomes a
+// transaction. Err is set when the line could not be read.
+type row struct {
+	Number      int
+	Date        time.Time
+	Description string
+	Amount      money.Money
+	Type        db.TransactionType
+	Hash        string
+	Err         error
+}
+
+// dateLayout turns a format such as DD/MM/YYYY into a Go time layout.
+func dateLayout(format string) string {
+	return strings.NewReplacer("YYYY", "2006", "YY", "06", "MM", "01", "DD", "02").Replace(format)
+}
+
+func validDateFormat(format string) bool {
+	return strings.Contains(format, "DD") && strings.Contains(format, "MM") && strings.Contains(format, "YY")
+}
+
+// readCSV reads the lines of a bank export as described by profile. Lines
+// that cannot be parsed are returned with Err set instead of failing the
+// whole file.
+func readCSV(profile *db.ImportProfile, r io.Reader) ([]row, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = rune(profile.Delimiter[0])
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	var columns map[string]int
+	if profile.HasHeader {
+		header, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("%w: cannot read header: %v", ErrInvalidFile, err)
+		}
+
+		columns = make(map[string]int, len(header))
+		for i, name := range header {
+			name = strings.TrimPrefix(name, "\ufeff")
+			columns[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+	}
+
+	dateIdx, err := columnIndex(profile.DateColumn, profile.HasHeader, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptionIdx, err := columnIndex(profile.DescriptionColumn, profile.HasHeader, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	amountIdx, err := columnIndex(profile.AmountColumn, profile.HasHeader, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := dateLayout(profile.DateFormat)
+	seen := make(map[string]int)
+
+	var lines []row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+
+		if len(lines) == maxRows {
+			return nil, fmt.Errorf("%w: more than %d lines", ErrInvalidFile, maxRows)
+		}
+
+		number, _ := reader.FieldPos(0)
+		line := row{Number: number}
+
+		if isBlank(record) {
+			continue
+		}
+
+		if len(record) <= max(dateIdx, descriptionIdx, amountIdx) {
+			line.Err = errors.New("missing columns")
+			lines = append(lines, line)
+			continue
+		}
+
+		line.Description = strings.TrimSpace(record[descriptionIdx])
+		if line.Description == "" {
+			line.Err = errors.New("empty description")
+			lines = append(lines, line)
+			continue
+		}
+
+		line.Date, err = time.Parse(layout, strings.TrimSpace(record[dateIdx]))
+		if err != nil {
+			line.Err = fmt.Errorf("date does not match format %s", profile.DateFormat)
+			lines = append(lines, line)
+			continue
+		}
+
+		amount, err := parseAmount(record[amountIdx], profile.DecimalSeparator)
+		if err != nil || amount == 0 {
+			line.Err = errors.New("invalid amount")
+			lines = append(lines, line)
+			continue
+		}
+
+		expense := amount < 0
+		if profile.SignConvention == signPositiveExpense {
+			expense = amount > 0
+		}
+
+		line.Amount = amount.Abs()
+		line.Type = db.TransactionTypeIncome
+		if expense {
+			line.Type = db.TransactionTypeExpense
+		}
+
+		// Identical lines in the same file are told apart by their position
+		// among themselves, which is stable between exports.
+		key := lineKey(line)
+		line.Hash = hash("csv", key, strconv.Itoa(seen[key]))
+		seen[key]++
+
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+func columnIndex(column string, hasHeader bool, columns map[string]int) (int, error) {
+	if hasHeader {
+		idx, ok := columns[strings.ToLower(strings.TrimSpace(column))]
+		if !ok {
+			return 0, fmt.Errorf("%w: column %q not found", ErrInvalidFile, column)
+		}
+		return idx, nil
+	}
+
+	position, err := strconv.Atoi(column)
+	if err != nil || position < 1 {
+		return 0, fmt.Errorf("%w: column %q must be a position starting at 1", ErrInvalidFile, column)
+	}
+	return position - 1, nil
+}
+
+// parseAmount reads amounts such as "-1.234,56", "R$ 10,00" or "(12.50)".
+// Anything other than digits, signs and the decimal separator, such as
+// thousands separators and currency symbols, is ignored.
+func parseAmount(value, decimalSeparator string) (money.Money, error) {
+	value = strings.TrimSpace(value)
+
+	negative := false
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = value[1 : len(value)-1]
+	}
+
+	var b strings.Builder
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9', r == '-', r == '+':
+			b.WriteRune(r)
+		case string(r) == decimalSeparator:
+			b.WriteRune('.')
+		}
+	}
+
+	amount, err := money.Parse(b.String())
+	if err != nil {
+		return 0, err
+	}
+
+	if negative {
+		amount = amount.Neg()
+	}
+
+	return amount, nil
+}
+
+func lineKey(line row) string {
+	description := strings.Join(strings.Fields(strings.ToLower(line.Description)), " ")
+	signed := line.Amount
+	if line.Type == db.TransactionTypeExpense {
+		signed = signed.Neg()
+	}
+	return strings.Join([]string{line.Date.Format("2006-01-02"), signed.String(), description}, "|")
+}
+
+// hash builds the import hash stored on each transaction.
+func hash(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
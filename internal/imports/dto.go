@@ -0,0 +1,165 @@
+package imports
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+)
+
+var delimiterOptions = map[string]bool{",": true, ";": true, "\t": true, "|": true}
+
+// ProfileReq is used both to create and to replace a profile.
+type ProfileReq struct {
+	Name              string `json:"name"`
+	Delimiter         string `json:"delimiter"`          // padrão: ","
+	HasHeader         *bool  `json:"has_header"`         // padrão: true
+	DateColumn        string `json:"date_column"`        // nome da coluna, ou posição (1, 2, ...) sem cabeçalho
+	DateFormat        string `json:"date_format"`        // ex.: DD/MM/YYYY, YYYY-MM-DD
+	DescriptionColumn string `json:"description_column"` // nome da coluna, ou posição sem cabeçalho
+	AmountColumn      string `json:"amount_column"`      // nome da coluna, ou posição sem cabeçalho
+	DecimalSeparator  string `json:"decimal_separator"`  // "." ou ",", padrão: "."
+	SignConvention    string `json:"sign_convention"`    // negative_expense ou positive_expense
+}
+
+// Valid fills the optional fields with their defaults before checking them.
+func (r *ProfileReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	r.setDefaults()
+
+	eval.CheckField(validator.NotBlank(r.Name), "name", "this field cannot be empty")
+	eval.CheckField(delimiterOptions[r.Delimiter], "delimiter", "this field must be ',', ';', '|' or a tab")
+	eval.CheckField(validDateFormat(r.DateFormat), "date_format", "this field must contain DD, MM and YY or YYYY, e.g. DD/MM/YYYY")
+	eval.CheckField(r.DecimalSeparator == "." || r.DecimalSeparator == ",", "decimal_separator", "this field must be '.' or ','")
+	eval.CheckField(r.SignConvention == signNegativeExpense || r.SignConvention == signPositiveExpense, "sign_convention", "this field must be 'negative_expense' or 'positive_expense'")
+
+	columns := map[string]string{
+		"date_column":        r.DateColumn,
+		"description_column": r.DescriptionColumn,
+		"amount_column":      r.AmountColumn,
+	}
+
+	for field, column := range columns {
+		eval.CheckField(validator.NotBlank(column), field, "this field cannot be empty")
+
+		if !*r.HasHeader {
+			position, err := strconv.Atoi(column)
+			eval.CheckField(err == nil && position > 0, field, "without a header this field must be a column position starting at 1")
+		}
+	}
+
+	return eval
+}
+
+func (r *ProfileReq) setDefaults() {
+	if r.Delimiter == "" {
+		r.Delimiter = ","
+	}
+
+	if r.HasHeader == nil {
+		hasHeader := true
+		r.HasHeader = &hasHeader
+	}
+
+	if r.DecimalSeparator == "" {
+		r.DecimalSeparator = "."
+	}
+
+	if r.SignConvention == "" {
+		r.SignConvention = signNegativeExpense
+	}
+}
+
+type ProfileRes struct {
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	Delimiter         string    `json:"delimiter"`
+	HasHeader         bool      `json:"has_header"`
+	DateColumn        string    `json:"date_column"`
+	DateFormat        string    `json:"date_format"`
+	DescriptionColumn string    `json:"description_column"`
+	AmountColumn      string    `json:"amount_column"`
+	DecimalSeparator  string    `json:"decimal_separator"`
+	SignConvention    string    `json:"sign_convention"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func ProfileToResponse(p *db.ImportProfile) ProfileRes {
+	return ProfileRes{
+		ID:                p.ID.String(),
+		Name:              p.Name,
+		Delimiter:         p.Delimiter,
+		HasHeader:         p.HasHeader,
+		DateColumn:        p.DateColumn,
+		DateFormat:        p.DateFormat,
+		DescriptionColumn: p.DescriptionColumn,
+		AmountColumn:      p.AmountColumn,
+		DecimalSeparator:  p.DecimalSeparator,
+		SignConvention:    p.SignConvention,
+		CreatedAt:         p.CreatedAt.Time,
+		UpdatedAt:         p.UpdatedAt.Time,
+	}
+}
+
+// ImportReq holds the form fields sent along with the uploaded file.
+type ImportReq struct {
+	AccountID         string
+	IncomeCategoryID  string
+	ExpenseCategoryID string
+	DryRun            bool
+}
+
+func (r *ImportReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.UUID(r.AccountID), "account_id", "this field must be a valid UUID")
+	eval.CheckField(validator.UUID(r.IncomeCategoryID), "income_category_id", "this field must be a valid UUID")
+	eval.CheckField(validator.UUID(r.ExpenseCategoryID), "expense_category_id", "this field must be a valid UUID")
+
+	return eval
+}
+
+const (
+	StatusNew       = "new"
+	StatusImported  = "imported"
+	StatusDuplicate = "duplicate"
+	StatusInvalid   = "invalid"
+)
+
+type ImportRes struct {
+	DryRun     bool           `json:"dry_run"`
+	Total      int            `json:"total"`
+	New        int            `json:"new"`
+	Imported   int            `json:"imported"`
+	Duplicates int            `json:"duplicates"`
+	Invalid    int            `json:"invalid"`
+	Rows       []ImportRowRes `json:"rows"`
+
+	BalanceCheck *BalanceCheckRes `json:"balance_check,omitempty"`
+}
+
+// BalanceCheckRes compares the balance reported by the bank with the account
+// balance. On a dry run the account balance includes the lines that would be
+// imported.
+type BalanceCheckRes struct {
+	StatementBalance money.Money `json:"statement_balance"`
+	StatementDate    string      `json:"statement_date"`
+	AccountBalance   money.Money `json:"account_balance"`
+	Difference       money.Money `json:"difference"`
+	Matches          bool        `json:"matches"`
+}
+
+type ImportRowRes struct {
+	Line        int         `json:"line"`
+	Date        string      `json:"date,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Amount      money.Money `json:"amount"`
+	Type        string      `json:"type,omitempty"`
+	Status      string      `json:"status"` // new, imported, duplicate ou invalid
+	Error       string      `json:"error,omitempty"`
+}
@@ -0,0 +1,295 @@
+package imports
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/EduardoMark/my-finance-api/internal/account"
+	"github.com/EduardoMark/my-finance-api/internal/books"
+	"github.com/EduardoMark/my-finance-api/internal/middlewares"
+	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/EduardoMark/my-finance-api/pkg/httputils"
+	"github.com/EduardoMark/my-finance-api/pkg/ofx"
+	"github.com/EduardoMark/my-finance-api/pkg/token"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxUploadSize limits the size of an uploaded statement.
+const maxUploadSize = 5 << 20
+
+type ImportHandler struct {
+	svc   Service
+	token *token.TokenManager
+}
+
+func NewImportHandler(svc Service, token *token.TokenManager) ImportHandler {
+	return ImportHandler{
+		svc:   svc,
+		token: token,
+	}
+}
+
+func (h *ImportHandler) RegisterImportRoutes(r chi.Router) {
+	r.Route("/imports", func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(h.token))
+
+		r.Post("/csv", h.ImportCSV)
+		r.Post("/ofx", h.ImportOFX)
+
+		r.Route("/profiles", func(r chi.Router) {
+			r.Post("/", h.CreateProfile)
+			r.Get("/", h.GetProfiles)
+			r.Get("/{id}", h.GetProfile)
+			r.Put("/{id}", h.UpdateProfile)
+			r.Delete("/{id}", h.DeleteProfile)
+		})
+	})
+}
+
+func (h *ImportHandler) CreateProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*ProfileReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.svc.CreateProfile(ctx, userID, data); err != nil {
+		if errors.Is(err, ErrDuplicatedProfile) {
+			_ = httputils.EncodeJson(w, r, http.StatusConflict, map[string]string{"error": err.Error()})
+			return
+		}
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	httputils.Created(w)
+}
+
+func (h *ImportHandler) GetProfiles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	res, err := h.svc.GetProfiles(ctx, userID)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *ImportHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	res, err := h.svc.GetProfile(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, ErrProfileNotFound) {
+			_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *ImportHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*ProfileReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.svc.UpdateProfile(ctx, userID, id, data); err != nil {
+		if errors.Is(err, ErrProfileNotFound) {
+			_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrDuplicatedProfile) {
+			_ = httputils.EncodeJson(w, r, http.StatusConflict, map[string]string{"error": err.Error()})
+			return
+		}
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+func (h *ImportHandler) DeleteProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	if err := h.svc.DeleteProfile(ctx, userID, id); err != nil {
+		if errors.Is(err, ErrProfileNotFound) {
+			_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+// ImportCSV expects a multipart form with the statement in "file", the
+// profile in "profile_id" and the ImportReq fields.
+func (h *ImportHandler) ImportCSV(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	req, file, problems, ok := parseImportForm(w, r)
+	if !ok {
+		return
+	}
+
+	profileID := r.FormValue("profile_id")
+	problems.CheckField(validator.UUID(profileID), "profile_id", "this field must be a valid UUID")
+
+	if len(problems) > 0 {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer file.Close()
+
+	res, err := h.svc.ImportCSV(ctx, userID, profileID, req, file)
+	if err != nil {
+		writeImportError(w, r, err)
+		return
+	}
+
+	writeImportResult(w, r, req, res)
+}
+
+// ImportOFX expects a multipart form with the OFX/QFX statement in "file" and
+// the ImportReq fields.
+func (h *ImportHandler) ImportOFX(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	req, file, problems, ok := parseImportForm(w, r)
+	if !ok {
+		return
+	}
+
+	if len(problems) > 0 {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer file.Close()
+
+	res, err := h.svc.ImportOFX(ctx, userID, req, file)
+	if err != nil {
+		writeImportError(w, r, err)
+		return
+	}
+
+	writeImportResult(w, r, req, res)
+}
+
+// parseImportForm reads the fields shared by every import. ok is false when
+// a response was already written. dry_run defaults to false.
+func parseImportForm(w http.ResponseWriter, r *http.Request) (*ImportReq, multipart.File, validator.Evaluator, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusBadRequest, map[string]string{"error": "invalid multipart form or file larger than 5MB"})
+		return nil, nil, nil, false
+	}
+
+	req := &ImportReq{
+		AccountID:         r.FormValue("account_id"),
+		IncomeCategoryID:  r.FormValue("income_category_id"),
+		ExpenseCategoryID: r.FormValue("expense_category_id"),
+	}
+
+	problems := req.Valid(r.Context())
+
+	if value := r.FormValue("dry_run"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		problems.CheckField(err == nil, "dry_run", "this field must be true or false")
+		req.DryRun = dryRun
+	}
+
+	file, _, err := r.FormFile("file")
+	problems.CheckField(err == nil, "file", "a statement file must be sent in this field")
+
+	return req, file, problems, true
+}
+
+func writeImportError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrProfileNotFound):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"profile_id": err.Error()})
+	case errors.Is(err, ErrInvalidAccount):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"account_id": err.Error()})
+	case errors.Is(err, ErrInvalidIncomeCategory):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"income_category_id": err.Error()})
+	case errors.Is(err, ErrInvalidExpenseCategory):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"expense_category_id": err.Error()})
+	case errors.Is(err, ErrInvalidFile), errors.Is(err, ofx.ErrInvalidFile):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"file": err.Error()})
+	case errors.Is(err, account.ErrNegativeBalance), errors.Is(err, books.ErrClosed):
+		_ = httputils.EncodeJson(w, r, http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
+
+func writeImportResult(w http.ResponseWriter, r *http.Request, req *ImportReq, res *ImportRes) {
+	status := http.StatusCreated
+	if req.DryRun {
+		status = http.StatusOK
+	}
+
+	_ = httputils.EncodeJson(w, r, status, res)
+}
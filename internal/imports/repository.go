@@ -0,0 +1,119 @@
+package imports
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Repository interface {
+	CreateProfile(ctx context.Context, arg db.CreateImportProfileParams) error
+	GetProfile(ctx context.Context, id, userID uuid.UUID) (*db.ImportProfile, error)
+	GetProfiles(ctx context.Context, userID uuid.UUID) ([]*db.ImportProfile, error)
+	UpdateProfile(ctx context.Context, arg db.UpdateImportProfileParams) error
+	DeleteProfile(ctx context.Context, id, userID uuid.UUID) error
+	GetImportedHashes(ctx context.Context, accountID uuid.UUID, hashes []string) (map[string]bool, error)
+	GetBalanceAsOf(ctx context.Context, accountID, userID uuid.UUID, asOf pgtype.Date) (money.Money, error)
+}
+
+type importRepository struct {
+	db *db.Queries
+}
+
+func NewImportRepository(db *db.Queries) Repository {
+	return &importRepository{db: db}
+}
+
+var ErrProfileNotFound = errors.New("import profile not found")
+var ErrDuplicatedProfile = errors.New("import profile already exist with this name")
+
+func (r *importRepository) CreateProfile(ctx context.Context, arg db.CreateImportProfileParams) error {
+	var pgErr *pgconn.PgError
+
+	if err := r.db.CreateImportProfile(ctx, arg); err != nil {
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrDuplicatedProfile
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *importRepository) GetProfile(ctx context.Context, id, userID uuid.UUID) (*db.ImportProfile, error) {
+	record, err := r.db.GetImportProfile(ctx, db.GetImportProfileParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrProfileNotFound
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+func (r *importRepository) GetProfiles(ctx context.Context, userID uuid.UUID) ([]*db.ImportProfile, error) {
+	records, err := r.db.GetImportProfiles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (r *importRepository) UpdateProfile(ctx context.Context, arg db.UpdateImportProfileParams) error {
+	var pgErr *pgconn.PgError
+
+	if _, err := r.GetProfile(ctx, arg.ID, arg.UserID); err != nil {
+		return err
+	}
+
+	if err := r.db.UpdateImportProfile(ctx, arg); err != nil {
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrDuplicatedProfile
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *importRepository) DeleteProfile(ctx context.Context, id, userID uuid.UUID) error {
+	if _, err := r.GetProfile(ctx, id, userID); err != nil {
+		return err
+	}
+
+	if err := r.db.DeleteImportProfile(ctx, db.DeleteImportProfileParams{ID: id, UserID: userID}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetImportedHashes returns which of hashes were already imported into the
+// account.
+func (r *importRepository) GetImportedHashes(ctx context.Context, accountID uuid.UUID, hashes []string) (map[string]bool, error) {
+	records, err := r.db.GetImportedHashes(ctx, db.GetImportedHashesParams{AccountID: accountID, Hashes: hashes})
+	if err != nil {
+		return nil, err
+	}
+
+	imported := make(map[string]bool, len(records))
+	for _, hash := range records {
+		imported[hash] = true
+	}
+	return imported, nil
+}
+
+// GetBalanceAsOf returns the balance of the account at the end of asOf,
+// leaving out transactions dated after it.
+func (r *importRepository) GetBalanceAsOf(ctx context.Context, accountID, userID uuid.UUID, asOf pgtype.Date) (money.Money, error) {
+	balance, err := r.db.GetAccountBalanceAsOf(ctx, db.GetAccountBalanceAsOfParams{AsOf: asOf, AccountID: accountID, UserID: userID})
+	if err != nil {
+		return 0, err
+	}
+	return money.FromCents(balance), nil
+}
@@ -0,0 +1,378 @@
+package imports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/EduardoMark/my-finance-api/internal/account"
+	"github.com/EduardoMark/my-finance-api/internal/category"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/transaction"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/EduardoMark/my-finance-api/pkg/ofx"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Service interface {
+	CreateProfile(ctx context.Context, userID string, req *ProfileReq) error
+	GetProfiles(ctx context.Context, userID string) ([]ProfileRes, error)
+	GetProfile(ctx context.Context, userID, id string) (*ProfileRes, error)
+	UpdateProfile(ctx context.Context, userID, id string, req *ProfileReq) error
+	DeleteProfile(ctx context.Context, userID, id string) error
+	ImportCSV(ctx context.Context, userID, profileID string, req *ImportReq, file io.Reader) (*ImportRes, error)
+	ImportOFX(ctx context.Context, userID string, req *ImportReq, file io.Reader) (*ImportRes, error)
+}
+
+type importService struct {
+	repo         Repository
+	accounts     account.Repository
+	categories   category.Repository
+	transactions transaction.Service
+}
+
+func NewImportService(repo Repository, accounts account.Repository, categories category.Repository, transactions transaction.Service) Service {
+	return &importService{
+		repo:         repo,
+		accounts:     accounts,
+		categories:   categories,
+		transactions: transactions,
+	}
+}
+
+var ErrInvalidAccount = errors.New("account not found")
+var ErrInvalidIncomeCategory = errors.New("category must be an income category of the user")
+var ErrInvalidExpenseCategory = errors.New("category must be an expense category of the user")
+
+func (s *importService) CreateProfile(ctx context.Context, userID string, req *ProfileReq) error {
+	arg := db.CreateImportProfileParams{
+		UserID:            uuid.MustParse(userID),
+		Name:              req.Name,
+		Delimiter:         req.Delimiter,
+		HasHeader:         *req.HasHeader,
+		DateColumn:        req.DateColumn,
+		DateFormat:        req.DateFormat,
+		DescriptionColumn: req.DescriptionColumn,
+		AmountColumn:      req.AmountColumn,
+		DecimalSeparator:  req.DecimalSeparator,
+		SignConvention:    req.SignConvention,
+	}
+
+	if err := s.repo.CreateProfile(ctx, arg); err != nil {
+		if errors.Is(err, ErrDuplicatedProfile) {
+			return err
+		}
+		return fmt.Errorf("service create profile: %w", err)
+	}
+
+	return nil
+}
+
+func (s *importService) GetProfiles(ctx context.Context, userID string) ([]ProfileRes, error) {
+	records, err := s.repo.GetProfiles(ctx, uuid.MustParse(userID))
+	if err != nil {
+		return nil, fmt.Errorf("service get profiles: %w", err)
+	}
+
+	res := make([]ProfileRes, len(records))
+	for i, record := range records {
+		res[i] = ProfileToResponse(record)
+	}
+
+	return res, nil
+}
+
+func (s *importService) GetProfile(ctx context.Context, userID, id string) (*ProfileRes, error) {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrProfileNotFound
+	}
+
+	record, err := s.repo.GetProfile(ctx, idUUID, uuid.MustParse(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	res := ProfileToResponse(record)
+	return &res, nil
+}
+
+func (s *importService) UpdateProfile(ctx context.Context, userID, id string, req *ProfileReq) error {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrProfileNotFound
+	}
+
+	arg := db.UpdateImportProfileParams{
+		ID:                idUUID,
+		Name:              req.Name,
+		Delimiter:         req.Delimiter,
+		HasHeader:         *req.HasHeader,
+		DateColumn:        req.DateColumn,
+		DateFormat:        req.DateFormat,
+		DescriptionColumn: req.DescriptionColumn,
+		AmountColumn:      req.AmountColumn,
+		DecimalSeparator:  req.DecimalSeparator,
+		SignConvention:    req.SignConvention,
+		UserID:            uuid.MustParse(userID),
+	}
+
+	return s.repo.UpdateProfile(ctx, arg)
+}
+
+func (s *importService) DeleteProfile(ctx context.Context, userID, id string) error {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrProfileNotFound
+	}
+
+	return s.repo.DeleteProfile(ctx, idUUID, uuid.MustParse(userID))
+}
+
+// ImportCSV reads file with the chosen profile and imports its lines.
+func (s *importService) ImportCSV(ctx context.Context, userID, profileID string, req *ImportReq, file io.Reader) (*ImportRes, error) {
+	userUUID := uuid.MustParse(userID)
+
+	profile, err := s.repo.GetProfile(ctx, uuid.MustParse(profileID), userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTarget(ctx, userUUID, req); err != nil {
+		return nil, err
+	}
+
+	lines, err := readCSV(profile, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.importLines(ctx, userID, req, lines)
+}
+
+// ImportOFX imports the transactions of an OFX statement. FITID identifies a
+// transaction, so downloading overlapping periods is safe. When the statement
+// has a LEDGERBAL the result includes a balance check against the account.
+func (s *importService) ImportOFX(ctx context.Context, userID string, req *ImportReq, file io.Reader) (*ImportRes, error) {
+	userUUID := uuid.MustParse(userID)
+
+	if err := s.checkTarget(ctx, userUUID, req); err != nil {
+		return nil, err
+	}
+
+	statements, err := ofx.Parse(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(statements) != 1 {
+		return nil, fmt.Errorf("%w: file has %d statements, only one is supported", ErrInvalidFile, len(statements))
+	}
+	statement := statements[0]
+
+	record, err := s.accounts.GetAccount(ctx, uuid.MustParse(req.AccountID), userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("service import ofx: %w", err)
+	}
+
+	if currency := strings.ToUpper(statement.Currency); currency != "" && currency != record.Currency {
+		return nil, fmt.Errorf("%w: statement currency %s does not match the account currency %s", ErrInvalidFile, currency, record.Currency)
+	}
+
+	lines := make([]row, len(statement.Transactions))
+	for i, trn := range statement.Transactions {
+		line := row{
+			Number:      i + 1,
+			Date:        trn.Posted,
+			Description: trn.Description(),
+			Amount:      trn.Amount.Abs(),
+			Type:        db.TransactionTypeIncome,
+			Hash:        hash("ofx", statement.AccountID, trn.FITID),
+		}
+
+		if trn.Amount < 0 {
+			line.Type = db.TransactionTypeExpense
+		}
+
+		switch {
+		case line.Description == "":
+			line.Err = errors.New("empty description")
+		case trn.Amount == 0:
+			line.Err = errors.New("invalid amount")
+		}
+
+		lines[i] = line
+	}
+
+	res, err := s.importLines(ctx, userID, req, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	if statement.LedgerBalance != nil {
+		// LEDGERBAL is the balance as of DTASOF, so the account balance is
+		// taken on the same date, without the transactions dated after it.
+		// It is read after the import, which changed it.
+		asOf := statement.LedgerBalance.AsOf
+		balance, err := s.repo.GetBalanceAsOf(ctx, uuid.MustParse(req.AccountID), userUUID, pgtype.Date{Time: asOf, Valid: true})
+		if err != nil {
+			return nil, fmt.Errorf("service import ofx: %w", err)
+		}
+
+		if req.DryRun {
+			for i, item := range res.Rows {
+				if item.Status == StatusNew && !lines[i].Date.After(asOf) {
+					balance += signedAmount(lines[i])
+				}
+			}
+		}
+
+		res.BalanceCheck = &BalanceCheckRes{
+			StatementBalance: statement.LedgerBalance.Amount,
+			StatementDate:    statement.LedgerBalance.AsOf.Format("2006-01-02"),
+			AccountBalance:   balance,
+			Difference:       balance - statement.LedgerBalance.Amount,
+			Matches:          balance == statement.LedgerBalance.Amount,
+		}
+	}
+
+	return res, nil
+}
+
+// importLines previews or creates the lines read from a file. With DryRun set
+// nothing is written; otherwise the new lines are created in one go through
+// the transaction service. Lines already imported into the account are
+// reported as duplicates and skipped.
+func (s *importService) importLines(ctx context.Context, userID string, req *ImportReq, lines []row) (*ImportRes, error) {
+	hashes := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line.Err == nil {
+			hashes = append(hashes, line.Hash)
+		}
+	}
+
+	imported, err := s.repo.GetImportedHashes(ctx, uuid.MustParse(req.AccountID), hashes)
+	if err != nil {
+		return nil, fmt.Errorf("service import: %w", err)
+	}
+
+	res := &ImportRes{DryRun: req.DryRun, Total: len(lines), Rows: make([]ImportRowRes, len(lines))}
+
+	var dtos []transaction.TransactionCreateRequest
+	var pending []int
+
+	for i, line := range lines {
+		item := ImportRowRes{Line: line.Number, Status: StatusNew}
+
+		if line.Err != nil {
+			item.Status = StatusInvalid
+			item.Error = line.Err.Error()
+			res.Invalid++
+			res.Rows[i] = item
+			continue
+		}
+
+		item.Date = line.Date.Format("2006-01-02")
+		item.Description = line.Description
+		item.Amount = line.Amount
+		item.Type = string(line.Type)
+
+		// The same FITID or line may appear twice in one file too.
+		if imported[line.Hash] {
+			item.Status = StatusDuplicate
+			res.Duplicates++
+			res.Rows[i] = item
+			continue
+		}
+		imported[line.Hash] = true
+
+		categoryID := req.ExpenseCategoryID
+		if line.Type == db.TransactionTypeIncome {
+			categoryID = req.IncomeCategoryID
+		}
+
+		dtos = append(dtos, transaction.TransactionCreateRequest{
+			Description: line.Description,
+			Amount:      line.Amount,
+			Date:        item.Date,
+			Type:        item.Type,
+			AccountID:   req.AccountID,
+			CategoryID:  categoryID,
+			ImportHash:  line.Hash,
+		})
+		pending = append(pending, i)
+
+		res.New++
+		res.Rows[i] = item
+	}
+
+	if req.DryRun || len(dtos) == 0 {
+		return res, nil
+	}
+
+	inserted, err := s.transactions.CreateBatch(ctx, userID, dtos)
+	if err != nil {
+		return nil, fmt.Errorf("service import: %w", err)
+	}
+
+	// A line may still turn out to be a duplicate if the same file is being
+	// imported concurrently.
+	for j, i := range pending {
+		if inserted[j] {
+			res.Rows[i].Status = StatusImported
+			res.Imported++
+		} else {
+			res.Rows[i].Status = StatusDuplicate
+			res.Duplicates++
+		}
+	}
+	res.New = 0
+
+	return res, nil
+}
+
+// checkTarget makes sure the account and both categories belong to the user
+// and that the categories have the right type.
+func (s *importService) checkTarget(ctx context.Context, userID uuid.UUID, req *ImportReq) error {
+	if _, err := s.accounts.GetAccount(ctx, uuid.MustParse(req.AccountID), userID); err != nil {
+		if errors.Is(err, account.ErrAccountNotFound) {
+			return ErrInvalidAccount
+		}
+		return fmt.Errorf("service check account: %w", err)
+	}
+
+	categories := []struct {
+		id      string
+		kind    db.TransactionType
+		invalid error
+	}{
+		{req.IncomeCategoryID, db.TransactionTypeIncome, ErrInvalidIncomeCategory},
+		{req.ExpenseCategoryID, db.TransactionTypeExpense, ErrInvalidExpenseCategory},
+	}
+
+	for _, c := range categories {
+		record, err := s.categories.GetCategory(ctx, uuid.MustParse(c.id), userID)
+		if errors.Is(err, category.ErrCategoryNotFound) {
+			return c.invalid
+		}
+		if err != nil {
+			return fmt.Errorf("service check category: %w", err)
+		}
+		if record.Type != c.kind {
+			return c.invalid
+		}
+	}
+
+	return nil
+}
+
+// signedAmount returns how much a line adds to the account balance.
+func signedAmount(line row) money.Money {
+	if line.Type == db.TransactionTypeExpense {
+		return line.Amount.Neg()
+	}
+	return line.Amount
+}
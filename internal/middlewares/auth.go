@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
 	"github.com/EduardoMark/my-finance-api/pkg/httpResponse"
 	"github.com/EduardoMark/my-finance-api/pkg/token"
 )
@@ -15,6 +16,10 @@ const (
 	ContextUserID contextKey = "user_id"
 	ContextName   contextKey = "name"
 	ContextExp    contextKey = "exp"
+	ContextRole   contextKey = "role"
+
+	// ContextImpersonatorID holds the admin acting as the user, if any.
+	ContextImpersonatorID contextKey = "impersonator_id"
 )
 
 func AuthMiddleware(jwtManager *token.TokenManager) func(http.Handler) http.Handler {
@@ -40,9 +45,22 @@ func AuthMiddleware(jwtManager *token.TokenManager) func(http.Handler) http.Hand
 				return
 			}
 
+			revoked, err := jwtManager.IsRevoked(r.Context(), claims)
+			if err != nil {
+				httpResponse.Error(w, http.StatusInternalServerError, "error on check token revocation")
+				return
+			}
+
+			if revoked {
+				httpResponse.Unauthorized(w)
+				return
+			}
+
 			ctx := context.WithValue(r.Context(), ContextUserID, claims.UserID)
 			ctx = context.WithValue(ctx, ContextName, claims.Name)
 			ctx = context.WithValue(ctx, ContextExp, claims.ExpiresAt)
+			ctx = context.WithValue(ctx, ContextRole, db.UserRole(claims.Role))
+			ctx = context.WithValue(ctx, ContextImpersonatorID, claims.ImpersonatorID)
 
 			r = r.WithContext(ctx)
 
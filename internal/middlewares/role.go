@@ -0,0 +1,30 @@
+package middlewares
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/pkg/httpResponse"
+)
+
+// RequireRole only lets through users with one of the given roles. It must
+// run after AuthMiddleware, which puts the role of the token in the context.
+func RequireRole(roles ...db.UserRole) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, ok := r.Context().Value(ContextRole).(db.UserRole)
+			if !ok {
+				httpResponse.Unauthorized(w)
+				return
+			}
+
+			if !slices.Contains(roles, role) {
+				httpResponse.Forbidden(w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
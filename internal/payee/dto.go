@@ -0,0 +1,147 @@
+package payee
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+)
+
+const (
+	maxNameLength    = 100
+	minPatternLength = 3
+	maxPatternLength = 100
+	maxMergeSources  = 50
+)
+
+// PayeeReq creates or replaces a payee. On create an omitted default
+// category is suggested from the transactions whose description contains
+// the name; on update it removes the default category.
+type PayeeReq struct {
+	Name              string `json:"name"`
+	DefaultCategoryID string `json:"default_category_id,omitempty"`
+}
+
+func (r *PayeeReq) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	r.Name = strings.TrimSpace(r.Name)
+	eval.CheckField(validator.NotBlank(r.Name), "name", "this field cannot be empty")
+	eval.CheckField(validator.MaxChars(r.Name, maxNameLength), "name", fmt.Sprintf("this field must have up to %d chars", maxNameLength))
+
+	if r.DefaultCategoryID != "" {
+		eval.CheckField(validator.UUID(r.DefaultCategoryID), "default_category_id", "this field must be a valid UUID")
+	}
+
+	return eval
+}
+
+// AliasReq adds a pattern that links descriptions containing it, ignoring
+// case, to the payee.
+type AliasReq struct {
+	Pattern string `json:"pattern"`
+}
+
+func (r *AliasReq) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	r.Pattern = strings.TrimSpace(r.Pattern)
+	eval.CheckField(validator.MinChars(r.Pattern, minPatternLength) && validator.MaxChars(r.Pattern, maxPatternLength),
+		"pattern", fmt.Sprintf("this field must have between %d and %d chars", minPatternLength, maxPatternLength))
+
+	return eval
+}
+
+// MergeReq lists the payees merged into the one in the path.
+type MergeReq struct {
+	SourceIDs []string `json:"source_ids"`
+}
+
+func (r *MergeReq) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(len(r.SourceIDs) > 0 && len(r.SourceIDs) <= maxMergeSources, "source_ids", fmt.Sprintf("this field must have between 1 and %d ids", maxMergeSources))
+
+	for i, id := range r.SourceIDs {
+		eval.CheckField(validator.UUID(id), fmt.Sprintf("source_ids[%d]", i), "this field must be a valid UUID")
+	}
+
+	return eval
+}
+
+type AliasRes struct {
+	ID        string    `json:"id"`
+	Pattern   string    `json:"pattern"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PayeeRes struct {
+	ID                string     `json:"id"`
+	Name              string     `json:"name"`
+	DefaultCategoryID string     `json:"default_category_id,omitempty"`
+	Aliases           []AliasRes `json:"aliases,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+func PayeeToResponse(p *db.Payee) PayeeRes {
+	res := PayeeRes{
+		ID:        p.ID.String(),
+		Name:      p.Name,
+		CreatedAt: p.CreatedAt.Time,
+		UpdatedAt: p.UpdatedAt.Time,
+	}
+
+	if p.DefaultCategoryID.Valid {
+		res.DefaultCategoryID = uuid.UUID(p.DefaultCategoryID.Bytes).String()
+	}
+
+	return res
+}
+
+func AliasToResponse(a *db.PayeeAlias) AliasRes {
+	return AliasRes{
+		ID:        a.ID.String(),
+		Pattern:   a.Pattern,
+		CreatedAt: a.CreatedAt.Time,
+	}
+}
+
+// MergeRes is the merged payee and how many transactions were moved to it.
+type MergeRes struct {
+	Payee        PayeeRes `json:"payee"`
+	Transactions int64    `json:"transactions"`
+}
+
+type PayeeTotalsFilters struct {
+	StartDate *string `json:"start_date,omitempty"`
+	EndDate   *string `json:"end_date,omitempty"`
+}
+
+func (f *PayeeTotalsFilters) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	if f.StartDate != nil {
+		eval.CheckField(validator.Date(*f.StartDate), "start_date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	if f.EndDate != nil {
+		eval.CheckField(validator.Date(*f.EndDate), "end_date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	return eval
+}
+
+type PayeeTotalRes struct {
+	ID           string      `json:"id"`
+	Name         string      `json:"name"`
+	Transactions int64       `json:"transactions"`
+	Income       money.Money `json:"income"`
+	Expense      money.Money `json:"expense"`
+	Net          money.Money `json:"net"`
+}
@@ -0,0 +1,267 @@
+package payee
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EduardoMark/my-finance-api/internal/exchangerate"
+	"github.com/EduardoMark/my-finance-api/internal/middlewares"
+	"github.com/EduardoMark/my-finance-api/pkg/httputils"
+	"github.com/EduardoMark/my-finance-api/pkg/token"
+	"github.com/go-chi/chi/v5"
+)
+
+type PayeeHandler struct {
+	svc   Service
+	token *token.TokenManager
+}
+
+func NewPayeeHandler(svc Service, token *token.TokenManager) PayeeHandler {
+	return PayeeHandler{
+		svc:   svc,
+		token: token,
+	}
+}
+
+func (h *PayeeHandler) RegisterPayeeRoutes(r chi.Router) {
+	r.Route("/payees", func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(h.token))
+
+		r.Post("/", h.Create)
+		r.Get("/", h.GetPayees)
+		r.Get("/totals", h.GetTotals)
+		r.Get("/{id}", h.GetPayee)
+		r.Put("/{id}", h.Update)
+		r.Delete("/{id}", h.Delete)
+		r.Post("/{id}/aliases", h.CreateAlias)
+		r.Delete("/{id}/aliases/{alias_id}", h.DeleteAlias)
+		r.Post("/{id}/merge", h.Merge)
+	})
+}
+
+func (h *PayeeHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*PayeeReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	res, err := h.svc.Create(ctx, userID, data)
+	if err != nil {
+		writePayeeError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusCreated, res)
+}
+
+func (h *PayeeHandler) GetPayees(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	res, err := h.svc.GetPayees(ctx, userID)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *PayeeHandler) GetTotals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	query := r.URL.Query()
+	filters := &PayeeTotalsFilters{}
+
+	if value := query.Get("start_date"); value != "" {
+		filters.StartDate = &value
+	}
+
+	if value := query.Get("end_date"); value != "" {
+		filters.EndDate = &value
+	}
+
+	if problems := filters.Valid(ctx); len(problems) > 0 {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	res, err := h.svc.GetTotals(ctx, userID, filters)
+	if err != nil {
+		writePayeeError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *PayeeHandler) GetPayee(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	res, err := h.svc.GetPayee(ctx, userID, id)
+	if err != nil {
+		writePayeeError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *PayeeHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*PayeeReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.svc.Update(ctx, userID, id, data); err != nil {
+		writePayeeError(w, r, err)
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+func (h *PayeeHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	if err := h.svc.Delete(ctx, userID, id); err != nil {
+		writePayeeError(w, r, err)
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+func (h *PayeeHandler) CreateAlias(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*AliasReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	res, err := h.svc.CreateAlias(ctx, userID, id, data)
+	if err != nil {
+		writePayeeError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusCreated, res)
+}
+
+func (h *PayeeHandler) DeleteAlias(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	if err := h.svc.DeleteAlias(ctx, userID, id, chi.URLParam(r, "alias_id")); err != nil {
+		writePayeeError(w, r, err)
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+// Merge folds the payees in source_ids into the payee in the path.
+func (h *PayeeHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*MergeReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	res, err := h.svc.Merge(ctx, userID, id, data)
+	if err != nil {
+		writePayeeError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func writePayeeError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrPayeeNotFound), errors.Is(err, ErrAliasNotFound):
+		_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrDuplicatedPayee), errors.Is(err, ErrDuplicatedAlias):
+		_ = httputils.EncodeJson(w, r, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrInvalidCategory):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"default_category_id": err.Error()})
+	case errors.Is(err, ErrInvalidSource), errors.Is(err, ErrMergeIntoItself):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"source_ids": err.Error()})
+	case errors.Is(err, exchangerate.ErrMissingRate):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+	default:
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
@@ -0,0 +1,206 @@
+package payee
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/EduardoMark/my-finance-api/internal/exchangerate"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type Repository interface {
+	Create(ctx context.Context, arg db.CreatePayeeParams) (*db.Payee, error)
+	GetPayee(ctx context.Context, id, userID uuid.UUID) (*db.Payee, error)
+	GetPayees(ctx context.Context, userID uuid.UUID) ([]*db.Payee, error)
+	Update(ctx context.Context, arg db.UpdatePayeeParams) error
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+	CreateAlias(ctx context.Context, arg db.CreatePayeeAliasParams) (*db.PayeeAlias, error)
+	GetAliases(ctx context.Context, payeeID uuid.UUID) ([]*db.PayeeAlias, error)
+	DeleteAlias(ctx context.Context, id, payeeID, userID uuid.UUID) error
+	Match(ctx context.Context, userID uuid.UUID, description string) (*db.Payee, error)
+	SuggestCategory(ctx context.Context, userID uuid.UUID, name string) (uuid.UUID, bool, error)
+	Merge(ctx context.Context, userID, targetID uuid.UUID, sourceIDs []uuid.UUID) (int64, error)
+	GetTotals(ctx context.Context, arg db.GetPayeeTotalsParams) ([]*db.GetPayeeTotalsRow, error)
+	WithTx(q *db.Queries) Repository
+}
+
+type payeeRepository struct {
+	db *db.Queries
+}
+
+func NewPayeeRepository(db *db.Queries) Repository {
+	return &payeeRepository{db: db}
+}
+
+var ErrPayeeNotFound = errors.New("payee not found")
+var ErrDuplicatedPayee = errors.New("payee already exists")
+var ErrAliasNotFound = errors.New("alias not found")
+var ErrDuplicatedAlias = errors.New("alias pattern already in use")
+var ErrInvalidCategory = errors.New("category not found")
+var ErrInvalidSource = errors.New("source payee not found")
+var ErrMergeIntoItself = errors.New("a payee cannot be merged into itself")
+
+func (r *payeeRepository) Create(ctx context.Context, arg db.CreatePayeeParams) (*db.Payee, error) {
+	var pgErr *pgconn.PgError
+
+	record, err := r.db.CreatePayee(ctx, arg)
+	if err != nil {
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrDuplicatedPayee
+		}
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (r *payeeRepository) GetPayee(ctx context.Context, id, userID uuid.UUID) (*db.Payee, error) {
+	record, err := r.db.GetPayee(ctx, db.GetPayeeParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPayeeNotFound
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+func (r *payeeRepository) GetPayees(ctx context.Context, userID uuid.UUID) ([]*db.Payee, error) {
+	return r.db.GetPayees(ctx, userID)
+}
+
+func (r *payeeRepository) Update(ctx context.Context, arg db.UpdatePayeeParams) error {
+	var pgErr *pgconn.PgError
+
+	if _, err := r.GetPayee(ctx, arg.ID, arg.UserID); err != nil {
+		return err
+	}
+
+	if err := r.db.UpdatePayee(ctx, arg); err != nil {
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrDuplicatedPayee
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *payeeRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	if _, err := r.GetPayee(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return r.db.DeletePayee(ctx, db.DeletePayeeParams{ID: id, UserID: userID})
+}
+
+func (r *payeeRepository) CreateAlias(ctx context.Context, arg db.CreatePayeeAliasParams) (*db.PayeeAlias, error) {
+	var pgErr *pgconn.PgError
+
+	record, err := r.db.CreatePayeeAlias(ctx, arg)
+	if err != nil {
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrDuplicatedAlias
+		}
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (r *payeeRepository) GetAliases(ctx context.Context, payeeID uuid.UUID) ([]*db.PayeeAlias, error) {
+	return r.db.GetPayeeAliases(ctx, payeeID)
+}
+
+func (r *payeeRepository) DeleteAlias(ctx context.Context, id, payeeID, userID uuid.UUID) error {
+	rows, err := r.db.DeletePayeeAlias(ctx, db.DeletePayeeAliasParams{ID: id, PayeeID: payeeID, UserID: userID})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrAliasNotFound
+	}
+
+	return nil
+}
+
+// Match returns the payee whose alias best matches the description, or nil
+// when no alias does.
+func (r *payeeRepository) Match(ctx context.Context, userID uuid.UUID, description string) (*db.Payee, error) {
+	record, err := r.db.MatchPayee(ctx, db.MatchPayeeParams{UserID: userID, Description: description})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+// SuggestCategory reports false when no transaction description contains
+// the name.
+func (r *payeeRepository) SuggestCategory(ctx context.Context, userID uuid.UUID, name string) (uuid.UUID, bool, error) {
+	categoryID, err := r.db.SuggestPayeeCategory(ctx, db.SuggestPayeeCategoryParams{UserID: userID, Name: name})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, false, nil
+		}
+		return uuid.Nil, false, err
+	}
+	return categoryID, true, nil
+}
+
+// Merge moves the transactions and aliases of the sources to the target,
+// keeps their names as aliases and deletes them. It returns how many
+// transactions were moved and must run inside a database transaction.
+func (r *payeeRepository) Merge(ctx context.Context, userID, targetID uuid.UUID, sourceIDs []uuid.UUID) (int64, error) {
+	moved, err := r.db.ReassignPayeeTransactions(ctx, db.ReassignPayeeTransactionsParams{
+		TargetID:  targetID,
+		UserID:    userID,
+		SourceIds: sourceIDs,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("repository merge payees: %w", err)
+	}
+
+	err = r.db.ReassignPayeeAliases(ctx, db.ReassignPayeeAliasesParams{
+		TargetID:  targetID,
+		UserID:    userID,
+		SourceIds: sourceIDs,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("repository merge payees: %w", err)
+	}
+
+	err = r.db.AddPayeeNamesAsAliases(ctx, db.AddPayeeNamesAsAliasesParams{
+		TargetID:  targetID,
+		UserID:    userID,
+		SourceIds: sourceIDs,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("repository merge payees: %w", err)
+	}
+
+	if err := r.db.DeletePayees(ctx, db.DeletePayeesParams{UserID: userID, Ids: sourceIDs}); err != nil {
+		return 0, fmt.Errorf("repository merge payees: %w", err)
+	}
+
+	return moved, nil
+}
+
+func (r *payeeRepository) GetTotals(ctx context.Context, arg db.GetPayeeTotalsParams) ([]*db.GetPayeeTotalsRow, error) {
+	records, err := r.db.GetPayeeTotals(ctx, arg)
+	if err != nil {
+		return nil, exchangerate.MissingRate(err)
+	}
+	return records, nil
+}
+
+func (r *payeeRepository) WithTx(q *db.Queries) Repository {
+	return &payeeRepository{db: q}
+}
@@ -0,0 +1,307 @@
+package payee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/category"
+	"github.com/EduardoMark/my-finance-api/internal/exchangerate"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Service interface {
+	Create(ctx context.Context, userID string, req *PayeeReq) (*PayeeRes, error)
+	GetPayee(ctx context.Context, userID, id string) (*PayeeRes, error)
+	GetPayees(ctx context.Context, userID string) ([]PayeeRes, error)
+	Update(ctx context.Context, userID, id string, req *PayeeReq) error
+	Delete(ctx context.Context, userID, id string) error
+	CreateAlias(ctx context.Context, userID, id string, req *AliasReq) (*AliasRes, error)
+	DeleteAlias(ctx context.Context, userID, id, aliasID string) error
+	Merge(ctx context.Context, userID, id string, req *MergeReq) (*MergeRes, error)
+	GetTotals(ctx context.Context, userID string, filters *PayeeTotalsFilters) ([]PayeeTotalRes, error)
+}
+
+type payeeService struct {
+	repo       Repository
+	categories category.Repository
+	uow        pgstore.UnitOfWork
+}
+
+func NewPayeeService(repo Repository, categories category.Repository, uow pgstore.UnitOfWork) Service {
+	return &payeeService{
+		repo:       repo,
+		categories: categories,
+		uow:        uow,
+	}
+}
+
+func (s *payeeService) Create(ctx context.Context, userID string, req *PayeeReq) (*PayeeRes, error) {
+	userUUID := uuid.MustParse(userID)
+
+	defaultCategory, err := s.defaultCategory(ctx, userUUID, req.DefaultCategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DefaultCategoryID == "" {
+		suggested, ok, err := s.repo.SuggestCategory(ctx, userUUID, req.Name)
+		if err != nil {
+			return nil, fmt.Errorf("service suggest category: %w", err)
+		}
+		defaultCategory = pgtype.UUID{Bytes: suggested, Valid: ok}
+	}
+
+	record, err := s.repo.Create(ctx, db.CreatePayeeParams{
+		UserID:            userUUID,
+		Name:              req.Name,
+		DefaultCategoryID: defaultCategory,
+	})
+	if err != nil {
+		if errors.Is(err, ErrDuplicatedPayee) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service create payee: %w", err)
+	}
+
+	res := PayeeToResponse(record)
+	return &res, nil
+}
+
+func (s *payeeService) GetPayee(ctx context.Context, userID, id string) (*PayeeRes, error) {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrPayeeNotFound
+	}
+
+	record, err := s.repo.GetPayee(ctx, idUUID, uuid.MustParse(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	aliases, err := s.repo.GetAliases(ctx, record.ID)
+	if err != nil {
+		return nil, fmt.Errorf("service get payee: %w", err)
+	}
+
+	res := PayeeToResponse(record)
+	for _, alias := range aliases {
+		res.Aliases = append(res.Aliases, AliasToResponse(alias))
+	}
+
+	return &res, nil
+}
+
+func (s *payeeService) GetPayees(ctx context.Context, userID string) ([]PayeeRes, error) {
+	records, err := s.repo.GetPayees(ctx, uuid.MustParse(userID))
+	if err != nil {
+		return nil, fmt.Errorf("service get payees: %w", err)
+	}
+
+	res := make([]PayeeRes, len(records))
+	for i, record := range records {
+		res[i] = PayeeToResponse(record)
+	}
+
+	return res, nil
+}
+
+func (s *payeeService) Update(ctx context.Context, userID, id string, req *PayeeReq) error {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrPayeeNotFound
+	}
+
+	userUUID := uuid.MustParse(userID)
+
+	defaultCategory, err := s.defaultCategory(ctx, userUUID, req.DefaultCategoryID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, db.UpdatePayeeParams{
+		ID:                idUUID,
+		Name:              req.Name,
+		DefaultCategoryID: defaultCategory,
+		UserID:            userUUID,
+	})
+}
+
+func (s *payeeService) Delete(ctx context.Context, userID, id string) error {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrPayeeNotFound
+	}
+
+	return s.repo.Delete(ctx, idUUID, uuid.MustParse(userID))
+}
+
+func (s *payeeService) CreateAlias(ctx context.Context, userID, id string, req *AliasReq) (*AliasRes, error) {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrPayeeNotFound
+	}
+
+	userUUID := uuid.MustParse(userID)
+
+	if _, err := s.repo.GetPayee(ctx, idUUID, userUUID); err != nil {
+		return nil, err
+	}
+
+	record, err := s.repo.CreateAlias(ctx, db.CreatePayeeAliasParams{
+		PayeeID: idUUID,
+		UserID:  userUUID,
+		Pattern: req.Pattern,
+	})
+	if err != nil {
+		if errors.Is(err, ErrDuplicatedAlias) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service create alias: %w", err)
+	}
+
+	res := AliasToResponse(record)
+	return &res, nil
+}
+
+func (s *payeeService) DeleteAlias(ctx context.Context, userID, id, aliasID string) error {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrAliasNotFound
+	}
+
+	aliasUUID, err := uuid.Parse(aliasID)
+	if err != nil {
+		return ErrAliasNotFound
+	}
+
+	return s.repo.DeleteAlias(ctx, aliasUUID, idUUID, uuid.MustParse(userID))
+}
+
+// Merge folds the source payees into the payee id. Their transactions and
+// aliases move to it and their names become aliases, so descriptions that
+// matched them keep resolving to the same payee.
+func (s *payeeService) Merge(ctx context.Context, userID, id string, req *MergeReq) (*MergeRes, error) {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrPayeeNotFound
+	}
+
+	userUUID := uuid.MustParse(userID)
+
+	sources := make([]uuid.UUID, 0, len(req.SourceIDs))
+	seen := make(map[uuid.UUID]bool, len(req.SourceIDs))
+	for _, sourceID := range req.SourceIDs {
+		source := uuid.MustParse(sourceID)
+		if source == idUUID {
+			return nil, ErrMergeIntoItself
+		}
+		if !seen[source] {
+			seen[source] = true
+			sources = append(sources, source)
+		}
+	}
+
+	var target *db.Payee
+	var moved int64
+
+	err = s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+
+		if target, err = repo.GetPayee(ctx, idUUID, userUUID); err != nil {
+			return err
+		}
+
+		for _, source := range sources {
+			if _, err := repo.GetPayee(ctx, source, userUUID); err != nil {
+				if errors.Is(err, ErrPayeeNotFound) {
+					return ErrInvalidSource
+				}
+				return err
+			}
+		}
+
+		moved, err = repo.Merge(ctx, userUUID, idUUID, sources)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrPayeeNotFound) || errors.Is(err, ErrInvalidSource) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service merge payees: %w", err)
+	}
+
+	aliases, err := s.repo.GetAliases(ctx, idUUID)
+	if err != nil {
+		return nil, fmt.Errorf("service merge payees: %w", err)
+	}
+
+	res := &MergeRes{Payee: PayeeToResponse(target), Transactions: moved}
+	for _, alias := range aliases {
+		res.Payee.Aliases = append(res.Payee.Aliases, AliasToResponse(alias))
+	}
+
+	return res, nil
+}
+
+// GetTotals sums the income and expenses of each payee. It expects filters
+// that already passed Valid.
+func (s *payeeService) GetTotals(ctx context.Context, userID string, filters *PayeeTotalsFilters) ([]PayeeTotalRes, error) {
+	arg := db.GetPayeeTotalsParams{UserID: uuid.MustParse(userID)}
+
+	if filters.StartDate != nil {
+		date, _ := time.Parse("2006-01-02", *filters.StartDate)
+		arg.StartDate = pgtype.Date{Time: date, Valid: true}
+	}
+
+	if filters.EndDate != nil {
+		date, _ := time.Parse("2006-01-02", *filters.EndDate)
+		arg.EndDate = pgtype.Date{Time: date, Valid: true}
+	}
+
+	records, err := s.repo.GetTotals(ctx, arg)
+	if err != nil {
+		if errors.Is(err, exchangerate.ErrMissingRate) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service get totals: %w", err)
+	}
+
+	res := make([]PayeeTotalRes, len(records))
+	for i, record := range records {
+		income, expense := money.FromCents(record.Income), money.FromCents(record.Expense)
+		res[i] = PayeeTotalRes{
+			ID:           record.ID.String(),
+			Name:         record.Name,
+			Transactions: record.Transactions,
+			Income:       income,
+			Expense:      expense,
+			Net:          income - expense,
+		}
+	}
+
+	return res, nil
+}
+
+// defaultCategory parses an optional category id and checks that it belongs
+// to the user.
+func (s *payeeService) defaultCategory(ctx context.Context, userID uuid.UUID, id string) (pgtype.UUID, error) {
+	if id == "" {
+		return pgtype.UUID{}, nil
+	}
+
+	categoryUUID := uuid.MustParse(id)
+	if _, err := s.categories.GetCategory(ctx, categoryUUID, userID); err != nil {
+		if errors.Is(err, category.ErrCategoryNotFound) {
+			return pgtype.UUID{}, ErrInvalidCategory
+		}
+		return pgtype.UUID{}, fmt.Errorf("service check category: %w", err)
+	}
+
+	return pgtype.UUID{Bytes: categoryUUID, Valid: true}, nil
+}
@@ -0,0 +1,120 @@
+package reconciliation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+)
+
+const maxClearIDs = 500
+
+// ReconciliationReq opens a reconciliation of an account against a bank
+// statement. The balance follows the sign of the account balance, so the
+// amount owed on a credit card is negative.
+type ReconciliationReq struct {
+	AccountID        string      `json:"account_id"`
+	StatementDate    string      `json:"statement_date"` // formato: YYYY-MM-DD
+	StatementBalance money.Money `json:"statement_balance"`
+}
+
+func (r *ReconciliationReq) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.UUID(r.AccountID), "account_id", "this field must be a valid UUID")
+	eval.CheckField(validator.Date(r.StatementDate), "statement_date", "this field must be a date in the format YYYY-MM-DD")
+
+	return eval
+}
+
+type ReconciliationFilters struct {
+	AccountID *string `json:"account_id,omitempty"`
+}
+
+func (f *ReconciliationFilters) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	if f.AccountID != nil {
+		eval.CheckField(validator.UUID(*f.AccountID), "account_id", "this field must be a valid UUID")
+	}
+
+	return eval
+}
+
+// ClearReq marks transactions of the reconciled account as cleared, or
+// back as uncleared.
+type ClearReq struct {
+	TransactionIDs []string `json:"transaction_ids"`
+	Cleared        bool     `json:"cleared"`
+}
+
+func (r *ClearReq) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(len(r.TransactionIDs) > 0 && len(r.TransactionIDs) <= maxClearIDs, "transaction_ids", fmt.Sprintf("this field must have between 1 and %d ids", maxClearIDs))
+
+	for i, id := range r.TransactionIDs {
+		eval.CheckField(validator.UUID(id), fmt.Sprintf("transaction_ids[%d]", i), "this field must be a valid UUID")
+	}
+
+	return eval
+}
+
+// ReconciliationRes shows how far the cleared balance is from the statement.
+// Once finalized both balances are the statement balance.
+type ReconciliationRes struct {
+	ID               string      `json:"id"`
+	AccountID        string      `json:"account_id"`
+	StatementDate    string      `json:"statement_date"`
+	StatementBalance money.Money `json:"statement_balance"`
+	ClearedBalance   money.Money `json:"cleared_balance"`
+	Difference       money.Money `json:"difference"` // statement_balance - cleared_balance
+	Status           string      `json:"status"`     // "open" ou "finalized"
+	FinalizedAt      *time.Time  `json:"finalized_at,omitempty"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+func ReconciliationToResponse(record *db.Reconciliation, cleared money.Money) ReconciliationRes {
+	res := ReconciliationRes{
+		ID:               record.ID.String(),
+		AccountID:        record.AccountID.String(),
+		StatementDate:    record.StatementDate.Time.Format("2006-01-02"),
+		StatementBalance: record.StatementBalance,
+		ClearedBalance:   cleared,
+		Difference:       record.StatementBalance - cleared,
+		Status:           "open",
+		CreatedAt:        record.CreatedAt.Time,
+		UpdatedAt:        record.UpdatedAt.Time,
+	}
+
+	if record.FinalizedAt.Valid {
+		res.Status = "finalized"
+		res.FinalizedAt = &record.FinalizedAt.Time
+	}
+
+	return res
+}
+
+type TransactionRes struct {
+	ID          string      `json:"id"`
+	Date        string      `json:"date"`
+	Description string      `json:"description"`
+	Amount      money.Money `json:"amount"`
+	Type        string      `json:"type"`
+	Status      string      `json:"status"` // "uncleared", "cleared" ou "reconciled"
+}
+
+func TransactionToResponse(t *db.Transaction) TransactionRes {
+	return TransactionRes{
+		ID:          t.ID.String(),
+		Date:        t.Date.Time.Format("2006-01-02"),
+		Description: t.Description,
+		Amount:      t.Amount,
+		Type:        string(t.Type),
+		Status:      string(t.ClearingStatus),
+	}
+}
@@ -0,0 +1,205 @@
+package reconciliation
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EduardoMark/my-finance-api/internal/middlewares"
+	"github.com/EduardoMark/my-finance-api/pkg/httputils"
+	"github.com/EduardoMark/my-finance-api/pkg/token"
+	"github.com/go-chi/chi/v5"
+)
+
+type ReconciliationHandler struct {
+	svc   Service
+	token *token.TokenManager
+}
+
+func NewReconciliationHandler(svc Service, token *token.TokenManager) ReconciliationHandler {
+	return ReconciliationHandler{
+		svc:   svc,
+		token: token,
+	}
+}
+
+func (h *ReconciliationHandler) RegisterReconciliationRoutes(r chi.Router) {
+	r.Route("/reconciliations", func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(h.token))
+
+		r.Post("/", h.Create)
+		r.Get("/", h.GetReconciliations)
+		r.Get("/{id}", h.GetReconciliation)
+		r.Delete("/{id}", h.Delete)
+		r.Get("/{id}/transactions", h.GetTransactions)
+		r.Put("/{id}/transactions", h.SetCleared)
+		r.Post("/{id}/finalize", h.Finalize)
+	})
+}
+
+func (h *ReconciliationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*ReconciliationReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	res, err := h.svc.Create(ctx, userID, data)
+	if err != nil {
+		writeReconciliationError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusCreated, res)
+}
+
+func (h *ReconciliationHandler) GetReconciliations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	filters := &ReconciliationFilters{}
+	if value := r.URL.Query().Get("account_id"); value != "" {
+		filters.AccountID = &value
+	}
+
+	if problems := filters.Valid(ctx); len(problems) > 0 {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	res, err := h.svc.GetReconciliations(ctx, userID, filters)
+	if err != nil {
+		writeReconciliationError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *ReconciliationHandler) GetReconciliation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	res, err := h.svc.GetReconciliation(ctx, userID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeReconciliationError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+// GetTransactions lists what an open reconciliation can still clear, or
+// what a finalized one reconciled.
+func (h *ReconciliationHandler) GetTransactions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	res, err := h.svc.GetTransactions(ctx, userID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeReconciliationError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *ReconciliationHandler) SetCleared(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*ClearReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	res, err := h.svc.SetCleared(ctx, userID, chi.URLParam(r, "id"), data)
+	if err != nil {
+		writeReconciliationError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *ReconciliationHandler) Finalize(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	res, err := h.svc.Finalize(ctx, userID, chi.URLParam(r, "id"))
+	if err != nil {
+		writeReconciliationError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *ReconciliationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	if err := h.svc.Delete(ctx, userID, chi.URLParam(r, "id")); err != nil {
+		writeReconciliationError(w, r, err)
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+func writeReconciliationError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrReconciliationNotFound):
+		_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrOpenReconciliation), errors.Is(err, ErrFinalized), errors.Is(err, ErrUnbalanced):
+		_ = httputils.EncodeJson(w, r, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrInvalidAccount):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"account_id": err.Error()})
+	case errors.Is(err, ErrStatementDate):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"statement_date": err.Error()})
+	case errors.Is(err, ErrInvalidTransactions):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"transaction_ids": err.Error()})
+	default:
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
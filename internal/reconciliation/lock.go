@@ -0,0 +1,23 @@
+package reconciliation
+
+import (
+	"errors"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+)
+
+// ErrLocked is returned by every change to a reconciled transaction. The
+// transaction must be unlocked first, which turns it back into cleared.
+var ErrLocked = errors.New("transaction is reconciled, unlock it before changing it")
+
+// CheckUnlocked fails with ErrLocked when any of the transactions is
+// reconciled.
+func CheckUnlocked(transactions ...*db.Transaction) error {
+	for _, t := range transactions {
+		if t.ClearingStatus == db.ClearingStatusReconciled {
+			return ErrLocked
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package reconciliation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Repository interface {
+	Create(ctx context.Context, arg db.CreateReconciliationParams) (*db.Reconciliation, error)
+	GetReconciliation(ctx context.Context, id, userID uuid.UUID) (*db.Reconciliation, error)
+	GetReconciliationForUpdate(ctx context.Context, id, userID uuid.UUID) (*db.Reconciliation, error)
+	GetReconciliations(ctx context.Context, arg db.GetReconciliationsParams) ([]*db.Reconciliation, error)
+	GetLastReconciledDate(ctx context.Context, accountID uuid.UUID) (pgtype.Date, error)
+	GetClearedBalance(ctx context.Context, accountID uuid.UUID, statementDate pgtype.Date) (money.Money, error)
+	GetTransactions(ctx context.Context, id uuid.UUID) ([]*db.Transaction, error)
+	SetClearingStatus(ctx context.Context, accountID uuid.UUID, ids []uuid.UUID, status db.ClearingStatus) (int64, error)
+	Reconcile(ctx context.Context, record *db.Reconciliation) (int64, error)
+	Finalize(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+	WithTx(q *db.Queries) Repository
+}
+
+type reconciliationRepository struct {
+	db *db.Queries
+}
+
+func NewReconciliationRepository(db *db.Queries) Repository {
+	return &reconciliationRepository{db: db}
+}
+
+var ErrReconciliationNotFound = errors.New("reconciliation not found")
+var ErrInvalidAccount = errors.New("account not found")
+var ErrOpenReconciliation = errors.New("the account already has an open reconciliation")
+var ErrStatementDate = errors.New("statement_date cannot be before the last reconciled statement of the account")
+var ErrFinalized = errors.New("reconciliation is already finalized")
+var ErrUnbalanced = errors.New("the cleared balance must match the statement balance")
+var ErrInvalidTransactions = errors.New("transactions must belong to the account and not be reconciled")
+
+func (r *reconciliationRepository) Create(ctx context.Context, arg db.CreateReconciliationParams) (*db.Reconciliation, error) {
+	var pgErr *pgconn.PgError
+
+	record, err := r.db.CreateReconciliation(ctx, arg)
+	if err != nil {
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrOpenReconciliation
+		}
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (r *reconciliationRepository) GetReconciliation(ctx context.Context, id, userID uuid.UUID) (*db.Reconciliation, error) {
+	record, err := r.db.GetReconciliation(ctx, db.GetReconciliationParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReconciliationNotFound
+		}
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (r *reconciliationRepository) GetReconciliationForUpdate(ctx context.Context, id, userID uuid.UUID) (*db.Reconciliation, error) {
+	record, err := r.db.GetReconciliationForUpdate(ctx, db.GetReconciliationForUpdateParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReconciliationNotFound
+		}
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (r *reconciliationRepository) GetReconciliations(ctx context.Context, arg db.GetReconciliationsParams) ([]*db.Reconciliation, error) {
+	return r.db.GetReconciliations(ctx, arg)
+}
+
+// GetLastReconciledDate returns an invalid date while the account has no
+// finalized reconciliation.
+func (r *reconciliationRepository) GetLastReconciledDate(ctx context.Context, accountID uuid.UUID) (pgtype.Date, error) {
+	return r.db.GetLastReconciledDate(ctx, accountID)
+}
+
+func (r *reconciliationRepository) GetClearedBalance(ctx context.Context, accountID uuid.UUID, statementDate pgtype.Date) (money.Money, error) {
+	balance, err := r.db.GetClearedBalance(ctx, db.GetClearedBalanceParams{
+		StatementDate: statementDate,
+		AccountID:     accountID,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return money.FromCents(balance), nil
+}
+
+func (r *reconciliationRepository) GetTransactions(ctx context.Context, id uuid.UUID) ([]*db.Transaction, error) {
+	return r.db.GetReconciliationTransactions(ctx, id)
+}
+
+// SetClearingStatus reports how many of the transactions changed; those of
+// other accounts and reconciled ones are skipped.
+func (r *reconciliationRepository) SetClearingStatus(ctx context.Context, accountID uuid.UUID, ids []uuid.UUID, status db.ClearingStatus) (int64, error) {
+	return r.db.SetAccountClearingStatus(ctx, db.SetAccountClearingStatusParams{
+		ClearingStatus: status,
+		AccountID:      accountID,
+		Ids:            ids,
+	})
+}
+
+// Reconcile marks the cleared transactions of the account up to the
+// statement date as reconciled by record.
+func (r *reconciliationRepository) Reconcile(ctx context.Context, record *db.Reconciliation) (int64, error) {
+	return r.db.ReconcileTransactions(ctx, db.ReconcileTransactionsParams{
+		ReconciliationID: pgtype.UUID{Bytes: record.ID, Valid: true},
+		AccountID:        record.AccountID,
+		StatementDate:    record.StatementDate,
+	})
+}
+
+func (r *reconciliationRepository) Finalize(ctx context.Context, id uuid.UUID) error {
+	return r.db.FinalizeReconciliation(ctx, id)
+}
+
+func (r *reconciliationRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	return r.db.DeleteReconciliation(ctx, db.DeleteReconciliationParams{ID: id, UserID: userID})
+}
+
+func (r *reconciliationRepository) WithTx(q *db.Queries) Repository {
+	return &reconciliationRepository{db: q}
+}
@@ -0,0 +1,269 @@
+package reconciliation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/account"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Service interface {
+	Create(ctx context.Context, userID string, req *ReconciliationReq) (*ReconciliationRes, error)
+	GetReconciliation(ctx context.Context, userID, id string) (*ReconciliationRes, error)
+	GetReconciliations(ctx context.Context, userID string, filters *ReconciliationFilters) ([]ReconciliationRes, error)
+	GetTransactions(ctx context.Context, userID, id string) ([]TransactionRes, error)
+	SetCleared(ctx context.Context, userID, id string, req *ClearReq) (*ReconciliationRes, error)
+	Finalize(ctx context.Context, userID, id string) (*ReconciliationRes, error)
+	Delete(ctx context.Context, userID, id string) error
+}
+
+type reconciliationService struct {
+	repo     Repository
+	accounts account.Repository
+	uow      pgstore.UnitOfWork
+}
+
+func NewReconciliationService(repo Repository, accounts account.Repository, uow pgstore.UnitOfWork) Service {
+	return &reconciliationService{
+		repo:     repo,
+		accounts: accounts,
+		uow:      uow,
+	}
+}
+
+func (s *reconciliationService) Create(ctx context.Context, userID string, req *ReconciliationReq) (*ReconciliationRes, error) {
+	userUUID := uuid.MustParse(userID)
+	accountUUID := uuid.MustParse(req.AccountID)
+
+	if _, err := s.accounts.GetAccount(ctx, accountUUID, userUUID); err != nil {
+		if errors.Is(err, account.ErrAccountNotFound) {
+			return nil, ErrInvalidAccount
+		}
+		return nil, fmt.Errorf("service create reconciliation: %w", err)
+	}
+
+	statementDate, _ := time.Parse("2006-01-02", req.StatementDate)
+
+	last, err := s.repo.GetLastReconciledDate(ctx, accountUUID)
+	if err != nil {
+		return nil, fmt.Errorf("service create reconciliation: %w", err)
+	}
+
+	if last.Valid && statementDate.Before(last.Time) {
+		return nil, ErrStatementDate
+	}
+
+	record, err := s.repo.Create(ctx, db.CreateReconciliationParams{
+		UserID:           userUUID,
+		AccountID:        accountUUID,
+		StatementDate:    pgtype.Date{Time: statementDate, Valid: true},
+		StatementBalance: req.StatementBalance,
+	})
+	if err != nil {
+		if errors.Is(err, ErrOpenReconciliation) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("service create reconciliation: %w", err)
+	}
+
+	return s.response(ctx, s.repo, record)
+}
+
+func (s *reconciliationService) GetReconciliation(ctx context.Context, userID, id string) (*ReconciliationRes, error) {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrReconciliationNotFound
+	}
+
+	record, err := s.repo.GetReconciliation(ctx, idUUID, uuid.MustParse(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.response(ctx, s.repo, record)
+}
+
+func (s *reconciliationService) GetReconciliations(ctx context.Context, userID string, filters *ReconciliationFilters) ([]ReconciliationRes, error) {
+	params := db.GetReconciliationsParams{UserID: uuid.MustParse(userID)}
+	if filters.AccountID != nil {
+		params.AccountID = pgtype.UUID{Bytes: uuid.MustParse(*filters.AccountID), Valid: true}
+	}
+
+	records, err := s.repo.GetReconciliations(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("service get reconciliations: %w", err)
+	}
+
+	res := make([]ReconciliationRes, len(records))
+	for i, record := range records {
+		item, err := s.response(ctx, s.repo, record)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = *item
+	}
+
+	return res, nil
+}
+
+func (s *reconciliationService) GetTransactions(ctx context.Context, userID, id string) ([]TransactionRes, error) {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrReconciliationNotFound
+	}
+
+	if _, err := s.repo.GetReconciliation(ctx, idUUID, uuid.MustParse(userID)); err != nil {
+		return nil, err
+	}
+
+	records, err := s.repo.GetTransactions(ctx, idUUID)
+	if err != nil {
+		return nil, fmt.Errorf("service get reconciliation transactions: %w", err)
+	}
+
+	res := make([]TransactionRes, len(records))
+	for i, record := range records {
+		res[i] = TransactionToResponse(record)
+	}
+
+	return res, nil
+}
+
+// SetCleared changes every transaction sent or none of them.
+func (s *reconciliationService) SetCleared(ctx context.Context, userID, id string, req *ClearReq) (*ReconciliationRes, error) {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrReconciliationNotFound
+	}
+
+	var ids []uuid.UUID
+	for _, value := range req.TransactionIDs {
+		if parsed := uuid.MustParse(value); !slices.Contains(ids, parsed) {
+			ids = append(ids, parsed)
+		}
+	}
+
+	status := db.ClearingStatusUncleared
+	if req.Cleared {
+		status = db.ClearingStatusCleared
+	}
+
+	var res *ReconciliationRes
+	err = s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+
+		record, err := repo.GetReconciliationForUpdate(ctx, idUUID, uuid.MustParse(userID))
+		if err != nil {
+			return err
+		}
+
+		if record.FinalizedAt.Valid {
+			return ErrFinalized
+		}
+
+		changed, err := repo.SetClearingStatus(ctx, record.AccountID, ids, status)
+		if err != nil {
+			return err
+		}
+
+		if changed != int64(len(ids)) {
+			return ErrInvalidTransactions
+		}
+
+		res, err = s.response(ctx, repo, record)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service set cleared: %w", err)
+	}
+
+	return res, nil
+}
+
+// Finalize requires the cleared balance to match the statement, then locks
+// the cleared transactions up to the statement date as reconciled.
+func (s *reconciliationService) Finalize(ctx context.Context, userID, id string) (*ReconciliationRes, error) {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrReconciliationNotFound
+	}
+
+	userUUID := uuid.MustParse(userID)
+
+	err = s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+
+		record, err := repo.GetReconciliationForUpdate(ctx, idUUID, userUUID)
+		if err != nil {
+			return err
+		}
+
+		if record.FinalizedAt.Valid {
+			return ErrFinalized
+		}
+
+		cleared, err := repo.GetClearedBalance(ctx, record.AccountID, record.StatementDate)
+		if err != nil {
+			return err
+		}
+
+		if cleared != record.StatementBalance {
+			return ErrUnbalanced
+		}
+
+		if _, err := repo.Reconcile(ctx, record); err != nil {
+			return err
+		}
+
+		return repo.Finalize(ctx, record.ID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service finalize reconciliation: %w", err)
+	}
+
+	return s.GetReconciliation(ctx, userID, id)
+}
+
+// Delete discards an open reconciliation. The cleared marks it set are kept.
+func (s *reconciliationService) Delete(ctx context.Context, userID, id string) error {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrReconciliationNotFound
+	}
+
+	userUUID := uuid.MustParse(userID)
+
+	record, err := s.repo.GetReconciliation(ctx, idUUID, userUUID)
+	if err != nil {
+		return err
+	}
+
+	if record.FinalizedAt.Valid {
+		return ErrFinalized
+	}
+
+	return s.repo.Delete(ctx, idUUID, userUUID)
+}
+
+// response computes the cleared balance of open reconciliations; a
+// finalized one matched its statement when it was closed.
+func (s *reconciliationService) response(ctx context.Context, repo Repository, record *db.Reconciliation) (*ReconciliationRes, error) {
+	cleared := record.StatementBalance
+
+	if !record.FinalizedAt.Valid {
+		var err error
+		if cleared, err = repo.GetClearedBalance(ctx, record.AccountID, record.StatementDate); err != nil {
+			return nil, fmt.Errorf("service cleared balance: %w", err)
+		}
+	}
+
+	res := ReconciliationToResponse(record, cleared)
+	return &res, nil
+}
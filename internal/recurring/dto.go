@@ -0,0 +1,186 @@
+package recurring
+
+import (
+	"context"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+)
+
+var frequencyOptions = map[string]bool{
+	string(db.RecurrenceFrequencyDaily):           true,
+	string(db.RecurrenceFrequencyWeekly):          true,
+	string(db.RecurrenceFrequencyMonthly):         true,
+	string(db.RecurrenceFrequencyLastBusinessDay): true,
+	string(db.RecurrenceFrequencyYearly):          true,
+}
+
+type RecurringCreateRequest struct {
+	Description    string      `json:"description"`
+	Amount         money.Money `json:"amount"`
+	Type           string      `json:"type"` // "income" ou "expense"
+	AccountID      string      `json:"account_id"`
+	CategoryID     string      `json:"category_id"`
+	Frequency      string      `json:"frequency"`       // daily, weekly, monthly, last_business_day, yearly
+	Interval       int32       `json:"interval"`        // a cada N dias/semanas/meses/anos, padrão 1
+	DayOfMonth     *int32      `json:"day_of_month"`    // apenas monthly, padrão: dia de start_date
+	StartDate      string      `json:"start_date"`      // formato: YYYY-MM-DD
+	EndDate        *string     `json:"end_date"`        // formato: YYYY-MM-DD
+	MaxOccurrences *int32      `json:"max_occurrences"` // encerra após N ocorrências
+}
+
+func (r *RecurringCreateRequest) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(r.Description), "description", "this field cannot be empty")
+	eval.CheckField(r.Amount > 0, "amount", "this field must be greater than 0")
+	eval.CheckField(r.Type == "income" || r.Type == "expense", "type", "this field must be 'income' or 'expense'")
+	eval.CheckField(validator.UUID(r.AccountID), "account_id", "this field must be a valid UUID")
+	eval.CheckField(validator.UUID(r.CategoryID), "category_id", "this field must be a valid UUID")
+	eval.CheckField(frequencyOptions[r.Frequency], "frequency", "this field must be 'daily', 'weekly', 'monthly', 'last_business_day' or 'yearly'")
+	eval.CheckField(r.Interval >= 0, "interval", "this field cannot be negative")
+	eval.CheckField(validator.Date(r.StartDate), "start_date", "this field must be a date in the format YYYY-MM-DD")
+
+	if r.DayOfMonth != nil {
+		eval.CheckField(r.Frequency == string(db.RecurrenceFrequencyMonthly), "day_of_month", "this field is only allowed for the 'monthly' frequency")
+		eval.CheckField(*r.DayOfMonth >= 1 && *r.DayOfMonth <= 31, "day_of_month", "this field must be between 1 and 31")
+	}
+
+	if r.EndDate != nil {
+		eval.CheckField(validator.Date(*r.EndDate), "end_date", "this field must be a date in the format YYYY-MM-DD")
+		eval.CheckField(*r.EndDate >= r.StartDate, "end_date", "this field cannot be before start_date")
+	}
+
+	if r.MaxOccurrences != nil {
+		eval.CheckField(*r.MaxOccurrences > 0, "max_occurrences", "this field must be greater than 0")
+	}
+
+	return eval
+}
+
+// RecurringUpdateRequest only changes future occurrences. Sending end_date as
+// "" or max_occurrences as 0 removes that end condition.
+type RecurringUpdateRequest struct {
+	Description    *string      `json:"description,omitempty"`
+	Amount         *money.Money `json:"amount,omitempty"`
+	AccountID      *string      `json:"account_id,omitempty"`
+	CategoryID     *string      `json:"category_id,omitempty"`
+	EndDate        *string      `json:"end_date,omitempty"`
+	MaxOccurrences *int32       `json:"max_occurrences,omitempty"`
+}
+
+func (r *RecurringUpdateRequest) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	hasAtLeastOneField := r.Description != nil || r.Amount != nil || r.AccountID != nil ||
+		r.CategoryID != nil || r.EndDate != nil || r.MaxOccurrences != nil
+
+	eval.CheckField(hasAtLeastOneField, "fields", "at least one field must be sent to update")
+
+	if r.Description != nil {
+		eval.CheckField(validator.NotBlank(*r.Description), "description", "this field cannot be empty")
+	}
+
+	if r.Amount != nil {
+		eval.CheckField(*r.Amount > 0, "amount", "this field must be greater than 0")
+	}
+
+	if r.AccountID != nil {
+		eval.CheckField(validator.UUID(*r.AccountID), "account_id", "this field must be a valid UUID")
+	}
+
+	if r.CategoryID != nil {
+		eval.CheckField(validator.UUID(*r.CategoryID), "category_id", "this field must be a valid UUID")
+	}
+
+	if r.EndDate != nil && *r.EndDate != "" {
+		eval.CheckField(validator.Date(*r.EndDate), "end_date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	if r.MaxOccurrences != nil {
+		eval.CheckField(*r.MaxOccurrences >= 0, "max_occurrences", "this field cannot be negative")
+	}
+
+	return eval
+}
+
+// OccurrenceUpdateRequest overrides a single future occurrence.
+type OccurrenceUpdateRequest struct {
+	Description *string      `json:"description,omitempty"`
+	Amount      *money.Money `json:"amount,omitempty"`
+}
+
+func (r *OccurrenceUpdateRequest) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(r.Description != nil || r.Amount != nil, "fields", "at least one field must be sent to update")
+
+	if r.Description != nil {
+		eval.CheckField(validator.NotBlank(*r.Description), "description", "this field cannot be empty")
+	}
+
+	if r.Amount != nil {
+		eval.CheckField(*r.Amount > 0, "amount", "this field must be greater than 0")
+	}
+
+	return eval
+}
+
+type RecurringResponse struct {
+	ID               string      `json:"id"`
+	Description      string      `json:"description"`
+	Amount           money.Money `json:"amount"`
+	Type             string      `json:"type"`
+	AccountID        string      `json:"account_id"`
+	CategoryID       string      `json:"category_id"`
+	Frequency        string      `json:"frequency"`
+	Interval         int32       `json:"interval"`
+	DayOfMonth       *int32      `json:"day_of_month,omitempty"`
+	StartDate        string      `json:"start_date"`
+	EndDate          *string     `json:"end_date,omitempty"`
+	MaxOccurrences   *int32      `json:"max_occurrences,omitempty"`
+	OccurrencesCount int32       `json:"occurrences_count"`
+	NextOccurrence   *string     `json:"next_occurrence"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+func RecurringToResponse(r *db.RecurringTransaction) RecurringResponse {
+	response := RecurringResponse{
+		ID:               r.ID.String(),
+		Description:      r.Description,
+		Amount:           r.Amount,
+		Type:             string(r.Type),
+		AccountID:        r.AccountID.String(),
+		CategoryID:       r.CategoryID.String(),
+		Frequency:        string(r.Frequency),
+		Interval:         r.IntervalCount,
+		StartDate:        r.StartDate.Time.Format("2006-01-02"),
+		OccurrencesCount: r.OccurrencesCount,
+		CreatedAt:        r.CreatedAt.Time,
+		UpdatedAt:        r.UpdatedAt.Time,
+	}
+
+	if r.DayOfMonth.Valid {
+		response.DayOfMonth = &r.DayOfMonth.Int32
+	}
+
+	if r.EndDate.Valid {
+		endDate := r.EndDate.Time.Format("2006-01-02")
+		response.EndDate = &endDate
+	}
+
+	if r.MaxOccurrences.Valid {
+		response.MaxOccurrences = &r.MaxOccurrences.Int32
+	}
+
+	// A nil next occurrence means the series reached its end condition.
+	if r.NextOccurrence.Valid {
+		next := r.NextOccurrence.Time.Format("2006-01-02")
+		response.NextOccurrence = &next
+	}
+
+	return response
+}
@@ -0,0 +1,231 @@
+package recurring
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EduardoMark/my-finance-api/internal/books"
+	"github.com/EduardoMark/my-finance-api/internal/middlewares"
+	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/EduardoMark/my-finance-api/pkg/httputils"
+	"github.com/EduardoMark/my-finance-api/pkg/token"
+	"github.com/go-chi/chi/v5"
+)
+
+type RecurringHandler struct {
+	svc   Service
+	token *token.TokenManager
+}
+
+func NewRecurringHandler(svc Service, token *token.TokenManager) RecurringHandler {
+	return RecurringHandler{
+		svc:   svc,
+		token: token,
+	}
+}
+
+func (h *RecurringHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/recurring-transactions", func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(h.token))
+
+		r.Post("/", h.Create)
+		r.Get("/", h.GetAllRecurring)
+		r.Get("/{id}", h.GetRecurring)
+		r.Put("/{id}", h.Update)
+		r.Delete("/{id}", h.Delete)
+		r.Post("/{id}/occurrences/{date}/skip", h.SkipOccurrence)
+		r.Put("/{id}/occurrences/{date}", h.UpdateOccurrence)
+	})
+}
+
+func (h *RecurringHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*RecurringCreateRequest](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if err := h.svc.Create(ctx, userID, data); err != nil {
+		if problems := ownershipProblems(err); problems != nil {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+			return
+		}
+		if errors.Is(err, books.ErrClosed) {
+			httputils.Error(w, r, http.StatusConflict, err.Error())
+			return
+		}
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	httputils.Created(w)
+}
+
+func (h *RecurringHandler) GetRecurring(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	record, err := h.svc.GetRecurring(ctx, userID, chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, ErrRecurringNotFound) {
+			httputils.NotFound(w)
+			return
+		}
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, record)
+}
+
+func (h *RecurringHandler) GetAllRecurring(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	records, err := h.svc.GetAllRecurring(ctx, userID)
+	if err != nil {
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, records)
+}
+
+func (h *RecurringHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*RecurringUpdateRequest](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if err := h.svc.Update(ctx, userID, chi.URLParam(r, "id"), *data); err != nil {
+		if problems := ownershipProblems(err); problems != nil {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+			return
+		}
+		if errors.Is(err, ErrRecurringNotFound) {
+			httputils.NotFound(w)
+			return
+		}
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+func (h *RecurringHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	if err := h.svc.Delete(ctx, userID, chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, ErrRecurringNotFound) {
+			httputils.NotFound(w)
+			return
+		}
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+func (h *RecurringHandler) SkipOccurrence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	err := h.svc.SkipOccurrence(ctx, userID, chi.URLParam(r, "id"), chi.URLParam(r, "date"))
+	if err != nil {
+		writeOccurrenceError(w, r, err)
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+func (h *RecurringHandler) UpdateOccurrence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*OccurrenceUpdateRequest](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	err = h.svc.UpdateOccurrence(ctx, userID, chi.URLParam(r, "id"), chi.URLParam(r, "date"), *data)
+	if err != nil {
+		writeOccurrenceError(w, r, err)
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+func writeOccurrenceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrRecurringNotFound):
+		httputils.NotFound(w)
+	case errors.Is(err, ErrInvalidOccurrence):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"date": err.Error()})
+	case errors.Is(err, ErrOccurrenceMaterialized):
+		_ = httputils.EncodeJson(w, r, http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func ownershipProblems(err error) validator.Evaluator {
+	var eval validator.Evaluator
+
+	if errors.Is(err, ErrInvalidAccount) {
+		eval.AddFieldError("account_id", err.Error())
+	}
+
+	if errors.Is(err, ErrInvalidCategory) {
+		eval.AddFieldError("category_id", err.Error())
+	}
+
+	return eval
+}
@@ -0,0 +1,170 @@
+package recurring
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Repository interface {
+	Create(ctx context.Context, args db.CreateRecurringTransactionParams) error
+	GetRecurring(ctx context.Context, id, userID uuid.UUID) (*db.RecurringTransaction, error)
+	GetRecurringForUpdate(ctx context.Context, id, userID uuid.UUID) (*db.RecurringTransaction, error)
+	GetAllRecurring(ctx context.Context, userID uuid.UUID) ([]*db.RecurringTransaction, error)
+	GetDue(ctx context.Context, arg db.GetDueRecurringTransactionsParams) ([]*db.GetDueRecurringTransactionsRow, error)
+	LockDue(ctx context.Context, id uuid.UUID, date pgtype.Date) (*db.RecurringTransaction, error)
+	Update(ctx context.Context, args db.UpdateRecurringTransactionParams) error
+	Advance(ctx context.Context, args db.AdvanceRecurringTransactionParams) error
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+	UpsertException(ctx context.Context, args db.UpsertRecurringExceptionParams) error
+	GetExceptions(ctx context.Context, id uuid.UUID, from pgtype.Date) ([]*db.RecurringException, error)
+	CreateOccurrence(ctx context.Context, args db.CreateRecurringOccurrenceParams) (bool, error)
+	WithTx(q *db.Queries) Repository
+}
+
+type recurringRepository struct {
+	db *db.Queries
+}
+
+func NewRecurringRepo(db *db.Queries) Repository {
+	return &recurringRepository{db: db}
+}
+
+var ErrRecurringNotFound = errors.New("recurring transaction not found")
+var ErrInvalidAccount = errors.New("account not found")
+var ErrInvalidCategory = errors.New("category must be a category of the user with the same type")
+var ErrInvalidOccurrence = errors.New("date is not a pending occurrence of this recurring transaction")
+var ErrOccurrenceMaterialized = errors.New("occurrence already created, change its transaction instead")
+
+// errNotDue is returned by LockDue when the row is no longer due or is being
+// processed by another scheduler.
+var errNotDue = errors.New("recurring transaction not due")
+
+func (r *recurringRepository) Create(ctx context.Context, args db.CreateRecurringTransactionParams) error {
+	if err := r.db.CreateRecurringTransaction(ctx, args); err != nil {
+		return fmt.Errorf("repository create: %w", err)
+	}
+
+	return nil
+}
+
+func (r *recurringRepository) GetRecurring(ctx context.Context, id, userID uuid.UUID) (*db.RecurringTransaction, error) {
+	record, err := r.db.GetRecurringTransaction(ctx, db.GetRecurringTransactionParams{ID: id, UserID: userID})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRecurringNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("repository getRecurring: %w", err)
+	}
+
+	return record, nil
+}
+
+func (r *recurringRepository) GetRecurringForUpdate(ctx context.Context, id, userID uuid.UUID) (*db.RecurringTransaction, error) {
+	record, err := r.db.GetRecurringTransactionForUpdate(ctx, db.GetRecurringTransactionForUpdateParams{ID: id, UserID: userID})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRecurringNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("repository getRecurringForUpdate: %w", err)
+	}
+
+	return record, nil
+}
+
+func (r *recurringRepository) GetAllRecurring(ctx context.Context, userID uuid.UUID) ([]*db.RecurringTransaction, error) {
+	records, err := r.db.GetAllRecurringTransactions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository getAllRecurring: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *recurringRepository) GetDue(ctx context.Context, arg db.GetDueRecurringTransactionsParams) ([]*db.GetDueRecurringTransactionsRow, error) {
+	records, err := r.db.GetDueRecurringTransactions(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("repository getDue: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *recurringRepository) LockDue(ctx context.Context, id uuid.UUID, date pgtype.Date) (*db.RecurringTransaction, error) {
+	record, err := r.db.LockDueRecurringTransaction(ctx, db.LockDueRecurringTransactionParams{ID: id, NextOccurrence: date})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errNotDue
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("repository lockDue: %w", err)
+	}
+
+	return record, nil
+}
+
+func (r *recurringRepository) Update(ctx context.Context, args db.UpdateRecurringTransactionParams) error {
+	if err := r.db.UpdateRecurringTransaction(ctx, args); err != nil {
+		return fmt.Errorf("repository update: %w", err)
+	}
+
+	return nil
+}
+
+func (r *recurringRepository) Advance(ctx context.Context, args db.AdvanceRecurringTransactionParams) error {
+	if err := r.db.AdvanceRecurringTransaction(ctx, args); err != nil {
+		return fmt.Errorf("repository advance: %w", err)
+	}
+
+	return nil
+}
+
+func (r *recurringRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	if _, err := r.GetRecurring(ctx, id, userID); err != nil {
+		return err
+	}
+
+	if err := r.db.DeleteRecurringTransaction(ctx, db.DeleteRecurringTransactionParams{ID: id, UserID: userID}); err != nil {
+		return fmt.Errorf("repository delete: %w", err)
+	}
+
+	return nil
+}
+
+func (r *recurringRepository) UpsertException(ctx context.Context, args db.UpsertRecurringExceptionParams) error {
+	if err := r.db.UpsertRecurringException(ctx, args); err != nil {
+		return fmt.Errorf("repository upsertException: %w", err)
+	}
+
+	return nil
+}
+
+func (r *recurringRepository) GetExceptions(ctx context.Context, id uuid.UUID, from pgtype.Date) ([]*db.RecurringException, error) {
+	records, err := r.db.GetRecurringExceptions(ctx, db.GetRecurringExceptionsParams{RecurringID: id, OccurrenceDate: from})
+	if err != nil {
+		return nil, fmt.Errorf("repository getExceptions: %w", err)
+	}
+
+	return records, nil
+}
+
+// CreateOccurrence reports false when the occurrence already existed.
+func (r *recurringRepository) CreateOccurrence(ctx context.Context, args db.CreateRecurringOccurrenceParams) (bool, error) {
+	rows, err := r.db.CreateRecurringOccurrence(ctx, args)
+	if err != nil {
+		return false, fmt.Errorf("repository createOccurrence: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *recurringRepository) WithTx(q *db.Queries) Repository {
+	return &recurringRepository{db: q}
+}
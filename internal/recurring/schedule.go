@@ -0,0 +1,159 @@
+package recurring
+
+import (
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// rule is the recurrence part of a recurring transaction. Occurrences are
+// always computed from start, never from the previous occurrence, so a
+// "monthly on day 31" series lands on Feb 28 and goes back to Mar 31.
+type rule struct {
+	frequency      db.RecurrenceFrequency
+	interval       int
+	dayOfMonth     int
+	start          time.Time
+	endDate        *time.Time
+	maxOccurrences int
+}
+
+func ruleFromRecord(record *db.RecurringTransaction) rule {
+	r := rule{
+		frequency: record.Frequency,
+		interval:  int(record.IntervalCount),
+		start:     record.StartDate.Time,
+	}
+
+	if record.DayOfMonth.Valid {
+		r.dayOfMonth = int(record.DayOfMonth.Int32)
+	}
+
+	if record.EndDate.Valid {
+		end := record.EndDate.Time
+		r.endDate = &end
+	}
+
+	if record.MaxOccurrences.Valid {
+		r.maxOccurrences = int(record.MaxOccurrences.Int32)
+	}
+
+	return r
+}
+
+// firstOccurrence moves start forward to the first date that matches the
+// rule, e.g. a series starting on Jan 20 that runs on day 10 begins on Feb 10.
+func (r rule) firstOccurrence() time.Time {
+	switch r.frequency {
+	case db.RecurrenceFrequencyMonthly, db.RecurrenceFrequencyLastBusinessDay:
+		first := r.monthly(r.start.Year(), r.start.Month())
+		if first.Before(r.start) {
+			next := r.start.AddDate(0, 0, -r.start.Day()+1).AddDate(0, 1, 0)
+			first = r.monthly(next.Year(), next.Month())
+		}
+		return first
+	default:
+		return r.start
+	}
+}
+
+// occurrence returns the k-th (0-based) occurrence of the series. start must
+// already be the first occurrence.
+func (r rule) occurrence(k int) time.Time {
+	step := k * r.interval
+
+	switch r.frequency {
+	case db.RecurrenceFrequencyDaily:
+		return r.start.AddDate(0, 0, step)
+	case db.RecurrenceFrequencyWeekly:
+		return r.start.AddDate(0, 0, 7*step)
+	case db.RecurrenceFrequencyMonthly, db.RecurrenceFrequencyLastBusinessDay:
+		month := time.Date(r.start.Year(), r.start.Month()+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+		return r.monthly(month.Year(), month.Month())
+	default:
+		year := r.start.Year() + step
+		return clampDay(year, r.start.Month(), r.start.Day())
+	}
+}
+
+// next returns the occurrence that follows count already generated ones, or
+// an invalid date when an end condition was reached.
+func (r rule) next(count int) pgtype.Date {
+	if r.maxOccurrences > 0 && count >= r.maxOccurrences {
+		return pgtype.Date{}
+	}
+
+	date := r.occurrence(count)
+	if r.endDate != nil && date.After(*r.endDate) {
+		return pgtype.Date{}
+	}
+
+	return pgtype.Date{Time: date, Valid: true}
+}
+
+// pending reports whether date is an occurrence that has not been generated
+// yet, given that count occurrences already were.
+func (r rule) pending(count int, date time.Time) bool {
+	for k := count; ; k++ {
+		next := r.next(k)
+		if !next.Valid || next.Time.After(date) {
+			return false
+		}
+		if next.Time.Equal(date) {
+			return true
+		}
+	}
+}
+
+// generated reports whether date is one of the first count occurrences.
+func (r rule) generated(count int, date time.Time) bool {
+	for k := 0; k < count; k++ {
+		occurrence := r.occurrence(k)
+		if occurrence.After(date) {
+			return false
+		}
+		if occurrence.Equal(date) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r rule) monthly(year int, month time.Month) time.Time {
+	if r.frequency == db.RecurrenceFrequencyLastBusinessDay {
+		return lastBusinessDay(year, month)
+	}
+
+	day := r.dayOfMonth
+	if day == 0 {
+		day = r.start.Day()
+	}
+
+	return clampDay(year, month, day)
+}
+
+// clampDay builds a date, using the last day of the month when day does not
+// exist in it.
+func clampDay(year int, month time.Month, day int) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// lastBusinessDay only skips weekends; holidays are not taken into account.
+func lastBusinessDay(year int, month time.Month) time.Time {
+	date := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	for date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		date = date.AddDate(0, 0, -1)
+	}
+	return date
+}
+
+// today is the current date at midnight UTC, the same shape pgx uses for DATE.
+func today() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,138 @@
+package recurring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+)
+
+func date(s string) time.Time {
+	d, _ := time.Parse("2006-01-02", s)
+	return d
+}
+
+func TestClampDay(t *testing.T) {
+	tests := []struct {
+		year  int
+		month time.Month
+		day   int
+		want  string
+	}{
+		{2025, time.January, 31, "2025-01-31"},
+		{2025, time.February, 31, "2025-02-28"},
+		{2024, time.February, 31, "2024-02-29"},
+		{2024, time.February, 29, "2024-02-29"},
+		{2025, time.April, 31, "2025-04-30"},
+		{2025, time.April, 15, "2025-04-15"},
+		{2025, time.December, 31, "2025-12-31"},
+	}
+
+	for _, tt := range tests {
+		if got := clampDay(tt.year, tt.month, tt.day); !got.Equal(date(tt.want)) {
+			t.Errorf("clampDay(%d, %s, %d) = %s, want %s", tt.year, tt.month, tt.day, got.Format("2006-01-02"), tt.want)
+		}
+	}
+}
+
+func TestLastBusinessDay(t *testing.T) {
+	tests := []struct {
+		year  int
+		month time.Month
+		want  string
+	}{
+		{2024, time.January, "2024-01-31"},  // Wednesday
+		{2024, time.February, "2024-02-29"}, // leap year, Thursday
+		{2024, time.March, "2024-03-29"},    // the 31st is a Sunday
+		{2024, time.August, "2024-08-30"},   // the 31st is a Saturday
+		{2025, time.May, "2025-05-30"},      // the 31st is a Saturday
+		{2025, time.February, "2025-02-28"}, // Friday
+	}
+
+	for _, tt := range tests {
+		if got := lastBusinessDay(tt.year, tt.month); !got.Equal(date(tt.want)) {
+			t.Errorf("lastBusinessDay(%d, %s) = %s, want %s", tt.year, tt.month, got.Format("2006-01-02"), tt.want)
+		}
+	}
+}
+
+func TestOccurrence(t *testing.T) {
+	tests := []struct {
+		name string
+		rule rule
+		want []string
+	}{
+		{
+			name: "monthly on day 31 goes back to the 31st after a short month",
+			rule: rule{frequency: db.RecurrenceFrequencyMonthly, interval: 1, dayOfMonth: 31, start: date("2025-01-31")},
+			want: []string{"2025-01-31", "2025-02-28", "2025-03-31", "2025-04-30"},
+		},
+		{
+			name: "monthly from the start day",
+			rule: rule{frequency: db.RecurrenceFrequencyMonthly, interval: 2, start: date("2023-12-30")},
+			want: []string{"2023-12-30", "2024-02-29", "2024-04-30", "2024-06-30"},
+		},
+		{
+			name: "last business day",
+			rule: rule{frequency: db.RecurrenceFrequencyLastBusinessDay, interval: 1, start: date("2024-02-29")},
+			want: []string{"2024-02-29", "2024-03-29", "2024-04-30", "2024-05-31"},
+		},
+		{
+			name: "yearly on Feb 29",
+			rule: rule{frequency: db.RecurrenceFrequencyYearly, interval: 1, start: date("2024-02-29")},
+			want: []string{"2024-02-29", "2025-02-28", "2026-02-28", "2027-02-28", "2028-02-29"},
+		},
+		{
+			name: "weekly",
+			rule: rule{frequency: db.RecurrenceFrequencyWeekly, interval: 2, start: date("2025-12-22")},
+			want: []string{"2025-12-22", "2026-01-05", "2026-01-19"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, want := range tt.want {
+				if got := tt.rule.occurrence(k); !got.Equal(date(want)) {
+					t.Errorf("occurrence(%d) = %s, want %s", k, got.Format("2006-01-02"), want)
+				}
+			}
+		})
+	}
+}
+
+func TestFirstOccurrence(t *testing.T) {
+	tests := []struct {
+		name string
+		rule rule
+		want string
+	}{
+		{
+			name: "day already passed in the start month",
+			rule: rule{frequency: db.RecurrenceFrequencyMonthly, interval: 1, dayOfMonth: 10, start: date("2025-01-20")},
+			want: "2025-02-10",
+		},
+		{
+			name: "day still ahead in the start month",
+			rule: rule{frequency: db.RecurrenceFrequencyMonthly, interval: 1, dayOfMonth: 31, start: date("2025-02-10")},
+			want: "2025-02-28",
+		},
+		{
+			name: "last business day already passed",
+			rule: rule{frequency: db.RecurrenceFrequencyLastBusinessDay, interval: 1, start: date("2024-08-31")},
+			want: "2024-09-30",
+		},
+		{
+			name: "daily starts on start",
+			rule: rule{frequency: db.RecurrenceFrequencyDaily, interval: 1, start: date("2025-03-15")},
+			want: "2025-03-15",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.firstOccurrence(); !got.Equal(date(tt.want)) {
+				t.Errorf("firstOccurrence() = %s, want %s", got.Format("2006-01-02"), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package recurring
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Scheduler periodically turns due recurring transactions into transactions.
+type Scheduler struct {
+	svc   Service
+	every time.Duration
+}
+
+func NewScheduler(svc Service, every time.Duration) *Scheduler {
+	return &Scheduler{
+		svc:   svc,
+		every: every,
+	}
+}
+
+// Run blocks until ctx is done. It runs once right away so occurrences that
+// came due while the API was down are created on startup.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.every)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	created, err := s.svc.MaterializeDue(ctx, today())
+	if err != nil {
+		log.Printf("recurring scheduler: %v", err)
+	}
+
+	if created > 0 {
+		log.Printf("recurring scheduler: created %d transactions", created)
+	}
+}
@@ -0,0 +1,509 @@
+package recurring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/account"
+	"github.com/EduardoMark/my-finance-api/internal/books"
+	"github.com/EduardoMark/my-finance-api/internal/category"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Service interface {
+	Create(ctx context.Context, userID string, dto *RecurringCreateRequest) error
+	GetRecurring(ctx context.Context, userID, id string) (*RecurringResponse, error)
+	GetAllRecurring(ctx context.Context, userID string) ([]RecurringResponse, error)
+	Update(ctx context.Context, userID, id string, dto RecurringUpdateRequest) error
+	Delete(ctx context.Context, userID, id string) error
+	SkipOccurrence(ctx context.Context, userID, id, date string) error
+	UpdateOccurrence(ctx context.Context, userID, id, date string, dto OccurrenceUpdateRequest) error
+	MaterializeDue(ctx context.Context, until time.Time) (int, error)
+}
+
+type recurringService struct {
+	repo       Repository
+	accounts   account.Repository
+	categories category.Repository
+	books      books.Repository
+	uow        pgstore.UnitOfWork
+}
+
+func NewRecurringService(repo Repository, accounts account.Repository, categories category.Repository, books books.Repository, uow pgstore.UnitOfWork) Service {
+	return &recurringService{
+		repo:       repo,
+		accounts:   accounts,
+		categories: categories,
+		books:      books,
+		uow:        uow,
+	}
+}
+
+const (
+	actionSkip   = "skip"
+	actionModify = "modify"
+
+	dueBatchSize = 100
+)
+
+func (s *recurringService) Create(ctx context.Context, userID string, dto *RecurringCreateRequest) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	accountUUID := uuid.MustParse(dto.AccountID)
+	categoryUUID := uuid.MustParse(dto.CategoryID)
+	txType := db.TransactionType(dto.Type)
+
+	if err := s.checkOwnership(ctx, userUUID, accountUUID, categoryUUID, txType); err != nil {
+		return err
+	}
+
+	start, err := time.Parse("2006-01-02", dto.StartDate)
+	if err != nil {
+		return fmt.Errorf("invalid start date: %w", err)
+	}
+
+	r := rule{
+		frequency: db.RecurrenceFrequency(dto.Frequency),
+		interval:  int(dto.Interval),
+		start:     start,
+	}
+
+	if r.interval == 0 {
+		r.interval = 1
+	}
+
+	// Monthly series keep their day even after a clamped month.
+	if r.frequency == db.RecurrenceFrequencyMonthly {
+		r.dayOfMonth = start.Day()
+		if dto.DayOfMonth != nil {
+			r.dayOfMonth = int(*dto.DayOfMonth)
+		}
+	}
+
+	if dto.EndDate != nil {
+		end, err := time.Parse("2006-01-02", *dto.EndDate)
+		if err != nil {
+			return fmt.Errorf("invalid end date: %w", err)
+		}
+		r.endDate = &end
+	}
+
+	if dto.MaxOccurrences != nil {
+		r.maxOccurrences = int(*dto.MaxOccurrences)
+	}
+
+	r.start = r.firstOccurrence()
+
+	// The scheduler would have to skip occurrences in a closed period.
+	if err := s.books.CheckOpen(ctx, userUUID, pgtype.Date{Time: r.start, Valid: true}); err != nil {
+		return err
+	}
+
+	params := db.CreateRecurringTransactionParams{
+		UserID:         userUUID,
+		AccountID:      accountUUID,
+		CategoryID:     categoryUUID,
+		Description:    dto.Description,
+		Amount:         dto.Amount,
+		Type:           txType,
+		Frequency:      r.frequency,
+		IntervalCount:  int32(r.interval),
+		StartDate:      pgtype.Date{Time: r.start, Valid: true},
+		NextOccurrence: r.next(0),
+	}
+
+	if r.dayOfMonth > 0 {
+		params.DayOfMonth = pgtype.Int4{Int32: int32(r.dayOfMonth), Valid: true}
+	}
+
+	if r.endDate != nil {
+		params.EndDate = pgtype.Date{Time: *r.endDate, Valid: true}
+	}
+
+	if r.maxOccurrences > 0 {
+		params.MaxOccurrences = pgtype.Int4{Int32: int32(r.maxOccurrences), Valid: true}
+	}
+
+	if err := s.repo.Create(ctx, params); err != nil {
+		return fmt.Errorf("service create recurring: %w", err)
+	}
+
+	return nil
+}
+
+func (s *recurringService) GetRecurring(ctx context.Context, userID, id string) (*RecurringResponse, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrRecurringNotFound
+	}
+
+	record, err := s.repo.GetRecurring(ctx, idUUID, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := RecurringToResponse(record)
+	return &response, nil
+}
+
+func (s *recurringService) GetAllRecurring(ctx context.Context, userID string) ([]RecurringResponse, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	records, err := s.repo.GetAllRecurring(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("service get all recurring: %w", err)
+	}
+
+	response := make([]RecurringResponse, len(records))
+	for i, record := range records {
+		response[i] = RecurringToResponse(record)
+	}
+
+	return response, nil
+}
+
+// Update changes the template for occurrences not created yet. Transactions
+// already created are left untouched.
+func (s *recurringService) Update(ctx context.Context, userID, id string, dto RecurringUpdateRequest) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrRecurringNotFound
+	}
+
+	return s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+
+		record, err := repo.GetRecurringForUpdate(ctx, idUUID, userUUID)
+		if err != nil {
+			return err
+		}
+
+		params := db.UpdateRecurringTransactionParams{
+			ID:             record.ID,
+			Description:    record.Description,
+			Amount:         record.Amount,
+			AccountID:      record.AccountID,
+			CategoryID:     record.CategoryID,
+			EndDate:        record.EndDate,
+			MaxOccurrences: record.MaxOccurrences,
+			UserID:         userUUID,
+		}
+
+		if dto.Description != nil {
+			params.Description = *dto.Description
+		}
+
+		if dto.Amount != nil {
+			params.Amount = *dto.Amount
+		}
+
+		if dto.AccountID != nil {
+			params.AccountID = uuid.MustParse(*dto.AccountID)
+		}
+
+		if dto.CategoryID != nil {
+			params.CategoryID = uuid.MustParse(*dto.CategoryID)
+		}
+
+		if dto.AccountID != nil || dto.CategoryID != nil {
+			if err := s.checkOwnership(ctx, userUUID, params.AccountID, params.CategoryID, record.Type); err != nil {
+				return err
+			}
+		}
+
+		if dto.EndDate != nil {
+			params.EndDate = pgtype.Date{}
+			if *dto.EndDate != "" {
+				end, err := time.Parse("2006-01-02", *dto.EndDate)
+				if err != nil {
+					return fmt.Errorf("invalid end date: %w", err)
+				}
+				params.EndDate = pgtype.Date{Time: end, Valid: true}
+			}
+		}
+
+		if dto.MaxOccurrences != nil {
+			params.MaxOccurrences = pgtype.Int4{}
+			if *dto.MaxOccurrences > 0 {
+				params.MaxOccurrences = pgtype.Int4{Int32: *dto.MaxOccurrences, Valid: true}
+			}
+		}
+
+		record.EndDate = params.EndDate
+		record.MaxOccurrences = params.MaxOccurrences
+		params.NextOccurrence = ruleFromRecord(record).next(int(record.OccurrencesCount))
+
+		return repo.Update(ctx, params)
+	})
+}
+
+// Delete stops the series. Transactions it already created are kept.
+func (s *recurringService) Delete(ctx context.Context, userID, id string) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrRecurringNotFound
+	}
+
+	return s.repo.Delete(ctx, idUUID, userUUID)
+}
+
+// SkipOccurrence drops a single future occurrence. It still counts towards
+// max_occurrences.
+func (s *recurringService) SkipOccurrence(ctx context.Context, userID, id, date string) error {
+	return s.saveException(ctx, userID, id, date, db.UpsertRecurringExceptionParams{Action: actionSkip})
+}
+
+// UpdateOccurrence changes the description and/or amount of a single future
+// occurrence, leaving the rest of the series as is.
+func (s *recurringService) UpdateOccurrence(ctx context.Context, userID, id, date string, dto OccurrenceUpdateRequest) error {
+	params := db.UpsertRecurringExceptionParams{Action: actionModify}
+
+	if dto.Description != nil {
+		params.Description = pgtype.Text{String: *dto.Description, Valid: true}
+	}
+
+	if dto.Amount != nil {
+		params.Amount = pgtype.Int8{Int64: dto.Amount.Cents(), Valid: true}
+	}
+
+	return s.saveException(ctx, userID, id, date, params)
+}
+
+func (s *recurringService) saveException(ctx context.Context, userID, id, date string, params db.UpsertRecurringExceptionParams) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrRecurringNotFound
+	}
+
+	occurrence, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return ErrInvalidOccurrence
+	}
+
+	return s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+
+		// Locking the series keeps the scheduler from creating the occurrence
+		// while the exception is being saved.
+		record, err := repo.GetRecurringForUpdate(ctx, idUUID, userUUID)
+		if err != nil {
+			return err
+		}
+
+		r := ruleFromRecord(record)
+		count := int(record.OccurrencesCount)
+
+		if r.generated(count, occurrence) {
+			return ErrOccurrenceMaterialized
+		}
+
+		if !r.pending(count, occurrence) {
+			return ErrInvalidOccurrence
+		}
+
+		params.RecurringID = record.ID
+		params.OccurrenceDate = pgtype.Date{Time: occurrence, Valid: true}
+
+		return repo.UpsertException(ctx, params)
+	})
+}
+
+// MaterializeDue creates every occurrence dated on or before until. Series
+// that fell behind, e.g. while the API was down, are caught up in one pass,
+// and creating the same occurrence twice is a no-op, so it is safe to run
+// from several instances at once.
+func (s *recurringService) MaterializeDue(ctx context.Context, until time.Time) (int, error) {
+	date := pgtype.Date{Time: until, Valid: true}
+	created := 0
+
+	// Each page starts after the last series of the previous one, so series
+	// left due by a failure are passed over instead of fetched again.
+	arg := db.GetDueRecurringTransactionsParams{Until: date, RowLimit: dueBatchSize}
+
+	for {
+		due, err := s.repo.GetDue(ctx, arg)
+		if err != nil {
+			return created, fmt.Errorf("service materialize due: %w", err)
+		}
+
+		for _, record := range due {
+			id := record.ID
+			n, err := s.materialize(ctx, id, date)
+			if errors.Is(err, errNotDue) {
+				continue
+			}
+			if errors.Is(err, account.ErrNegativeBalance) {
+				// Other series go on; this one is retried on the next run,
+				// once the account has funds.
+				log.Printf("recurring %s: %v", id, err)
+				continue
+			}
+			if err != nil {
+				return created, fmt.Errorf("service materialize %s: %w", id, err)
+			}
+
+			created += n
+		}
+
+		if len(due) < dueBatchSize {
+			return created, nil
+		}
+
+		last := due[len(due)-1]
+		arg.AfterDate = last.NextOccurrence
+		arg.AfterID = pgtype.UUID{Bytes: last.ID, Valid: true}
+	}
+}
+
+func (s *recurringService) materialize(ctx context.Context, id uuid.UUID, until pgtype.Date) (int, error) {
+	created := 0
+
+	err := s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+		accounts := s.accounts.WithTx(q)
+		closing := s.books.WithTx(q)
+
+		record, err := repo.LockDue(ctx, id, until)
+		if err != nil {
+			return err
+		}
+
+		exceptions, err := repo.GetExceptions(ctx, record.ID, record.NextOccurrence)
+		if err != nil {
+			return err
+		}
+
+		overrides := make(map[time.Time]*db.RecurringException, len(exceptions))
+		for _, exception := range exceptions {
+			overrides[exception.OccurrenceDate.Time] = exception
+		}
+
+		r := ruleFromRecord(record)
+		count := int(record.OccurrencesCount)
+		next := record.NextOccurrence
+
+		for next.Valid && !next.Time.After(until.Time) {
+			params := db.CreateRecurringOccurrenceParams{
+				Description:    record.Description,
+				Amount:         record.Amount,
+				Date:           next,
+				Type:           record.Type,
+				UserID:         record.UserID,
+				AccountID:      record.AccountID,
+				CategoryID:     record.CategoryID,
+				RecurringID:    pgtype.UUID{Bytes: record.ID, Valid: true},
+				OccurrenceDate: next,
+			}
+
+			exception := overrides[next.Time]
+			if exception != nil && exception.Description.Valid {
+				params.Description = exception.Description.String
+			}
+			if exception != nil && exception.Amount.Valid {
+				params.Amount = money.FromCents(exception.Amount.Int64)
+			}
+
+			// Occurrences that fell behind into a closed period are skipped
+			// for good, as if the user had skipped them.
+			open := true
+			if err := closing.CheckOpen(ctx, record.UserID, next); errors.Is(err, books.ErrClosed) {
+				log.Printf("recurring %s: skipping %s: %v", record.ID, next.Time.Format("2006-01-02"), err)
+				open = false
+			} else if err != nil {
+				return err
+			}
+
+			if open && (exception == nil || exception.Action != actionSkip) {
+				inserted, err := repo.CreateOccurrence(ctx, params)
+				if err != nil {
+					return err
+				}
+
+				if inserted {
+					if err := accounts.AdjustBalance(ctx, params.AccountID, signedAmount(params.Type, params.Amount)); err != nil {
+						return err
+					}
+					created++
+				}
+			}
+
+			count++
+			next = r.next(count)
+		}
+
+		return repo.Advance(ctx, db.AdvanceRecurringTransactionParams{
+			ID:               record.ID,
+			OccurrencesCount: int32(count),
+			NextOccurrence:   next,
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return created, nil
+}
+
+func (s *recurringService) checkOwnership(ctx context.Context, userID, accountID, categoryID uuid.UUID, txType db.TransactionType) error {
+	if _, err := s.accounts.GetAccount(ctx, accountID, userID); err != nil {
+		if errors.Is(err, account.ErrAccountNotFound) {
+			return ErrInvalidAccount
+		}
+		return fmt.Errorf("service check account: %w", err)
+	}
+
+	record, err := s.categories.GetCategory(ctx, categoryID, userID)
+	if err != nil {
+		if errors.Is(err, category.ErrCategoryNotFound) {
+			return ErrInvalidCategory
+		}
+		return fmt.Errorf("service check category: %w", err)
+	}
+
+	if record.Type != txType {
+		return ErrInvalidCategory
+	}
+
+	return nil
+}
+
+// signedAmount returns how much an occurrence adds to its account balance.
+func signedAmount(t db.TransactionType, amount money.Money) money.Money {
+	if t == db.TransactionTypeExpense {
+		return -amount
+	}
+	return amount
+}
@@ -0,0 +1,217 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+)
+
+const (
+	defaultPayeeLimit = 10
+	maxPayeeLimit     = 100
+)
+
+// ReportFilters are shared by every report and work like the filters of
+// GET /transactions.
+type ReportFilters struct {
+	AccountID  *string `json:"account_id,omitempty"`
+	CategoryID *string `json:"category_id,omitempty"`
+	StartDate  *string `json:"start_date,omitempty"`
+	EndDate    *string `json:"end_date,omitempty"`
+	Limit      *string `json:"limit,omitempty"` // apenas top-payees
+}
+
+func (f *ReportFilters) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	if f.AccountID != nil {
+		eval.CheckField(validator.UUID(*f.AccountID), "account_id", "this field must be a valid UUID")
+	}
+
+	if f.CategoryID != nil {
+		eval.CheckField(validator.UUID(*f.CategoryID), "category_id", "this field must be a valid UUID")
+	}
+
+	if f.StartDate != nil {
+		eval.CheckField(validator.Date(*f.StartDate), "start_date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	if f.EndDate != nil {
+		eval.CheckField(validator.Date(*f.EndDate), "end_date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	if f.StartDate != nil && f.EndDate != nil && len(eval) == 0 {
+		eval.CheckField(*f.StartDate <= *f.EndDate, "end_date", "this field must not be before start_date")
+	}
+
+	if f.Limit != nil {
+		limit, err := strconv.Atoi(*f.Limit)
+		eval.CheckField(err == nil && limit > 0 && limit <= maxPayeeLimit, "limit", fmt.Sprintf("this field must be between 1 and %d", maxPayeeLimit))
+	}
+
+	return eval
+}
+
+func (f *ReportFilters) limit() int {
+	if f.Limit == nil {
+		return defaultPayeeLimit
+	}
+
+	limit, _ := strconv.Atoi(*f.Limit)
+	return limit
+}
+
+// dates returns the parsed date range. It expects filters that already
+// passed Valid.
+func (f *ReportFilters) dates() (start, end *time.Time) {
+	if f.StartDate != nil {
+		date, _ := time.Parse("2006-01-02", *f.StartDate)
+		start = &date
+	}
+
+	if f.EndDate != nil {
+		date, _ := time.Parse("2006-01-02", *f.EndDate)
+		end = &date
+	}
+
+	return start, end
+}
+
+type MonthlyRes struct {
+	Month   string      `json:"month"` // formato: YYYY-MM
+	Income  money.Money `json:"income"`
+	Expense money.Money `json:"expense"`
+	Net     money.Money `json:"net"`
+}
+
+type CategorySpendingRes struct {
+	CategoryID   string      `json:"category_id"`
+	Name         string      `json:"name"`
+	Transactions int64       `json:"transactions"`
+	Total        money.Money `json:"total"`
+	Percent      float64     `json:"percent"`
+}
+
+// PayeeRes has PayeeID only for transactions linked to a payee.
+type PayeeRes struct {
+	Payee        string      `json:"payee"`
+	PayeeID      string      `json:"payee_id,omitempty"`
+	Transactions int64       `json:"transactions"`
+	Total        money.Money `json:"total"`
+}
+
+type CashFlowRes struct {
+	Date    string      `json:"date"`
+	Inflow  money.Money `json:"inflow"`
+	Outflow money.Money `json:"outflow"`
+	Net     money.Money `json:"net"`
+}
+
+// maxNetWorthPoints bounds how many dates a net worth request can ask for.
+const maxNetWorthPoints = 1000
+
+var intervalOptions = map[string]bool{
+	"day":   true,
+	"week":  true,
+	"month": true,
+	"year":  true,
+}
+
+type NetWorthFilters struct {
+	From     *string `json:"from,omitempty"`     // padrão: um ano antes de to
+	To       *string `json:"to,omitempty"`       // padrão: hoje
+	Interval *string `json:"interval,omitempty"` // day, week, month (padrão) ou year
+}
+
+func (f *NetWorthFilters) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	if f.From != nil {
+		eval.CheckField(validator.Date(*f.From), "from", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	if f.To != nil {
+		eval.CheckField(validator.Date(*f.To), "to", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	if f.Interval != nil {
+		eval.CheckField(intervalOptions[*f.Interval], "interval", "this field must be day, week, month or year")
+	}
+
+	if len(eval) == 0 {
+		from, to := f.dates(time.Now())
+		eval.CheckField(!from.After(to), "to", "this field must not be before from")
+		eval.CheckField(len(f.points(from, to)) <= maxNetWorthPoints, "interval", fmt.Sprintf("the range must have up to %d intervals", maxNetWorthPoints))
+	}
+
+	return eval
+}
+
+func (f *NetWorthFilters) interval() string {
+	if f.Interval == nil {
+		return "month"
+	}
+	return *f.Interval
+}
+
+// dates returns the requested range, filling in the defaults relative to
+// now. It expects filters that already passed Valid.
+func (f *NetWorthFilters) dates(now time.Time) (from, to time.Time) {
+	to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if f.To != nil {
+		to, _ = time.Parse("2006-01-02", *f.To)
+	}
+
+	from = to.AddDate(-1, 0, 0)
+	if f.From != nil {
+		from, _ = time.Parse("2006-01-02", *f.From)
+	}
+
+	return from, to
+}
+
+// points returns the last day of every interval that overlaps the range; the
+// last point is to itself when it falls in the middle of an interval. Weeks
+// end on Sunday.
+func (f *NetWorthFilters) points(from, to time.Time) []time.Time {
+	var points []time.Time
+
+	for day := from; !day.After(to); {
+		var end time.Time
+
+		switch f.interval() {
+		case "day":
+			end = day
+		case "week":
+			end = day.AddDate(0, 0, (7-int(day.Weekday()))%7)
+		case "year":
+			end = time.Date(day.Year(), time.December, 31, 0, 0, 0, 0, time.UTC)
+		default:
+			end = time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+		}
+
+		if end.After(to) {
+			end = to
+		}
+
+		points = append(points, end)
+		if len(points) > maxNetWorthPoints {
+			break
+		}
+
+		day = end.AddDate(0, 0, 1)
+	}
+
+	return points
+}
+
+type NetWorthRes struct {
+	Date        string      `json:"date"`
+	Assets      money.Money `json:"assets"`
+	Liabilities money.Money `json:"liabilities"`
+	NetWorth    money.Money `json:"net_worth"`
+}
@@ -0,0 +1,203 @@
+package report
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/EduardoMark/my-finance-api/internal/exchangerate"
+	"github.com/EduardoMark/my-finance-api/internal/middlewares"
+	"github.com/EduardoMark/my-finance-api/pkg/httputils"
+	"github.com/EduardoMark/my-finance-api/pkg/token"
+	"github.com/go-chi/chi/v5"
+)
+
+type ReportHandler struct {
+	svc   Service
+	token *token.TokenManager
+}
+
+func NewReportHandler(svc Service, token *token.TokenManager) ReportHandler {
+	return ReportHandler{
+		svc:   svc,
+		token: token,
+	}
+}
+
+func (h *ReportHandler) RegisterReportRoutes(r chi.Router) {
+	r.Route("/reports", func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(h.token))
+
+		r.Get("/income-expense", h.GetIncomeExpense)
+		r.Get("/spending-by-category", h.GetSpendingByCategory)
+		r.Get("/top-payees", h.GetTopPayees)
+		r.Get("/cash-flow", h.GetCashFlow)
+		r.Get("/net-worth", h.GetNetWorth)
+	})
+}
+
+func (h *ReportHandler) GetIncomeExpense(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	filters, ok := parseFilters(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.GetIncomeExpense(ctx, userID, filters)
+	if err != nil {
+		writeReportError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *ReportHandler) GetSpendingByCategory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	filters, ok := parseFilters(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.GetSpendingByCategory(ctx, userID, filters)
+	if err != nil {
+		writeReportError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *ReportHandler) GetTopPayees(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	filters, ok := parseFilters(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.GetTopPayees(ctx, userID, filters)
+	if err != nil {
+		writeReportError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *ReportHandler) GetCashFlow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	filters, ok := parseFilters(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.GetCashFlow(ctx, userID, filters)
+	if err != nil {
+		if errors.Is(err, ErrAccountRequired) || errors.Is(err, ErrInvalidAccount) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"account_id": err.Error()})
+			return
+		}
+		writeReportError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *ReportHandler) GetNetWorth(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	query := r.URL.Query()
+	filters := &NetWorthFilters{
+		From:     queryParam(query, "from"),
+		To:       queryParam(query, "to"),
+		Interval: queryParam(query, "interval"),
+	}
+
+	if problems := filters.Valid(ctx); len(problems) > 0 {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	res, err := h.svc.GetNetWorth(ctx, userID, filters)
+	if err != nil {
+		writeReportError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+// writeReportError answers 422 when an amount could not be converted into
+// the base currency, so the user knows which rate to add.
+func writeReportError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, exchangerate.ErrMissingRate) {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
+
+// parseFilters reads and validates the query string. ok is false when a
+// response was already written.
+func parseFilters(w http.ResponseWriter, r *http.Request) (*ReportFilters, bool) {
+	query := r.URL.Query()
+	filters := &ReportFilters{
+		AccountID:  queryParam(query, "account_id"),
+		CategoryID: queryParam(query, "category_id"),
+		StartDate:  queryParam(query, "start_date"),
+		EndDate:    queryParam(query, "end_date"),
+		Limit:      queryParam(query, "limit"),
+	}
+
+	if problems := filters.Valid(r.Context()); len(problems) > 0 {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return nil, false
+	}
+
+	return filters, true
+}
+
+// queryParam returns nil for absent or empty query parameters.
+func queryParam(query url.Values, key string) *string {
+	value := query.Get(key)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,49 @@
+package report
+
+import (
+	"context"
+
+	"github.com/EduardoMark/my-finance-api/internal/exchangerate"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+)
+
+type Repository interface {
+	GetMonthlyIncomeExpense(ctx context.Context, arg db.GetMonthlyIncomeExpenseParams) ([]*db.GetMonthlyIncomeExpenseRow, error)
+	GetSpendingByCategory(ctx context.Context, arg db.GetSpendingByCategoryParams) ([]*db.GetSpendingByCategoryRow, error)
+	GetTopPayees(ctx context.Context, arg db.GetTopPayeesParams) ([]*db.GetTopPayeesRow, error)
+	GetDailyCashFlow(ctx context.Context, arg db.GetDailyCashFlowParams) ([]*db.GetDailyCashFlowRow, error)
+	GetNetWorthHistory(ctx context.Context, arg db.GetNetWorthHistoryParams) ([]*db.GetNetWorthHistoryRow, error)
+}
+
+type reportRepository struct {
+	db *db.Queries
+}
+
+func NewReportRepository(db *db.Queries) Repository {
+	return &reportRepository{db: db}
+}
+
+func (r *reportRepository) GetMonthlyIncomeExpense(ctx context.Context, arg db.GetMonthlyIncomeExpenseParams) ([]*db.GetMonthlyIncomeExpenseRow, error) {
+	rows, err := r.db.GetMonthlyIncomeExpense(ctx, arg)
+	return rows, exchangerate.MissingRate(err)
+}
+
+func (r *reportRepository) GetSpendingByCategory(ctx context.Context, arg db.GetSpendingByCategoryParams) ([]*db.GetSpendingByCategoryRow, error) {
+	rows, err := r.db.GetSpendingByCategory(ctx, arg)
+	return rows, exchangerate.MissingRate(err)
+}
+
+func (r *reportRepository) GetTopPayees(ctx context.Context, arg db.GetTopPayeesParams) ([]*db.GetTopPayeesRow, error) {
+	rows, err := r.db.GetTopPayees(ctx, arg)
+	return rows, exchangerate.MissingRate(err)
+}
+
+func (r *reportRepository) GetDailyCashFlow(ctx context.Context, arg db.GetDailyCashFlowParams) ([]*db.GetDailyCashFlowRow, error) {
+	rows, err := r.db.GetDailyCashFlow(ctx, arg)
+	return rows, exchangerate.MissingRate(err)
+}
+
+func (r *reportRepository) GetNetWorthHistory(ctx context.Context, arg db.GetNetWorthHistoryParams) ([]*db.GetNetWorthHistoryRow, error) {
+	rows, err := r.db.GetNetWorthHistory(ctx, arg)
+	return rows, exchangerate.MissingRate(err)
+}
@@ -0,0 +1,267 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/account"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Service interface {
+	GetIncomeExpense(ctx context.Context, userID string, filters *ReportFilters) ([]MonthlyRes, error)
+	GetSpendingByCategory(ctx context.Context, userID string, filters *ReportFilters) ([]CategorySpendingRes, error)
+	GetTopPayees(ctx context.Context, userID string, filters *ReportFilters) ([]PayeeRes, error)
+	GetCashFlow(ctx context.Context, userID string, filters *ReportFilters) ([]CashFlowRes, error)
+	GetNetWorth(ctx context.Context, userID string, filters *NetWorthFilters) ([]NetWorthRes, error)
+}
+
+type reportService struct {
+	repo     Repository
+	accounts account.Repository
+}
+
+func NewReportService(repo Repository, accounts account.Repository) Service {
+	return &reportService{
+		repo:     repo,
+		accounts: accounts,
+	}
+}
+
+var ErrInvalidAccount = errors.New("account not found")
+var ErrAccountRequired = errors.New("this field is required")
+
+// args holds the filters converted to query arguments; absent filters stay
+// NULL.
+type args struct {
+	userID     uuid.UUID
+	accountID  pgtype.UUID
+	categoryID pgtype.UUID
+	startDate  pgtype.Date
+	endDate    pgtype.Date
+}
+
+func newArgs(userID string, filters *ReportFilters) args {
+	a := args{userID: uuid.MustParse(userID)}
+
+	if filters.AccountID != nil {
+		a.accountID = pgtype.UUID{Bytes: uuid.MustParse(*filters.AccountID), Valid: true}
+	}
+
+	if filters.CategoryID != nil {
+		a.categoryID = pgtype.UUID{Bytes: uuid.MustParse(*filters.CategoryID), Valid: true}
+	}
+
+	start, end := filters.dates()
+	if start != nil {
+		a.startDate = pgtype.Date{Time: *start, Valid: true}
+	}
+	if end != nil {
+		a.endDate = pgtype.Date{Time: *end, Valid: true}
+	}
+
+	return a
+}
+
+// GetIncomeExpense returns one entry per month, including months without
+// transactions between the first and the last one of the range.
+func (s *reportService) GetIncomeExpense(ctx context.Context, userID string, filters *ReportFilters) ([]MonthlyRes, error) {
+	a := newArgs(userID, filters)
+
+	records, err := s.repo.GetMonthlyIncomeExpense(ctx, db.GetMonthlyIncomeExpenseParams{
+		UserID:     a.userID,
+		AccountID:  a.accountID,
+		CategoryID: a.categoryID,
+		StartDate:  a.startDate,
+		EndDate:    a.endDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service get income expense: %w", err)
+	}
+
+	byMonth := make(map[string]*db.GetMonthlyIncomeExpenseRow, len(records))
+	for _, record := range records {
+		byMonth[record.Month.Time.Format("2006-01")] = record
+	}
+
+	first, last := filters.dates()
+	if len(records) > 0 {
+		if first == nil {
+			first = &records[0].Month.Time
+		}
+		if last == nil {
+			last = &records[len(records)-1].Month.Time
+		}
+	}
+
+	res := []MonthlyRes{}
+	if first == nil || last == nil {
+		return res, nil
+	}
+
+	for month := monthStart(*first); !month.After(*last); month = month.AddDate(0, 1, 0) {
+		item := MonthlyRes{Month: month.Format("2006-01")}
+
+		if record, ok := byMonth[item.Month]; ok {
+			item.Income = money.FromCents(record.Income)
+			item.Expense = money.FromCents(record.Expense)
+			item.Net = item.Income - item.Expense
+		}
+
+		res = append(res, item)
+	}
+
+	return res, nil
+}
+
+// GetSpendingByCategory returns the expenses of each category, largest
+// first, with their share of the total.
+func (s *reportService) GetSpendingByCategory(ctx context.Context, userID string, filters *ReportFilters) ([]CategorySpendingRes, error) {
+	a := newArgs(userID, filters)
+
+	records, err := s.repo.GetSpendingByCategory(ctx, db.GetSpendingByCategoryParams{
+		UserID:     a.userID,
+		AccountID:  a.accountID,
+		CategoryID: a.categoryID,
+		StartDate:  a.startDate,
+		EndDate:    a.endDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service get spending by category: %w", err)
+	}
+
+	var total int64
+	for _, record := range records {
+		total += record.Total
+	}
+
+	res := make([]CategorySpendingRes, len(records))
+	for i, record := range records {
+		res[i] = CategorySpendingRes{
+			CategoryID:   record.ID.String(),
+			Name:         record.Name,
+			Transactions: record.Transactions,
+			Total:        money.FromCents(record.Total),
+			Percent:      math.Round(float64(record.Total)/float64(total)*10000) / 100,
+		}
+	}
+
+	return res, nil
+}
+
+func (s *reportService) GetTopPayees(ctx context.Context, userID string, filters *ReportFilters) ([]PayeeRes, error) {
+	a := newArgs(userID, filters)
+
+	records, err := s.repo.GetTopPayees(ctx, db.GetTopPayeesParams{
+		UserID:     a.userID,
+		AccountID:  a.accountID,
+		CategoryID: a.categoryID,
+		StartDate:  a.startDate,
+		EndDate:    a.endDate,
+		RowLimit:   int32(filters.limit()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service get top payees: %w", err)
+	}
+
+	res := make([]PayeeRes, len(records))
+	for i, record := range records {
+		res[i] = PayeeRes{
+			Payee:        record.Payee,
+			Transactions: record.Transactions,
+			Total:        money.FromCents(record.Total),
+		}
+
+		if record.PayeeID.Valid {
+			res[i].PayeeID = uuid.UUID(record.PayeeID.Bytes).String()
+		}
+	}
+
+	return res, nil
+}
+
+// GetCashFlow returns the money in and out of one account on each day with
+// movement. The account filter is required.
+func (s *reportService) GetCashFlow(ctx context.Context, userID string, filters *ReportFilters) ([]CashFlowRes, error) {
+	if filters.AccountID == nil {
+		return nil, ErrAccountRequired
+	}
+
+	a := newArgs(userID, filters)
+	accountID := uuid.UUID(a.accountID.Bytes)
+
+	if _, err := s.accounts.GetAccount(ctx, accountID, a.userID); err != nil {
+		if errors.Is(err, account.ErrAccountNotFound) {
+			return nil, ErrInvalidAccount
+		}
+		return nil, fmt.Errorf("service get cash flow: %w", err)
+	}
+
+	records, err := s.repo.GetDailyCashFlow(ctx, db.GetDailyCashFlowParams{
+		UserID:     a.userID,
+		AccountID:  accountID,
+		CategoryID: a.categoryID,
+		StartDate:  a.startDate,
+		EndDate:    a.endDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service get cash flow: %w", err)
+	}
+
+	res := make([]CashFlowRes, len(records))
+	for i, record := range records {
+		inflow, outflow := money.FromCents(record.Inflow), money.FromCents(record.Outflow)
+		res[i] = CashFlowRes{
+			Date:    record.Date.Time.Format("2006-01-02"),
+			Inflow:  inflow,
+			Outflow: outflow,
+			Net:     inflow - outflow,
+		}
+	}
+
+	return res, nil
+}
+
+// GetNetWorth returns the net worth at the end of each interval. Accounts
+// only store their current balance, so past balances are rebuilt from the
+// transaction history. It expects filters that already passed Valid.
+func (s *reportService) GetNetWorth(ctx context.Context, userID string, filters *NetWorthFilters) ([]NetWorthRes, error) {
+	from, to := filters.dates(time.Now())
+	points := filters.points(from, to)
+
+	days := make([]pgtype.Date, len(points))
+	for i, point := range points {
+		days[i] = pgtype.Date{Time: point, Valid: true}
+	}
+
+	records, err := s.repo.GetNetWorthHistory(ctx, db.GetNetWorthHistoryParams{
+		Days:   days,
+		UserID: uuid.MustParse(userID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service get net worth: %w", err)
+	}
+
+	res := make([]NetWorthRes, len(records))
+	for i, record := range records {
+		assets, liabilities := money.FromCents(record.Assets), money.FromCents(record.Liabilities)
+		res[i] = NetWorthRes{
+			Date:        record.Date.Time.Format("2006-01-02"),
+			Assets:      assets,
+			Liabilities: liabilities,
+			NetWorth:    assets - liabilities,
+		}
+	}
+
+	return res, nil
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,217 @@
+package rule
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/tag"
+	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+)
+
+const (
+	maxNameLength        = 100
+	maxPatternLength     = 200
+	maxDescriptionLength = 255
+)
+
+// RuleReq creates or replaces a rule. At least one condition and one action
+// must be set; Enabled defaults to true.
+type RuleReq struct {
+	Name       string     `json:"name"`
+	Priority   int32      `json:"priority"` // menor roda primeiro
+	Enabled    *bool      `json:"enabled,omitempty"`
+	Conditions Conditions `json:"conditions"`
+	Actions    Actions    `json:"actions"`
+}
+
+type Conditions struct {
+	DescriptionContains string       `json:"description_contains,omitempty"` // sem diferenciar maiúsculas
+	DescriptionRegex    string       `json:"description_regex,omitempty"`    // sintaxe RE2 do Go
+	MinAmount           *money.Money `json:"min_amount,omitempty"`
+	MaxAmount           *money.Money `json:"max_amount,omitempty"`
+	AccountID           string       `json:"account_id,omitempty"`
+	Type                string       `json:"type,omitempty"` // "income" ou "expense"
+}
+
+type Actions struct {
+	CategoryID  string   `json:"category_id,omitempty"`
+	PayeeID     string   `json:"payee_id,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+func (r *RuleReq) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	r.Name = strings.TrimSpace(r.Name)
+	eval.CheckField(validator.NotBlank(r.Name) && validator.MaxChars(r.Name, maxNameLength), "name", fmt.Sprintf("this field must have between 1 and %d chars", maxNameLength))
+	eval.CheckField(r.Priority >= 0, "priority", "this field must be 0 or greater")
+
+	c := &r.Conditions
+	eval.CheckField(c.DescriptionContains != "" || c.DescriptionRegex != "" || c.MinAmount != nil || c.MaxAmount != nil ||
+		c.AccountID != "" || c.Type != "", "conditions", "at least one condition must be set")
+
+	if c.DescriptionContains != "" {
+		eval.CheckField(validator.MaxChars(c.DescriptionContains, maxPatternLength), "conditions.description_contains", fmt.Sprintf("this field must have up to %d chars", maxPatternLength))
+	}
+
+	if c.DescriptionRegex != "" {
+		_, err := regexp.Compile(c.DescriptionRegex)
+		eval.CheckField(err == nil && validator.MaxChars(c.DescriptionRegex, maxPatternLength), "conditions.description_regex", fmt.Sprintf("this field must be a valid regular expression with up to %d chars", maxPatternLength))
+	}
+
+	if c.MinAmount != nil {
+		eval.CheckField(*c.MinAmount > 0, "conditions.min_amount", "this field must be greater than 0")
+	}
+
+	if c.MaxAmount != nil {
+		eval.CheckField(*c.MaxAmount > 0, "conditions.max_amount", "this field must be greater than 0")
+		if c.MinAmount != nil {
+			eval.CheckField(*c.MaxAmount >= *c.MinAmount, "conditions.max_amount", "this field must be greater than or equal to min_amount")
+		}
+	}
+
+	if c.AccountID != "" {
+		eval.CheckField(validator.UUID(c.AccountID), "conditions.account_id", "this field must be a valid UUID")
+	}
+
+	if c.Type != "" {
+		eval.CheckField(c.Type == "income" || c.Type == "expense", "conditions.type", "this field must be 'income' or 'expense'")
+	}
+
+	a := &r.Actions
+	a.Tags = tag.NormalizeNames(a.Tags)
+	a.Description = strings.TrimSpace(a.Description)
+	eval.CheckField(a.CategoryID != "" || a.PayeeID != "" || len(a.Tags) > 0 || a.Description != "", "actions", "at least one action must be set")
+
+	if a.CategoryID != "" {
+		eval.CheckField(validator.UUID(a.CategoryID), "actions.category_id", "this field must be a valid UUID")
+		eval.CheckField(c.Type != "", "conditions.type", "this field is required when actions.category_id is set")
+	}
+
+	if a.PayeeID != "" {
+		eval.CheckField(validator.UUID(a.PayeeID), "actions.payee_id", "this field must be a valid UUID")
+	}
+
+	for i, name := range a.Tags {
+		eval.CheckField(tag.ValidName(name), fmt.Sprintf("actions.tags[%d]", i), "this field must have up to 50 chars and no commas")
+	}
+
+	eval.CheckField(validator.MaxChars(a.Description, maxDescriptionLength), "actions.description", fmt.Sprintf("this field must have up to %d chars", maxDescriptionLength))
+
+	return eval
+}
+
+type RuleRes struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Priority   int32      `json:"priority"`
+	Enabled    bool       `json:"enabled"`
+	Conditions Conditions `json:"conditions"`
+	Actions    Actions    `json:"actions"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func RuleToResponse(r *db.Rule) RuleRes {
+	res := RuleRes{
+		ID:       r.ID.String(),
+		Name:     r.Name,
+		Priority: r.Priority,
+		Enabled:  r.Enabled,
+		Conditions: Conditions{
+			DescriptionContains: r.DescriptionContains.String,
+			DescriptionRegex:    r.DescriptionRegex.String,
+			Type:                string(r.Type.TransactionType),
+		},
+		Actions: Actions{
+			Tags:        r.AddTags,
+			Description: r.SetDescription.String,
+		},
+		CreatedAt: r.CreatedAt.Time,
+		UpdatedAt: r.UpdatedAt.Time,
+	}
+
+	if r.MinAmount.Valid {
+		amount := money.FromCents(r.MinAmount.Int64)
+		res.Conditions.MinAmount = &amount
+	}
+
+	if r.MaxAmount.Valid {
+		amount := money.FromCents(r.MaxAmount.Int64)
+		res.Conditions.MaxAmount = &amount
+	}
+
+	if r.AccountID.Valid {
+		res.Conditions.AccountID = uuid.UUID(r.AccountID.Bytes).String()
+	}
+
+	if r.SetCategoryID.Valid {
+		res.Actions.CategoryID = uuid.UUID(r.SetCategoryID.Bytes).String()
+	}
+
+	if r.SetPayeeID.Valid {
+		res.Actions.PayeeID = uuid.UUID(r.SetPayeeID.Bytes).String()
+	}
+
+	return res
+}
+
+// RunReq selects the income and expenses the rules run on again. With
+// DryRun nothing is saved and the response shows what would change.
+type RunReq struct {
+	AccountID *string `json:"account_id,omitempty"`
+	StartDate *string `json:"start_date,omitempty"` // formato: YYYY-MM-DD
+	EndDate   *string `json:"end_date,omitempty"`   // formato: YYYY-MM-DD
+	DryRun    bool    `json:"dry_run"`
+}
+
+func (r *RunReq) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	if r.AccountID != nil {
+		eval.CheckField(validator.UUID(*r.AccountID), "account_id", "this field must be a valid UUID")
+	}
+
+	if r.StartDate != nil {
+		eval.CheckField(validator.Date(*r.StartDate), "start_date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	if r.EndDate != nil {
+		eval.CheckField(validator.Date(*r.EndDate), "end_date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	return eval
+}
+
+// DiffRes is the value of a field before and after the rules. Empty means
+// no value.
+type DiffRes struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ChangeRes lists what the rules change in one transaction; fields they
+// leave as they are are omitted.
+type ChangeRes struct {
+	TransactionID string   `json:"transaction_id"`
+	Date          string   `json:"date"`
+	RuleIDs       []string `json:"rule_ids"`
+	Description   *DiffRes `json:"description,omitempty"`
+	CategoryID    *DiffRes `json:"category_id,omitempty"`
+	PayeeID       *DiffRes `json:"payee_id,omitempty"`
+	AddedTags     []string `json:"added_tags,omitempty"`
+}
+
+type RunRes struct {
+	DryRun  bool        `json:"dry_run"`
+	Checked int         `json:"checked"`
+	Changed int         `json:"changed"`
+	Changes []ChangeRes `json:"changes"`
+}
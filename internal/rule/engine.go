@@ -0,0 +1,139 @@
+package rule
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/tag"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+)
+
+// Input is the part of a transaction that rule conditions look at.
+type Input struct {
+	Description string
+	Amount      money.Money
+	AccountID   uuid.UUID
+	Type        db.TransactionType
+}
+
+// Outcome gathers the actions of every rule that matched. A nil field was
+// not set by any of them.
+type Outcome struct {
+	RuleIDs     []uuid.UUID
+	CategoryID  *uuid.UUID
+	PayeeID     *uuid.UUID
+	Description *string
+	Tags        []string
+}
+
+func (o *Outcome) Matched() bool {
+	return len(o.RuleIDs) > 0
+}
+
+// Set is a list of rules ready to run, in priority order.
+type Set struct {
+	rules      []compiledRule
+	categories map[uuid.UUID]db.TransactionType
+}
+
+type compiledRule struct {
+	*db.Rule
+	regex *regexp.Regexp
+}
+
+// NewSet expects the rules in the order they run, as GetEnabled returns
+// them, and the categories of the user. Regexes are checked when rules are
+// saved, so a rule whose regex no longer compiles is skipped instead of
+// failing every transaction.
+func NewSet(records []*db.Rule, categories []*db.Category) *Set {
+	set := &Set{
+		rules:      make([]compiledRule, 0, len(records)),
+		categories: make(map[uuid.UUID]db.TransactionType, len(categories)),
+	}
+
+	for _, record := range categories {
+		set.categories[record.ID] = record.Type
+	}
+
+	for _, record := range records {
+		r := compiledRule{Rule: record}
+		if record.DescriptionRegex.Valid {
+			regex, err := regexp.Compile(record.DescriptionRegex.String)
+			if err != nil {
+				continue
+			}
+			r.regex = regex
+		}
+		set.rules = append(set.rules, r)
+	}
+
+	return set
+}
+
+// Apply runs every rule against in. For each action the first matching rule
+// that sets it wins; tags of all matching rules add up. A category whose type
+// differs from in.Type is skipped, e.g. on a rule saved before conditions.type
+// was required or whose category changed type since.
+func (s *Set) Apply(in Input) Outcome {
+	var outcome Outcome
+
+	for _, r := range s.rules {
+		if !r.matches(in) {
+			continue
+		}
+
+		outcome.RuleIDs = append(outcome.RuleIDs, r.ID)
+
+		if outcome.CategoryID == nil && r.SetCategoryID.Valid {
+			id := uuid.UUID(r.SetCategoryID.Bytes)
+			if s.categories[id] == in.Type {
+				outcome.CategoryID = &id
+			}
+		}
+
+		if outcome.PayeeID == nil && r.SetPayeeID.Valid {
+			id := uuid.UUID(r.SetPayeeID.Bytes)
+			outcome.PayeeID = &id
+		}
+
+		if outcome.Description == nil && r.SetDescription.Valid {
+			description := r.SetDescription.String
+			outcome.Description = &description
+		}
+
+		outcome.Tags = append(outcome.Tags, r.AddTags...)
+	}
+
+	outcome.Tags = tag.NormalizeNames(outcome.Tags)
+	return outcome
+}
+
+func (r *compiledRule) matches(in Input) bool {
+	if r.DescriptionContains.Valid && !strings.Contains(strings.ToLower(in.Description), strings.ToLower(r.DescriptionContains.String)) {
+		return false
+	}
+
+	if r.regex != nil && !r.regex.MatchString(in.Description) {
+		return false
+	}
+
+	if r.MinAmount.Valid && in.Amount.Cents() < r.MinAmount.Int64 {
+		return false
+	}
+
+	if r.MaxAmount.Valid && in.Amount.Cents() > r.MaxAmount.Int64 {
+		return false
+	}
+
+	if r.AccountID.Valid && uuid.UUID(r.AccountID.Bytes) != in.AccountID {
+		return false
+	}
+
+	if r.Type.Valid && r.Type.TransactionType != in.Type {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,187 @@
+package rule
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EduardoMark/my-finance-api/internal/middlewares"
+	"github.com/EduardoMark/my-finance-api/pkg/httputils"
+	"github.com/EduardoMark/my-finance-api/pkg/token"
+	"github.com/go-chi/chi/v5"
+)
+
+type RuleHandler struct {
+	svc    Service
+	runner Runner
+	token  *token.TokenManager
+}
+
+func NewRuleHandler(svc Service, runner Runner, token *token.TokenManager) RuleHandler {
+	return RuleHandler{
+		svc:    svc,
+		runner: runner,
+		token:  token,
+	}
+}
+
+func (h *RuleHandler) RegisterRuleRoutes(r chi.Router) {
+	r.Route("/rules", func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(h.token))
+
+		r.Post("/", h.Create)
+		r.Get("/", h.GetRules)
+		r.Post("/run", h.Run)
+		r.Get("/{id}", h.GetRule)
+		r.Put("/{id}", h.Update)
+		r.Delete("/{id}", h.Delete)
+	})
+}
+
+func (h *RuleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*RuleReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	res, err := h.svc.Create(ctx, userID, data)
+	if err != nil {
+		writeRuleError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusCreated, res)
+}
+
+func (h *RuleHandler) GetRules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	res, err := h.svc.GetRules(ctx, userID)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *RuleHandler) GetRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	res, err := h.svc.GetRule(ctx, userID, id)
+	if err != nil {
+		writeRuleError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func (h *RuleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*RuleReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.svc.Update(ctx, userID, id, data); err != nil {
+		writeRuleError(w, r, err)
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+func (h *RuleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	if err := h.svc.Delete(ctx, userID, id); err != nil {
+		writeRuleError(w, r, err)
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+// Run applies the enabled rules to existing income and expenses, or only
+// lists the changes when dry_run is true.
+func (h *RuleHandler) Run(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*RunReq](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	res, err := h.runner.RunRules(ctx, userID, data)
+	if err != nil {
+		writeRuleError(w, r, err)
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
+}
+
+func writeRuleError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrRuleNotFound):
+		_ = httputils.EncodeJson(w, r, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrInvalidAccount):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"conditions.account_id": err.Error()})
+	case errors.Is(err, ErrInvalidCategory), errors.Is(err, ErrCategoryType):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"actions.category_id": err.Error()})
+	case errors.Is(err, ErrInvalidPayee):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"actions.payee_id": err.Error()})
+	case errors.Is(err, ErrTooManyTransactions):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+	default:
+		_ = httputils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
@@ -0,0 +1,102 @@
+package rule
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, arg db.CreateRuleParams) (*db.Rule, error)
+	GetRule(ctx context.Context, id, userID uuid.UUID) (*db.Rule, error)
+	GetRules(ctx context.Context, userID uuid.UUID) ([]*db.Rule, error)
+	GetEnabled(ctx context.Context, userID uuid.UUID) ([]*db.Rule, error)
+	Update(ctx context.Context, arg db.UpdateRuleParams) error
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+}
+
+type ruleRepository struct {
+	db *db.Queries
+}
+
+func NewRuleRepository(db *db.Queries) Repository {
+	return &ruleRepository{db: db}
+}
+
+var ErrRuleNotFound = errors.New("rule not found")
+var ErrInvalidAccount = errors.New("account not found")
+var ErrInvalidCategory = errors.New("category not found")
+var ErrCategoryType = errors.New("the category type must match conditions.type")
+var ErrInvalidPayee = errors.New("payee not found")
+var ErrTooManyTransactions = errors.New("too many transactions, narrow the period with start_date and end_date")
+
+func (r *ruleRepository) Create(ctx context.Context, arg db.CreateRuleParams) (*db.Rule, error) {
+	record, err := r.db.CreateRule(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("repository create rule: %w", err)
+	}
+
+	return record, nil
+}
+
+func (r *ruleRepository) GetRule(ctx context.Context, id, userID uuid.UUID) (*db.Rule, error) {
+	record, err := r.db.GetRule(ctx, db.GetRuleParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRuleNotFound
+		}
+		return nil, fmt.Errorf("repository get rule: %w", err)
+	}
+
+	return record, nil
+}
+
+func (r *ruleRepository) GetRules(ctx context.Context, userID uuid.UUID) ([]*db.Rule, error) {
+	records, err := r.db.GetRules(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository get rules: %w", err)
+	}
+
+	return records, nil
+}
+
+// GetEnabled returns the rules that run on transactions, in the order they
+// run.
+func (r *ruleRepository) GetEnabled(ctx context.Context, userID uuid.UUID) ([]*db.Rule, error) {
+	records, err := r.db.GetEnabledRules(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository get enabled rules: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *ruleRepository) Update(ctx context.Context, arg db.UpdateRuleParams) error {
+	rows, err := r.db.UpdateRule(ctx, arg)
+	if err != nil {
+		return fmt.Errorf("repository update rule: %w", err)
+	}
+
+	if rows == 0 {
+		return ErrRuleNotFound
+	}
+
+	return nil
+}
+
+func (r *ruleRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	rows, err := r.db.DeleteRule(ctx, db.DeleteRuleParams{ID: id, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("repository delete rule: %w", err)
+	}
+
+	if rows == 0 {
+		return ErrRuleNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package rule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/EduardoMark/my-finance-api/internal/account"
+	"github.com/EduardoMark/my-finance-api/internal/category"
+	"github.com/EduardoMark/my-finance-api/internal/payee"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Service interface {
+	Create(ctx context.Context, userID string, req *RuleReq) (*RuleRes, error)
+	GetRule(ctx context.Context, userID, id string) (*RuleRes, error)
+	GetRules(ctx context.Context, userID string) ([]RuleRes, error)
+	Update(ctx context.Context, userID, id string, req *RuleReq) error
+	Delete(ctx context.Context, userID, id string) error
+}
+
+// Runner runs the rules again on existing transactions. It is implemented
+// by the transaction service, which owns the update path.
+type Runner interface {
+	RunRules(ctx context.Context, userID string, req *RunReq) (*RunRes, error)
+}
+
+type ruleService struct {
+	repo       Repository
+	accounts   account.Repository
+	categories category.Repository
+	payees     payee.Repository
+}
+
+func NewRuleService(repo Repository, accounts account.Repository, categories category.Repository, payees payee.Repository) Service {
+	return &ruleService{
+		repo:       repo,
+		accounts:   accounts,
+		categories: categories,
+		payees:     payees,
+	}
+}
+
+func (s *ruleService) Create(ctx context.Context, userID string, req *RuleReq) (*RuleRes, error) {
+	userUUID := uuid.MustParse(userID)
+
+	fields, err := s.fields(ctx, userUUID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.repo.Create(ctx, db.CreateRuleParams{
+		UserID:              userUUID,
+		Name:                fields.Name,
+		Priority:            fields.Priority,
+		Enabled:             fields.Enabled,
+		DescriptionContains: fields.DescriptionContains,
+		DescriptionRegex:    fields.DescriptionRegex,
+		MinAmount:           fields.MinAmount,
+		MaxAmount:           fields.MaxAmount,
+		AccountID:           fields.AccountID,
+		Type:                fields.Type,
+		SetCategoryID:       fields.SetCategoryID,
+		SetPayeeID:          fields.SetPayeeID,
+		AddTags:             fields.AddTags,
+		SetDescription:      fields.SetDescription,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service create rule: %w", err)
+	}
+
+	res := RuleToResponse(record)
+	return &res, nil
+}
+
+func (s *ruleService) GetRule(ctx context.Context, userID, id string) (*RuleRes, error) {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrRuleNotFound
+	}
+
+	record, err := s.repo.GetRule(ctx, idUUID, uuid.MustParse(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	res := RuleToResponse(record)
+	return &res, nil
+}
+
+func (s *ruleService) GetRules(ctx context.Context, userID string) ([]RuleRes, error) {
+	records, err := s.repo.GetRules(ctx, uuid.MustParse(userID))
+	if err != nil {
+		return nil, fmt.Errorf("service get rules: %w", err)
+	}
+
+	res := make([]RuleRes, len(records))
+	for i, record := range records {
+		res[i] = RuleToResponse(record)
+	}
+
+	return res, nil
+}
+
+func (s *ruleService) Update(ctx context.Context, userID, id string, req *RuleReq) error {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrRuleNotFound
+	}
+
+	userUUID := uuid.MustParse(userID)
+
+	fields, err := s.fields(ctx, userUUID, req)
+	if err != nil {
+		return err
+	}
+
+	fields.ID = idUUID
+	fields.UserID = userUUID
+
+	return s.repo.Update(ctx, *fields)
+}
+
+func (s *ruleService) Delete(ctx context.Context, userID, id string) error {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrRuleNotFound
+	}
+
+	return s.repo.Delete(ctx, idUUID, uuid.MustParse(userID))
+}
+
+// fields converts a request that already passed Valid into the columns of
+// a rule, checking that the account, category and payee belong to the user.
+// ID and UserID are left for the caller.
+func (s *ruleService) fields(ctx context.Context, userID uuid.UUID, req *RuleReq) (*db.UpdateRuleParams, error) {
+	c, a := req.Conditions, req.Actions
+
+	fields := &db.UpdateRuleParams{
+		Name:                req.Name,
+		Priority:            req.Priority,
+		Enabled:             req.Enabled == nil || *req.Enabled,
+		DescriptionContains: pgtype.Text{String: c.DescriptionContains, Valid: c.DescriptionContains != ""},
+		DescriptionRegex:    pgtype.Text{String: c.DescriptionRegex, Valid: c.DescriptionRegex != ""},
+		AddTags:             a.Tags,
+		SetDescription:      pgtype.Text{String: a.Description, Valid: a.Description != ""},
+	}
+
+	if c.MinAmount != nil {
+		fields.MinAmount = pgtype.Int8{Int64: c.MinAmount.Cents(), Valid: true}
+	}
+
+	if c.MaxAmount != nil {
+		fields.MaxAmount = pgtype.Int8{Int64: c.MaxAmount.Cents(), Valid: true}
+	}
+
+	if c.Type != "" {
+		fields.Type = db.NullTransactionType{TransactionType: db.TransactionType(c.Type), Valid: true}
+	}
+
+	if c.AccountID != "" {
+		id := uuid.MustParse(c.AccountID)
+		if _, err := s.accounts.GetAccount(ctx, id, userID); err != nil {
+			if errors.Is(err, account.ErrAccountNotFound) {
+				return nil, ErrInvalidAccount
+			}
+			return nil, fmt.Errorf("service check account: %w", err)
+		}
+		fields.AccountID = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	if a.CategoryID != "" {
+		id := uuid.MustParse(a.CategoryID)
+		record, err := s.categories.GetCategory(ctx, id, userID)
+		if err != nil {
+			if errors.Is(err, category.ErrCategoryNotFound) {
+				return nil, ErrInvalidCategory
+			}
+			return nil, fmt.Errorf("service check category: %w", err)
+		}
+		if string(record.Type) != c.Type {
+			return nil, ErrCategoryType
+		}
+		fields.SetCategoryID = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	if a.PayeeID != "" {
+		id := uuid.MustParse(a.PayeeID)
+		if _, err := s.payees.GetPayee(ctx, id, userID); err != nil {
+			if errors.Is(err, payee.ErrPayeeNotFound) {
+				return nil, ErrInvalidPayee
+			}
+			return nil, fmt.Errorf("service check payee: %w", err)
+		}
+		fields.SetPayeeID = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	return fields, nil
+}
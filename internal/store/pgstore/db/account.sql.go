@@ -8,24 +8,70 @@ package db
 import (
 	"context"
 
+	"github.com/EduardoMark/my-finance-api/pkg/money"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const accountExists = `-- name: AccountExists :one
+select exists(select 1 from accounts where id = $1)
+`
+
+func (q *Queries) AccountExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, accountExists, id)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const adjustAccountBalance = `-- name: AdjustAccountBalance :execrows
+UPDATE accounts
+SET balance = balance + $1::bigint,
+    updated_at = now()
+WHERE id = $2
+  AND ($1 >= 0 OR liability OR balance + $1 >= 0)
+`
+
+type AdjustAccountBalanceParams struct {
+	Delta int64     `json:"delta"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// Only liabilities may end up with a negative balance. Money coming in is
+// always accepted, so an overdrawn account can still be settled.
+func (q *Queries) AdjustAccountBalance(ctx context.Context, arg AdjustAccountBalanceParams) (int64, error) {
+	result, err := q.db.Exec(ctx, adjustAccountBalance, arg.Delta, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const createAccount = `-- name: CreateAccount :exec
 INSERT INTO accounts (
   user_id,
   name,
   type,
-  balance
-) VALUES ($1, $2, $3, $4)
+  balance,
+  credit_limit,
+  closing_day,
+  due_day,
+  currency
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7,
+  coalesce($8::varchar, (SELECT base_currency FROM users WHERE id = $1))
+)
 `
 
 type CreateAccountParams struct {
-	UserID  uuid.UUID     `json:"user_id"`
-	Name    string        `json:"name"`
-	Type    string        `json:"type"`
-	Balance pgtype.Float8 `json:"balance"`
+	UserID      uuid.UUID   `json:"user_id"`
+	Name        string      `json:"name"`
+	Type        AccountType `json:"type"`
+	Balance     money.Money `json:"balance"`
+	CreditLimit pgtype.Int8 `json:"credit_limit"`
+	ClosingDay  pgtype.Int2 `json:"closing_day"`
+	DueDay      pgtype.Int2 `json:"due_day"`
+	Currency    pgtype.Text `json:"currency"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) error {
@@ -34,25 +80,39 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) er
 		arg.Name,
 		arg.Type,
 		arg.Balance,
+		arg.CreditLimit,
+		arg.ClosingDay,
+		arg.DueDay,
+		arg.Currency,
 	)
 	return err
 }
 
 const deleteAccount = `-- name: DeleteAccount :exec
-DELETE FROM accounts WHERE id = $1
+DELETE FROM accounts WHERE id = $1 AND user_id = $2
 `
 
-func (q *Queries) DeleteAccount(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteAccount, id)
+type DeleteAccountParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteAccount(ctx context.Context, arg DeleteAccountParams) error {
+	_, err := q.db.Exec(ctx, deleteAccount, arg.ID, arg.UserID)
 	return err
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, user_id, name, type, balance, created_at, updated_at FROM accounts WHERE id = $1
+SELECT id, user_id, name, type, balance, created_at, updated_at, liability, credit_limit, closing_day, due_day, currency FROM accounts WHERE id = $1 AND user_id = $2
 `
 
-func (q *Queries) GetAccount(ctx context.Context, id uuid.UUID) (*Account, error) {
-	row := q.db.QueryRow(ctx, getAccount, id)
+type GetAccountParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetAccount(ctx context.Context, arg GetAccountParams) (*Account, error) {
+	row := q.db.QueryRow(ctx, getAccount, arg.ID, arg.UserID)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -62,12 +122,40 @@ func (q *Queries) GetAccount(ctx context.Context, id uuid.UUID) (*Account, error
 		&i.Balance,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Liability,
+		&i.CreditLimit,
+		&i.ClosingDay,
+		&i.DueDay,
+		&i.Currency,
 	)
 	return &i, err
 }
 
+const getAccountLedgerSummary = `-- name: GetAccountLedgerSummary :one
+select min(t.date)::date as first_date,
+       coalesce(bool_or(t.clearing_status = 'reconciled'), false)::bool as reconciled,
+       coalesce(bool_or(t.transfer_id is not null), false)::bool as transfers
+  from transactions t
+ where t.account_id = $1
+`
+
+type GetAccountLedgerSummaryRow struct {
+	FirstDate  pgtype.Date `json:"first_date"`
+	Reconciled bool        `json:"reconciled"`
+	Transfers  bool        `json:"transfers"`
+}
+
+// Deleting an account deletes its transactions. first_date is NULL while the
+// account has none.
+func (q *Queries) GetAccountLedgerSummary(ctx context.Context, accountID uuid.UUID) (*GetAccountLedgerSummaryRow, error) {
+	row := q.db.QueryRow(ctx, getAccountLedgerSummary, accountID)
+	var i GetAccountLedgerSummaryRow
+	err := row.Scan(&i.FirstDate, &i.Reconciled, &i.Transfers)
+	return &i, err
+}
+
 const getAccountsByUserId = `-- name: GetAccountsByUserId :many
-SELECT id, user_id, name, type, balance, created_at, updated_at FROM accounts WHERE user_id = $1
+SELECT id, user_id, name, type, balance, created_at, updated_at, liability, credit_limit, closing_day, due_day, currency FROM accounts WHERE user_id = $1
 `
 
 func (q *Queries) GetAccountsByUserId(ctx context.Context, userID uuid.UUID) ([]*Account, error) {
@@ -87,6 +175,11 @@ func (q *Queries) GetAccountsByUserId(ctx context.Context, userID uuid.UUID) ([]
 			&i.Balance,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Liability,
+			&i.CreditLimit,
+			&i.ClosingDay,
+			&i.DueDay,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
@@ -102,17 +195,32 @@ const updateAccount = `-- name: UpdateAccount :exec
 UPDATE accounts
 SET name = $2,
     type = $3,
+    credit_limit = $4,
+    closing_day = $5,
+    due_day = $6,
     updated_at = now()
-WHERE id = $1
+WHERE id = $1 AND user_id = $7
 `
 
 type UpdateAccountParams struct {
-	ID   uuid.UUID `json:"id"`
-	Name string    `json:"name"`
-	Type string    `json:"type"`
+	ID          uuid.UUID   `json:"id"`
+	Name        string      `json:"name"`
+	Type        AccountType `json:"type"`
+	CreditLimit pgtype.Int8 `json:"credit_limit"`
+	ClosingDay  pgtype.Int2 `json:"closing_day"`
+	DueDay      pgtype.Int2 `json:"due_day"`
+	UserID      uuid.UUID   `json:"user_id"`
 }
 
 func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) error {
-	_, err := q.db.Exec(ctx, updateAccount, arg.ID, arg.Name, arg.Type)
+	_, err := q.db.Exec(ctx, updateAccount,
+		arg.ID,
+		arg.Name,
+		arg.Type,
+		arg.CreditLimit,
+		arg.ClosingDay,
+		arg.DueDay,
+		arg.UserID,
+	)
 	return err
 }
@@ -34,10 +34,16 @@ func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams)
 const deleteCategory = `-- name: DeleteCategory :exec
 delete from categories
  where id = $1
+   and user_id = $2
 `
 
-func (q *Queries) DeleteCategory(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteCategory, id)
+type DeleteCategoryParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteCategory(ctx context.Context, arg DeleteCategoryParams) error {
+	_, err := q.db.Exec(ctx, deleteCategory, arg.ID, arg.UserID)
 	return err
 }
 
@@ -78,10 +84,16 @@ const getCategory = `-- name: GetCategory :one
 select id, name, type, user_id, created_at, updated_at
   from categories
  where id = $1
+   and user_id = $2
 `
 
-func (q *Queries) GetCategory(ctx context.Context, id uuid.UUID) (*Category, error) {
-	row := q.db.QueryRow(ctx, getCategory, id)
+type GetCategoryParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetCategory(ctx context.Context, arg GetCategoryParams) (*Category, error) {
+	row := q.db.QueryRow(ctx, getCategory, arg.ID, arg.UserID)
 	var i Category
 	err := row.Scan(
 		&i.ID,
@@ -100,15 +112,22 @@ update categories
        type = $3,
        updated_at = now()
  where id = $1
+   and user_id = $4
 `
 
 type UpdateCategoryParams struct {
-	ID   uuid.UUID       `json:"id"`
-	Name string          `json:"name"`
-	Type TransactionType `json:"type"`
+	ID     uuid.UUID       `json:"id"`
+	Name   string          `json:"name"`
+	Type   TransactionType `json:"type"`
+	UserID uuid.UUID       `json:"user_id"`
 }
 
 func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams) error {
-	_, err := q.db.Exec(ctx, updateCategory, arg.ID, arg.Name, arg.Type)
+	_, err := q.db.Exec(ctx, updateCategory,
+		arg.ID,
+		arg.Name,
+		arg.Type,
+		arg.UserID,
+	)
 	return err
 }
@@ -8,15 +8,195 @@ import (
 	"database/sql/driver"
 	"fmt"
 
+	"github.com/EduardoMark/my-finance-api/pkg/money"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AccountType string
+
+const (
+	AccountTypeChecking   AccountType = "checking"
+	AccountTypeSavings    AccountType = "savings"
+	AccountTypeCash       AccountType = "cash"
+	AccountTypeCreditCard AccountType = "credit_card"
+	AccountTypeLoan       AccountType = "loan"
+	AccountTypeInvestment AccountType = "investment"
+	AccountTypeWallet     AccountType = "wallet"
+)
+
+func (e *AccountType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AccountType(s)
+	case string:
+		*e = AccountType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AccountType: %T", src)
+	}
+	return nil
+}
+
+type NullAccountType struct {
+	AccountType AccountType `json:"account_type"`
+	Valid       bool        `json:"valid"` // Valid is true if AccountType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAccountType) Scan(value interface{}) error {
+	if value == nil {
+		ns.AccountType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AccountType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAccountType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AccountType), nil
+}
+
+type BookClosingAction string
+
+const (
+	BookClosingActionClose  BookClosingAction = "close"
+	BookClosingActionReopen BookClosingAction = "reopen"
+)
+
+func (e *BookClosingAction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = BookClosingAction(s)
+	case string:
+		*e = BookClosingAction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for BookClosingAction: %T", src)
+	}
+	return nil
+}
+
+type NullBookClosingAction struct {
+	BookClosingAction BookClosingAction `json:"book_closing_action"`
+	Valid             bool              `json:"valid"` // Valid is true if BookClosingAction is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullBookClosingAction) Scan(value interface{}) error {
+	if value == nil {
+		ns.BookClosingAction, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.BookClosingAction.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullBookClosingAction) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.BookClosingAction), nil
+}
+
+type ClearingStatus string
+
+const (
+	ClearingStatusUncleared  ClearingStatus = "uncleared"
+	ClearingStatusCleared    ClearingStatus = "cleared"
+	ClearingStatusReconciled ClearingStatus = "reconciled"
+)
+
+func (e *ClearingStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ClearingStatus(s)
+	case string:
+		*e = ClearingStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ClearingStatus: %T", src)
+	}
+	return nil
+}
+
+type NullClearingStatus struct {
+	ClearingStatus ClearingStatus `json:"clearing_status"`
+	Valid          bool           `json:"valid"` // Valid is true if ClearingStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullClearingStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ClearingStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ClearingStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullClearingStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ClearingStatus), nil
+}
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceFrequencyDaily           RecurrenceFrequency = "daily"
+	RecurrenceFrequencyWeekly          RecurrenceFrequency = "weekly"
+	RecurrenceFrequencyMonthly         RecurrenceFrequency = "monthly"
+	RecurrenceFrequencyLastBusinessDay RecurrenceFrequency = "last_business_day"
+	RecurrenceFrequencyYearly          RecurrenceFrequency = "yearly"
+)
+
+func (e *RecurrenceFrequency) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = RecurrenceFrequency(s)
+	case string:
+		*e = RecurrenceFrequency(s)
+	default:
+		return fmt.Errorf("unsupported scan type for RecurrenceFrequency: %T", src)
+	}
+	return nil
+}
+
+type NullRecurrenceFrequency struct {
+	RecurrenceFrequency RecurrenceFrequency `json:"recurrence_frequency"`
+	Valid               bool                `json:"valid"` // Valid is true if RecurrenceFrequency is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullRecurrenceFrequency) Scan(value interface{}) error {
+	if value == nil {
+		ns.RecurrenceFrequency, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.RecurrenceFrequency.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullRecurrenceFrequency) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.RecurrenceFrequency), nil
+}
+
 type TransactionType string
 
 const (
-	TransactionTypeIncome  TransactionType = "income"
-	TransactionTypeExpense TransactionType = "expense"
+	TransactionTypeIncome      TransactionType = "income"
+	TransactionTypeExpense     TransactionType = "expense"
+	TransactionTypeTransferIn  TransactionType = "transfer_in"
+	TransactionTypeTransferOut TransactionType = "transfer_out"
 )
 
 func (e *TransactionType) Scan(src interface{}) error {
@@ -54,16 +234,98 @@ func (ns NullTransactionType) Value() (driver.Value, error) {
 	return string(ns.TransactionType), nil
 }
 
+type UserRole string
+
+const (
+	UserRoleUser  UserRole = "user"
+	UserRoleAdmin UserRole = "admin"
+)
+
+func (e *UserRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = UserRole(s)
+	case string:
+		*e = UserRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for UserRole: %T", src)
+	}
+	return nil
+}
+
+type NullUserRole struct {
+	UserRole UserRole `json:"user_role"`
+	Valid    bool     `json:"valid"` // Valid is true if UserRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullUserRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.UserRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.UserRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullUserRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.UserRole), nil
+}
+
 type Account struct {
-	ID        uuid.UUID          `json:"id"`
-	UserID    uuid.UUID          `json:"user_id"`
-	Name      string             `json:"name"`
-	Type      string             `json:"type"`
-	Balance   pgtype.Float8      `json:"balance"`
+	ID     uuid.UUID   `json:"id"`
+	UserID uuid.UUID   `json:"user_id"`
+	Name   string      `json:"name"`
+	Type   AccountType `json:"type"`
+	// minor units (cents)
+	Balance   money.Money        `json:"balance"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	Liability bool               `json:"liability"`
+	// minor units (cents)
+	CreditLimit pgtype.Int8 `json:"credit_limit"`
+	ClosingDay  pgtype.Int2 `json:"closing_day"`
+	DueDay      pgtype.Int2 `json:"due_day"`
+	Currency    string      `json:"currency"`
+}
+
+type BookClosing struct {
+	UserID        uuid.UUID          `json:"user_id"`
+	ClosedThrough pgtype.Date        `json:"closed_through"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+type BookClosingEvent struct {
+	ID            uuid.UUID          `json:"id"`
+	UserID        uuid.UUID          `json:"user_id"`
+	Action        BookClosingAction  `json:"action"`
+	PreviousDate  pgtype.Date        `json:"previous_date"`
+	ClosedThrough pgtype.Date        `json:"closed_through"`
+	Reason        pgtype.Text        `json:"reason"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type Budget struct {
+	ID         uuid.UUID   `json:"id"`
+	UserID     uuid.UUID   `json:"user_id"`
+	CategoryID uuid.UUID   `json:"category_id"`
+	Period     pgtype.Date `json:"period"`
+	// minor units (cents)
+	Amount    money.Money        `json:"amount"`
+	Rollover  bool               `json:"rollover"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 }
 
+type CardStatementPayment struct {
+	TransactionID uuid.UUID   `json:"transaction_id"`
+	StatementDate pgtype.Date `json:"statement_date"`
+}
+
 type Category struct {
 	ID        uuid.UUID          `json:"id"`
 	Name      string             `json:"name"`
@@ -73,24 +335,227 @@ type Category struct {
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 }
 
-type Transaction struct {
-	ID          uuid.UUID          `json:"id"`
-	Description string             `json:"description"`
-	Amount      float64            `json:"amount"`
-	Date        pgtype.Date        `json:"date"`
-	Type        TransactionType    `json:"type"`
-	AccountID   uuid.UUID          `json:"account_id"`
-	CategoryID  uuid.UUID          `json:"category_id"`
-	UserID      uuid.UUID          `json:"user_id"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+type ClassifierCategory struct {
+	UserID     uuid.UUID `json:"user_id"`
+	CategoryID uuid.UUID `json:"category_id"`
+	Documents  int32     `json:"documents"`
+	Tokens     int32     `json:"tokens"`
 }
 
-type User struct {
+type ClassifierToken struct {
+	UserID     uuid.UUID `json:"user_id"`
+	CategoryID uuid.UUID `json:"category_id"`
+	Token      string    `json:"token"`
+	Count      int32     `json:"count"`
+}
+
+type ExchangeRate struct {
+	ID           uuid.UUID          `json:"id"`
+	UserID       uuid.UUID          `json:"user_id"`
+	FromCurrency string             `json:"from_currency"`
+	ToCurrency   string             `json:"to_currency"`
+	Date         pgtype.Date        `json:"date"`
+	Rate         pgtype.Numeric     `json:"rate"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Goal struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+	// minor units (cents)
+	TargetAmount money.Money        `json:"target_amount"`
+	TargetDate   pgtype.Date        `json:"target_date"`
+	AccountID    pgtype.UUID        `json:"account_id"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+type GoalContribution struct {
+	TransactionID uuid.UUID          `json:"transaction_id"`
+	GoalID        uuid.UUID          `json:"goal_id"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type ImportProfile struct {
+	ID                uuid.UUID          `json:"id"`
+	UserID            uuid.UUID          `json:"user_id"`
+	Name              string             `json:"name"`
+	Delimiter         string             `json:"delimiter"`
+	HasHeader         bool               `json:"has_header"`
+	DateColumn        string             `json:"date_column"`
+	DateFormat        string             `json:"date_format"`
+	DescriptionColumn string             `json:"description_column"`
+	AmountColumn      string             `json:"amount_column"`
+	DecimalSeparator  string             `json:"decimal_separator"`
+	SignConvention    string             `json:"sign_convention"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Payee struct {
+	ID                uuid.UUID          `json:"id"`
+	UserID            uuid.UUID          `json:"user_id"`
+	Name              string             `json:"name"`
+	DefaultCategoryID pgtype.UUID        `json:"default_category_id"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+}
+
+type PayeeAlias struct {
+	ID        uuid.UUID          `json:"id"`
+	PayeeID   uuid.UUID          `json:"payee_id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	Pattern   string             `json:"pattern"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type Reconciliation struct {
+	ID            uuid.UUID   `json:"id"`
+	UserID        uuid.UUID   `json:"user_id"`
+	AccountID     uuid.UUID   `json:"account_id"`
+	StatementDate pgtype.Date `json:"statement_date"`
+	// minor units (cents)
+	StatementBalance money.Money        `json:"statement_balance"`
+	FinalizedAt      pgtype.Timestamptz `json:"finalized_at"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+}
+
+type RecurringException struct {
+	RecurringID    uuid.UUID          `json:"recurring_id"`
+	OccurrenceDate pgtype.Date        `json:"occurrence_date"`
+	Action         string             `json:"action"`
+	Description    pgtype.Text        `json:"description"`
+	Amount         pgtype.Int8        `json:"amount"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type RecurringTransaction struct {
+	ID               uuid.UUID           `json:"id"`
+	UserID           uuid.UUID           `json:"user_id"`
+	AccountID        uuid.UUID           `json:"account_id"`
+	CategoryID       uuid.UUID           `json:"category_id"`
+	Description      string              `json:"description"`
+	Amount           money.Money         `json:"amount"`
+	Type             TransactionType     `json:"type"`
+	Frequency        RecurrenceFrequency `json:"frequency"`
+	IntervalCount    int32               `json:"interval_count"`
+	DayOfMonth       pgtype.Int4         `json:"day_of_month"`
+	StartDate        pgtype.Date         `json:"start_date"`
+	EndDate          pgtype.Date         `json:"end_date"`
+	MaxOccurrences   pgtype.Int4         `json:"max_occurrences"`
+	OccurrencesCount int32               `json:"occurrences_count"`
+	NextOccurrence   pgtype.Date         `json:"next_occurrence"`
+	CreatedAt        pgtype.Timestamptz  `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz  `json:"updated_at"`
+}
+
+type RefreshToken struct {
+	ID        uuid.UUID          `json:"id"`
+	SessionID uuid.UUID          `json:"session_id"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type Rule struct {
+	ID                  uuid.UUID           `json:"id"`
+	UserID              uuid.UUID           `json:"user_id"`
+	Name                string              `json:"name"`
+	Priority            int32               `json:"priority"`
+	Enabled             bool                `json:"enabled"`
+	DescriptionContains pgtype.Text         `json:"description_contains"`
+	DescriptionRegex    pgtype.Text         `json:"description_regex"`
+	MinAmount           pgtype.Int8         `json:"min_amount"`
+	MaxAmount           pgtype.Int8         `json:"max_amount"`
+	AccountID           pgtype.UUID         `json:"account_id"`
+	Type                NullTransactionType `json:"type"`
+	SetCategoryID       pgtype.UUID         `json:"set_category_id"`
+	SetPayeeID          pgtype.UUID         `json:"set_payee_id"`
+	AddTags             []string            `json:"add_tags"`
+	SetDescription      pgtype.Text         `json:"set_description"`
+	CreatedAt           pgtype.Timestamptz  `json:"created_at"`
+	UpdatedAt           pgtype.Timestamptz  `json:"updated_at"`
+}
+
+type Session struct {
+	ID             uuid.UUID          `json:"id"`
+	UserID         uuid.UUID          `json:"user_id"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	RevokedAt      pgtype.Timestamptz `json:"revoked_at"`
+	ImpersonatorID pgtype.UUID        `json:"impersonator_id"`
+}
+
+type Tag struct {
 	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
 	Name      string             `json:"name"`
-	Email     string             `json:"email"`
-	Password  string             `json:"password"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 }
+
+type Transaction struct {
+	ID          uuid.UUID `json:"id"`
+	Description string    `json:"description"`
+	// minor units (cents)
+	Amount            money.Money        `json:"amount"`
+	Date              pgtype.Date        `json:"date"`
+	Type              TransactionType    `json:"type"`
+	AccountID         uuid.UUID          `json:"account_id"`
+	CategoryID        uuid.UUID          `json:"category_id"`
+	UserID            uuid.UUID          `json:"user_id"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	TransferID        pgtype.UUID        `json:"transfer_id"`
+	RecurringID       pgtype.UUID        `json:"recurring_id"`
+	OccurrenceDate    pgtype.Date        `json:"occurrence_date"`
+	ImportHash        pgtype.Text        `json:"import_hash"`
+	InstallmentID     pgtype.UUID        `json:"installment_id"`
+	InstallmentNumber pgtype.Int2        `json:"installment_number"`
+	InstallmentCount  pgtype.Int2        `json:"installment_count"`
+	Currency          string             `json:"currency"`
+	ExchangeRate      pgtype.Numeric     `json:"exchange_rate"`
+	PayeeID           pgtype.UUID        `json:"payee_id"`
+	ClearingStatus    ClearingStatus     `json:"clearing_status"`
+	ReconciliationID  pgtype.UUID        `json:"reconciliation_id"`
+}
+
+type TransactionLine struct {
+	TransactionID uuid.UUID       `json:"transaction_id"`
+	UserID        uuid.UUID       `json:"user_id"`
+	AccountID     uuid.UUID       `json:"account_id"`
+	Date          pgtype.Date     `json:"date"`
+	Type          TransactionType `json:"type"`
+	CategoryID    uuid.UUID       `json:"category_id"`
+	Amount        money.Money     `json:"amount"`
+	Currency      string          `json:"currency"`
+}
+
+type TransactionSplit struct {
+	ID            uuid.UUID   `json:"id"`
+	TransactionID uuid.UUID   `json:"transaction_id"`
+	CategoryID    uuid.UUID   `json:"category_id"`
+	Amount        money.Money `json:"amount"`
+	Memo          string      `json:"memo"`
+	Position      int32       `json:"position"`
+}
+
+type TransactionTag struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	TagID         uuid.UUID `json:"tag_id"`
+}
+
+type User struct {
+	ID           uuid.UUID          `json:"id"`
+	Name         string             `json:"name"`
+	Email        string             `json:"email"`
+	Password     string             `json:"password"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	Role         UserRole           `json:"role"`
+	DisabledAt   pgtype.Timestamptz `json:"disabled_at"`
+	BaseCurrency string             `json:"base_currency"`
+}
@@ -8,11 +8,12 @@ package db
 import (
 	"context"
 
+	"github.com/EduardoMark/my-finance-api/pkg/money"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createTransaction = `-- name: CreateTransaction :exec
+const createImportedTransaction = `-- name: CreateImportedTransaction :one
 insert into transactions (
   description,
   amount,
@@ -20,23 +21,125 @@ insert into transactions (
   type,
   user_id,
   account_id,
-  category_id
+  category_id,
+  import_hash,
+  payee_id
 )
-values ($1, $2, $3, $4, $5, $6, $7)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+on conflict (account_id, import_hash) do nothing
+returning id
+`
+
+type CreateImportedTransactionParams struct {
+	Description string          `json:"description"`
+	Amount      money.Money     `json:"amount"`
+	Date        pgtype.Date     `json:"date"`
+	Type        TransactionType `json:"type"`
+	UserID      uuid.UUID       `json:"user_id"`
+	AccountID   uuid.UUID       `json:"account_id"`
+	CategoryID  uuid.UUID       `json:"category_id"`
+	ImportHash  pgtype.Text     `json:"import_hash"`
+	PayeeID     pgtype.UUID     `json:"payee_id"`
+}
+
+// Lines whose import_hash already exists in the account are skipped and
+// return no row.
+func (q *Queries) CreateImportedTransaction(ctx context.Context, arg CreateImportedTransactionParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createImportedTransaction,
+		arg.Description,
+		arg.Amount,
+		arg.Date,
+		arg.Type,
+		arg.UserID,
+		arg.AccountID,
+		arg.CategoryID,
+		arg.ImportHash,
+		arg.PayeeID,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createInstallment = `-- name: CreateInstallment :one
+insert into transactions (
+  description,
+  amount,
+  date,
+  type,
+  user_id,
+  account_id,
+  category_id,
+  installment_id,
+  installment_number,
+  installment_count,
+  payee_id
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+returning id
+`
+
+type CreateInstallmentParams struct {
+	Description       string          `json:"description"`
+	Amount            money.Money     `json:"amount"`
+	Date              pgtype.Date     `json:"date"`
+	Type              TransactionType `json:"type"`
+	UserID            uuid.UUID       `json:"user_id"`
+	AccountID         uuid.UUID       `json:"account_id"`
+	CategoryID        uuid.UUID       `json:"category_id"`
+	InstallmentID     pgtype.UUID     `json:"installment_id"`
+	InstallmentNumber pgtype.Int2     `json:"installment_number"`
+	InstallmentCount  pgtype.Int2     `json:"installment_count"`
+	PayeeID           pgtype.UUID     `json:"payee_id"`
+}
+
+func (q *Queries) CreateInstallment(ctx context.Context, arg CreateInstallmentParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createInstallment,
+		arg.Description,
+		arg.Amount,
+		arg.Date,
+		arg.Type,
+		arg.UserID,
+		arg.AccountID,
+		arg.CategoryID,
+		arg.InstallmentID,
+		arg.InstallmentNumber,
+		arg.InstallmentCount,
+		arg.PayeeID,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createTransaction = `-- name: CreateTransaction :one
+insert into transactions (
+  description,
+  amount,
+  date,
+  type,
+  user_id,
+  account_id,
+  category_id,
+  payee_id
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8)
+returning id
 `
 
 type CreateTransactionParams struct {
 	Description string          `json:"description"`
-	Amount      float64         `json:"amount"`
+	Amount      money.Money     `json:"amount"`
 	Date        pgtype.Date     `json:"date"`
 	Type        TransactionType `json:"type"`
 	UserID      uuid.UUID       `json:"user_id"`
 	AccountID   uuid.UUID       `json:"account_id"`
 	CategoryID  uuid.UUID       `json:"category_id"`
+	PayeeID     pgtype.UUID     `json:"payee_id"`
 }
 
-func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) error {
-	_, err := q.db.Exec(ctx, createTransaction,
+func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, createTransaction,
 		arg.Description,
 		arg.Amount,
 		arg.Date,
@@ -44,28 +147,100 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		arg.UserID,
 		arg.AccountID,
 		arg.CategoryID,
+		arg.PayeeID,
+	)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createTransactionSplit = `-- name: CreateTransactionSplit :exec
+insert into transaction_splits (
+  transaction_id,
+  category_id,
+  amount,
+  memo,
+  position
+)
+values ($1, $2, $3, $4, $5)
+`
+
+type CreateTransactionSplitParams struct {
+	TransactionID uuid.UUID   `json:"transaction_id"`
+	CategoryID    uuid.UUID   `json:"category_id"`
+	Amount        money.Money `json:"amount"`
+	Memo          string      `json:"memo"`
+	Position      int32       `json:"position"`
+}
+
+func (q *Queries) CreateTransactionSplit(ctx context.Context, arg CreateTransactionSplitParams) error {
+	_, err := q.db.Exec(ctx, createTransactionSplit,
+		arg.TransactionID,
+		arg.CategoryID,
+		arg.Amount,
+		arg.Memo,
+		arg.Position,
 	)
 	return err
 }
 
+const deleteInstallments = `-- name: DeleteInstallments :exec
+delete from transactions
+ where installment_id = $1
+   and user_id = $2
+`
+
+type DeleteInstallmentsParams struct {
+	InstallmentID pgtype.UUID `json:"installment_id"`
+	UserID        uuid.UUID   `json:"user_id"`
+}
+
+func (q *Queries) DeleteInstallments(ctx context.Context, arg DeleteInstallmentsParams) error {
+	_, err := q.db.Exec(ctx, deleteInstallments, arg.InstallmentID, arg.UserID)
+	return err
+}
+
 const deleteTransaction = `-- name: DeleteTransaction :exec
 delete from transactions
  where id = $1
+   and user_id = $2
 `
 
-func (q *Queries) DeleteTransaction(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteTransaction, id)
+type DeleteTransactionParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteTransaction(ctx context.Context, arg DeleteTransactionParams) error {
+	_, err := q.db.Exec(ctx, deleteTransaction, arg.ID, arg.UserID)
 	return err
 }
 
-const getAllTransactions = `-- name: GetAllTransactions :many
-select id, description, amount, date, type, account_id, category_id, user_id, created_at, updated_at
+const deleteTransactionSplits = `-- name: DeleteTransactionSplits :exec
+delete from transaction_splits
+ where transaction_id = $1
+`
+
+func (q *Queries) DeleteTransactionSplits(ctx context.Context, transactionID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteTransactionSplits, transactionID)
+	return err
+}
+
+const getInstallments = `-- name: GetInstallments :many
+select id, description, amount, date, type, account_id, category_id, user_id, created_at, updated_at, transfer_id, recurring_id, occurrence_date, import_hash, installment_id, installment_number, installment_count, currency, exchange_rate, payee_id, clearing_status, reconciliation_id
   from transactions
- where user_id = $1
+ where installment_id = $1
+   and user_id = $2
+ order by installment_number
 `
 
-func (q *Queries) GetAllTransactions(ctx context.Context, userID uuid.UUID) ([]*Transaction, error) {
-	rows, err := q.db.Query(ctx, getAllTransactions, userID)
+type GetInstallmentsParams struct {
+	InstallmentID pgtype.UUID `json:"installment_id"`
+	UserID        uuid.UUID   `json:"user_id"`
+}
+
+func (q *Queries) GetInstallments(ctx context.Context, arg GetInstallmentsParams) ([]*Transaction, error) {
+	rows, err := q.db.Query(ctx, getInstallments, arg.InstallmentID, arg.UserID)
 	if err != nil {
 		return nil, err
 	}
@@ -84,6 +259,18 @@ func (q *Queries) GetAllTransactions(ctx context.Context, userID uuid.UUID) ([]*
 			&i.UserID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.TransferID,
+			&i.RecurringID,
+			&i.OccurrenceDate,
+			&i.ImportHash,
+			&i.InstallmentID,
+			&i.InstallmentNumber,
+			&i.InstallmentCount,
+			&i.Currency,
+			&i.ExchangeRate,
+			&i.PayeeID,
+			&i.ClearingStatus,
+			&i.ReconciliationID,
 		); err != nil {
 			return nil, err
 		}
@@ -95,14 +282,22 @@ func (q *Queries) GetAllTransactions(ctx context.Context, userID uuid.UUID) ([]*
 	return items, nil
 }
 
-const getAllTransactionsByAccount = `-- name: GetAllTransactionsByAccount :many
-select id, description, amount, date, type, account_id, category_id, user_id, created_at, updated_at
+const getInstallmentsForUpdate = `-- name: GetInstallmentsForUpdate :many
+select id, description, amount, date, type, account_id, category_id, user_id, created_at, updated_at, transfer_id, recurring_id, occurrence_date, import_hash, installment_id, installment_number, installment_count, currency, exchange_rate, payee_id, clearing_status, reconciliation_id
   from transactions
- where account_id = $1
+ where installment_id = $1
+   and user_id = $2
+ order by installment_number
+   for update
 `
 
-func (q *Queries) GetAllTransactionsByAccount(ctx context.Context, accountID uuid.UUID) ([]*Transaction, error) {
-	rows, err := q.db.Query(ctx, getAllTransactionsByAccount, accountID)
+type GetInstallmentsForUpdateParams struct {
+	InstallmentID pgtype.UUID `json:"installment_id"`
+	UserID        uuid.UUID   `json:"user_id"`
+}
+
+func (q *Queries) GetInstallmentsForUpdate(ctx context.Context, arg GetInstallmentsForUpdateParams) ([]*Transaction, error) {
+	rows, err := q.db.Query(ctx, getInstallmentsForUpdate, arg.InstallmentID, arg.UserID)
 	if err != nil {
 		return nil, err
 	}
@@ -121,6 +316,18 @@ func (q *Queries) GetAllTransactionsByAccount(ctx context.Context, accountID uui
 			&i.UserID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.TransferID,
+			&i.RecurringID,
+			&i.OccurrenceDate,
+			&i.ImportHash,
+			&i.InstallmentID,
+			&i.InstallmentNumber,
+			&i.InstallmentCount,
+			&i.Currency,
+			&i.ExchangeRate,
+			&i.PayeeID,
+			&i.ClearingStatus,
+			&i.ReconciliationID,
 		); err != nil {
 			return nil, err
 		}
@@ -132,32 +339,98 @@ func (q *Queries) GetAllTransactionsByAccount(ctx context.Context, accountID uui
 	return items, nil
 }
 
-const getAllTransactionsByCategory = `-- name: GetAllTransactionsByCategory :many
-select id, description, amount, date, type, account_id, category_id, user_id, created_at, updated_at
+const getSplitTransactionIDs = `-- name: GetSplitTransactionIDs :many
+select distinct transaction_id
+  from transaction_splits
+ where transaction_id = any($1::uuid[])
+`
+
+func (q *Queries) GetSplitTransactionIDs(ctx context.Context, transactionIds []uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, getSplitTransactionIDs, transactionIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var transaction_id uuid.UUID
+		if err := rows.Scan(&transaction_id); err != nil {
+			return nil, err
+		}
+		items = append(items, transaction_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTransactionForUpdate = `-- name: GetTransactionForUpdate :one
+select id, description, amount, date, type, account_id, category_id, user_id, created_at, updated_at, transfer_id, recurring_id, occurrence_date, import_hash, installment_id, installment_number, installment_count, currency, exchange_rate, payee_id, clearing_status, reconciliation_id
   from transactions
- where category_id = $1
+ where id = $1
+   and user_id = $2
+   for update
 `
 
-func (q *Queries) GetAllTransactionsByCategory(ctx context.Context, categoryID uuid.UUID) ([]*Transaction, error) {
-	rows, err := q.db.Query(ctx, getAllTransactionsByCategory, categoryID)
+type GetTransactionForUpdateParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetTransactionForUpdate(ctx context.Context, arg GetTransactionForUpdateParams) (*Transaction, error) {
+	row := q.db.QueryRow(ctx, getTransactionForUpdate, arg.ID, arg.UserID)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.Description,
+		&i.Amount,
+		&i.Date,
+		&i.Type,
+		&i.AccountID,
+		&i.CategoryID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TransferID,
+		&i.RecurringID,
+		&i.OccurrenceDate,
+		&i.ImportHash,
+		&i.InstallmentID,
+		&i.InstallmentNumber,
+		&i.InstallmentCount,
+		&i.Currency,
+		&i.ExchangeRate,
+		&i.PayeeID,
+		&i.ClearingStatus,
+		&i.ReconciliationID,
+	)
+	return &i, err
+}
+
+const getTransactionSplits = `-- name: GetTransactionSplits :many
+select id, transaction_id, category_id, amount, memo, position
+  from transaction_splits
+ where transaction_id = $1
+ order by position
+`
+
+func (q *Queries) GetTransactionSplits(ctx context.Context, transactionID uuid.UUID) ([]*TransactionSplit, error) {
+	rows, err := q.db.Query(ctx, getTransactionSplits, transactionID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []*Transaction
+	var items []*TransactionSplit
 	for rows.Next() {
-		var i Transaction
+		var i TransactionSplit
 		if err := rows.Scan(
 			&i.ID,
-			&i.Description,
-			&i.Amount,
-			&i.Date,
-			&i.Type,
-			&i.AccountID,
+			&i.TransactionID,
 			&i.CategoryID,
-			&i.UserID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.Amount,
+			&i.Memo,
+			&i.Position,
 		); err != nil {
 			return nil, err
 		}
@@ -170,13 +443,19 @@ func (q *Queries) GetAllTransactionsByCategory(ctx context.Context, categoryID u
 }
 
 const getTrasaction = `-- name: GetTrasaction :one
-select id, description, amount, date, type, account_id, category_id, user_id, created_at, updated_at
+select id, description, amount, date, type, account_id, category_id, user_id, created_at, updated_at, transfer_id, recurring_id, occurrence_date, import_hash, installment_id, installment_number, installment_count, currency, exchange_rate, payee_id, clearing_status, reconciliation_id
   from transactions
  where id = $1
+   and user_id = $2
 `
 
-func (q *Queries) GetTrasaction(ctx context.Context, id uuid.UUID) (*Transaction, error) {
-	row := q.db.QueryRow(ctx, getTrasaction, id)
+type GetTrasactionParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetTrasaction(ctx context.Context, arg GetTrasactionParams) (*Transaction, error) {
+	row := q.db.QueryRow(ctx, getTrasaction, arg.ID, arg.UserID)
 	var i Transaction
 	err := row.Scan(
 		&i.ID,
@@ -189,26 +468,300 @@ func (q *Queries) GetTrasaction(ctx context.Context, id uuid.UUID) (*Transaction
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TransferID,
+		&i.RecurringID,
+		&i.OccurrenceDate,
+		&i.ImportHash,
+		&i.InstallmentID,
+		&i.InstallmentNumber,
+		&i.InstallmentCount,
+		&i.Currency,
+		&i.ExchangeRate,
+		&i.PayeeID,
+		&i.ClearingStatus,
+		&i.ReconciliationID,
 	)
 	return &i, err
 }
 
+const listRuleCandidates = `-- name: ListRuleCandidates :many
+select id, description, amount, date, type, account_id, category_id, user_id, created_at, updated_at, transfer_id, recurring_id, occurrence_date, import_hash, installment_id, installment_number, installment_count, currency, exchange_rate, payee_id, clearing_status, reconciliation_id
+  from transactions
+ where transactions.user_id = $1
+   and type in ('income', 'expense')
+   and clearing_status <> 'reconciled'
+   and date > coalesce((select b.closed_through from book_closings b where b.user_id = $1), '-infinity')
+   and ($2::uuid is null or account_id = $2)
+   and ($3::date is null or date >= $3)
+   and ($4::date is null or date <= $4)
+ order by date, id
+ limit $5::int
+`
+
+type ListRuleCandidatesParams struct {
+	UserID    uuid.UUID   `json:"user_id"`
+	AccountID pgtype.UUID `json:"account_id"`
+	StartDate pgtype.Date `json:"start_date"`
+	EndDate   pgtype.Date `json:"end_date"`
+	RowLimit  int32       `json:"row_limit"`
+}
+
+// Income and expenses that rules can change, oldest first. Reconciled
+// transactions and those in a closed period are locked and left out.
+func (q *Queries) ListRuleCandidates(ctx context.Context, arg ListRuleCandidatesParams) ([]*Transaction, error) {
+	rows, err := q.db.Query(ctx, listRuleCandidates,
+		arg.UserID,
+		arg.AccountID,
+		arg.StartDate,
+		arg.EndDate,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.Description,
+			&i.Amount,
+			&i.Date,
+			&i.Type,
+			&i.AccountID,
+			&i.CategoryID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TransferID,
+			&i.RecurringID,
+			&i.OccurrenceDate,
+			&i.ImportHash,
+			&i.InstallmentID,
+			&i.InstallmentNumber,
+			&i.InstallmentCount,
+			&i.Currency,
+			&i.ExchangeRate,
+			&i.PayeeID,
+			&i.ClearingStatus,
+			&i.ReconciliationID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransactions = `-- name: ListTransactions :many
+select id, description, amount, date, type, account_id, category_id, user_id, created_at, updated_at, transfer_id, recurring_id, occurrence_date, import_hash, installment_id, installment_number, installment_count, currency, exchange_rate, payee_id, clearing_status, reconciliation_id
+  from transactions
+ where transactions.user_id = $1
+   and ($2::uuid is null or account_id = $2)
+   and ($3::uuid is null or exists (
+         select 1
+           from transaction_lines l
+          where l.transaction_id = transactions.id
+            and l.category_id = $3))
+   and ($4::transaction_type is null or type = $4)
+   and ($5::date is null or date >= $5)
+   and ($6::date is null or date <= $6)
+   and ($7::bigint is null or amount >= $7)
+   and ($8::bigint is null or amount <= $8)
+   and ($9::text is null or strpos(lower(description), lower($9)) > 0)
+   and ($10::uuid is null or payee_id = $10)
+   and ($11::text[] is null or (
+         select count(*)
+           from transaction_tags tt
+           join tags tg on tg.id = tt.tag_id
+          where tt.transaction_id = transactions.id
+            and tg.name = any($11)) = cardinality($11))
+   and ($12::text[] is null or exists (
+         select 1
+           from transaction_tags tt
+           join tags tg on tg.id = tt.tag_id
+          where tt.transaction_id = transactions.id
+            and tg.name = any($12)))
+   and ($13::text[] is null or not exists (
+         select 1
+           from transaction_tags tt
+           join tags tg on tg.id = tt.tag_id
+          where tt.transaction_id = transactions.id
+            and tg.name = any($13)))
+   and ($14::uuid is null or case $15::text
+         when 'date_asc' then (date, id) > ($16::date, $14)
+         when 'date_desc' then (date, id) < ($16, $14)
+         when 'amount_asc' then (amount, id) > ($17::bigint, $14)
+         when 'amount_desc' then (amount, id) < ($17, $14)
+       end)
+ order by
+   case when $15 = 'date_asc' then date end asc,
+   case when $15 = 'date_desc' then date end desc,
+   case when $15 = 'amount_asc' then amount end asc,
+   case when $15 = 'amount_desc' then amount end desc,
+   case when $15 in ('date_asc', 'amount_asc') then id end asc,
+   case when $15 in ('date_desc', 'amount_desc') then id end desc
+ limit $18::int
+`
+
+type ListTransactionsParams struct {
+	UserID       uuid.UUID           `json:"user_id"`
+	AccountID    pgtype.UUID         `json:"account_id"`
+	CategoryID   pgtype.UUID         `json:"category_id"`
+	Type         NullTransactionType `json:"type"`
+	StartDate    pgtype.Date         `json:"start_date"`
+	EndDate      pgtype.Date         `json:"end_date"`
+	MinAmount    pgtype.Int8         `json:"min_amount"`
+	MaxAmount    pgtype.Int8         `json:"max_amount"`
+	Search       pgtype.Text         `json:"search"`
+	PayeeID      pgtype.UUID         `json:"payee_id"`
+	AllTags      []string            `json:"all_tags"`
+	AnyTags      []string            `json:"any_tags"`
+	NoTags       []string            `json:"no_tags"`
+	CursorID     pgtype.UUID         `json:"cursor_id"`
+	Sort         string              `json:"sort"`
+	CursorDate   pgtype.Date         `json:"cursor_date"`
+	CursorAmount pgtype.Int8         `json:"cursor_amount"`
+	PageSize     int32               `json:"page_size"`
+}
+
+// Every filter is optional: a NULL argument disables it. The category filter
+// also matches split lines. all_tags requires every tag, any_tags at least
+// one and no_tags none; the tag lists must not repeat names. The cursor holds
+// the sort key and id of the last row of the previous page.
+func (q *Queries) ListTransactions(ctx context.Context, arg ListTransactionsParams) ([]*Transaction, error) {
+	rows, err := q.db.Query(ctx, listTransactions,
+		arg.UserID,
+		arg.AccountID,
+		arg.CategoryID,
+		arg.Type,
+		arg.StartDate,
+		arg.EndDate,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.Search,
+		arg.PayeeID,
+		arg.AllTags,
+		arg.AnyTags,
+		arg.NoTags,
+		arg.CursorID,
+		arg.Sort,
+		arg.CursorDate,
+		arg.CursorAmount,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.Description,
+			&i.Amount,
+			&i.Date,
+			&i.Type,
+			&i.AccountID,
+			&i.CategoryID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TransferID,
+			&i.RecurringID,
+			&i.OccurrenceDate,
+			&i.ImportHash,
+			&i.InstallmentID,
+			&i.InstallmentNumber,
+			&i.InstallmentCount,
+			&i.Currency,
+			&i.ExchangeRate,
+			&i.PayeeID,
+			&i.ClearingStatus,
+			&i.ReconciliationID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setTransactionClearingStatus = `-- name: SetTransactionClearingStatus :exec
+update transactions
+   set clearing_status = $3,
+       updated_at = now()
+ where id = $1
+   and user_id = $2
+`
+
+type SetTransactionClearingStatusParams struct {
+	ID             uuid.UUID      `json:"id"`
+	UserID         uuid.UUID      `json:"user_id"`
+	ClearingStatus ClearingStatus `json:"clearing_status"`
+}
+
+func (q *Queries) SetTransactionClearingStatus(ctx context.Context, arg SetTransactionClearingStatusParams) error {
+	_, err := q.db.Exec(ctx, setTransactionClearingStatus, arg.ID, arg.UserID, arg.ClearingStatus)
+	return err
+}
+
+const unlockTransaction = `-- name: UnlockTransaction :execrows
+update transactions
+   set clearing_status = 'cleared',
+       updated_at = now()
+ where id = $1
+   and user_id = $2
+   and clearing_status = 'reconciled'
+`
+
+type UnlockTransactionParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// A reconciled transaction goes back to cleared, so it can be edited and is
+// picked up again by the next reconciliation of its account.
+func (q *Queries) UnlockTransaction(ctx context.Context, arg UnlockTransactionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, unlockTransaction, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateTransaction = `-- name: UpdateTransaction :exec
 update transactions
    set description = $2,
    amount = $3,
    date = $4,
    type = $5,
+   account_id = $6,
+   category_id = $7,
+   payee_id = $8,
    updated_at = now()
  where id = $1
+   and user_id = $9
 `
 
 type UpdateTransactionParams struct {
 	ID          uuid.UUID       `json:"id"`
 	Description string          `json:"description"`
-	Amount      float64         `json:"amount"`
+	Amount      money.Money     `json:"amount"`
 	Date        pgtype.Date     `json:"date"`
 	Type        TransactionType `json:"type"`
+	AccountID   uuid.UUID       `json:"account_id"`
+	CategoryID  uuid.UUID       `json:"category_id"`
+	PayeeID     pgtype.UUID     `json:"payee_id"`
+	UserID      uuid.UUID       `json:"user_id"`
 }
 
 func (q *Queries) UpdateTransaction(ctx context.Context, arg UpdateTransactionParams) error {
@@ -218,6 +771,10 @@ func (q *Queries) UpdateTransaction(ctx context.Context, arg UpdateTransactionPa
 		arg.Amount,
 		arg.Date,
 		arg.Type,
+		arg.AccountID,
+		arg.CategoryID,
+		arg.PayeeID,
+		arg.UserID,
 	)
 	return err
 }
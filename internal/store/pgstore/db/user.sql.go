@@ -40,8 +40,34 @@ func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const disableUser = `-- name: DisableUser :exec
+UPDATE users
+SET
+  disabled_at = coalesce(disabled_at, now()),
+  updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) DisableUser(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, disableUser, id)
+	return err
+}
+
+const enableUser = `-- name: EnableUser :exec
+UPDATE users
+SET
+  disabled_at = NULL,
+  updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) EnableUser(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, enableUser, id)
+	return err
+}
+
 const getAllUsers = `-- name: GetAllUsers :many
-SELECT id, name, email, password, created_at, updated_at FROM users
+SELECT id, name, email, password, created_at, updated_at, role, disabled_at, base_currency FROM users
 `
 
 func (q *Queries) GetAllUsers(ctx context.Context) ([]*User, error) {
@@ -60,6 +86,9 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]*User, error) {
 			&i.Password,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Role,
+			&i.DisabledAt,
+			&i.BaseCurrency,
 		); err != nil {
 			return nil, err
 		}
@@ -72,7 +101,7 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]*User, error) {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, name, email, password, created_at, updated_at FROM users WHERE id = $1
+SELECT id, name, email, password, created_at, updated_at, role, disabled_at, base_currency FROM users WHERE id = $1
 `
 
 func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (*User, error) {
@@ -85,12 +114,15 @@ func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (*User, error) {
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.DisabledAt,
+		&i.BaseCurrency,
 	)
 	return &i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, name, email, password, created_at, updated_at FROM users WHERE email = $1
+SELECT id, name, email, password, created_at, updated_at, role, disabled_at, base_currency FROM users WHERE email = $1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (*User, error) {
@@ -103,6 +135,9 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (*User, erro
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.DisabledAt,
+		&i.BaseCurrency,
 	)
 	return &i, err
 }
@@ -113,15 +148,17 @@ SET
   name = $2, 
   email = $3, 
   password = $4, 
+  base_currency = $5,
   updated_at = now() 
 WHERE id=$1
 `
 
 type UpdateUserParams struct {
-	ID       uuid.UUID `json:"id"`
-	Name     string    `json:"name"`
-	Email    string    `json:"email"`
-	Password string    `json:"password"`
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	Password     string    `json:"password"`
+	BaseCurrency string    `json:"base_currency"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) error {
@@ -130,6 +167,7 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) error {
 		arg.Name,
 		arg.Email,
 		arg.Password,
+		arg.BaseCurrency,
 	)
 	return err
 }
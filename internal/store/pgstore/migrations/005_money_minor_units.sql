@@ -0,0 +1,29 @@
+-- Write your migrate up statements here
+-- Money is stored as BIGINT minor units (cents). FLOAT8 values are rounded to
+-- the nearest cent through NUMERIC, which recovers the amounts that were
+-- originally typed in.
+
+UPDATE accounts SET balance = 0 WHERE balance IS NULL;
+
+ALTER TABLE accounts
+  ALTER COLUMN balance TYPE BIGINT USING round(balance::numeric * 100)::bigint,
+  ALTER COLUMN balance SET DEFAULT 0,
+  ALTER COLUMN balance SET NOT NULL;
+
+ALTER TABLE transactions
+  ALTER COLUMN amount TYPE BIGINT USING round(amount::numeric * 100)::bigint;
+
+COMMENT ON COLUMN accounts.balance IS 'minor units (cents)';
+COMMENT ON COLUMN transactions.amount IS 'minor units (cents)';
+
+---- create above / drop below ----
+
+ALTER TABLE transactions
+  ALTER COLUMN amount TYPE FLOAT8 USING amount::numeric / 100;
+
+ALTER TABLE accounts
+  ALTER COLUMN balance DROP NOT NULL,
+  ALTER COLUMN balance TYPE FLOAT8 USING balance::numeric / 100;
+
+COMMENT ON COLUMN accounts.balance IS NULL;
+COMMENT ON COLUMN transactions.amount IS NULL;
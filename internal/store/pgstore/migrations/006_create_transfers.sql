@@ -0,0 +1,20 @@
+-- Write your migrate up statements here
+-- A transfer is a pair of transactions sharing the same transfer_id: a
+-- transfer_out leg on the source account and a transfer_in leg on the
+-- destination account. Transfer legs have no category.
+ALTER TYPE transaction_type ADD VALUE IF NOT EXISTS 'transfer_in';
+ALTER TYPE transaction_type ADD VALUE IF NOT EXISTS 'transfer_out';
+
+ALTER TABLE transactions ADD COLUMN transfer_id UUID;
+
+CREATE INDEX IF NOT EXISTS transactions_transfer_id_idx ON transactions (transfer_id);
+
+---- create above / drop below ----
+
+DELETE FROM transactions WHERE transfer_id IS NOT NULL;
+
+DROP INDEX IF EXISTS transactions_transfer_id_idx;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS transfer_id;
+
+-- Postgres cannot drop enum values, so transfer_in and transfer_out stay.
@@ -0,0 +1,21 @@
+-- Write your migrate up statements here
+-- A budget limits the spending of one expense category in one month. period
+-- is always the first day of that month.
+
+CREATE TABLE IF NOT EXISTS budgets (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+  period DATE NOT NULL CHECK (extract(day FROM period) = 1),
+  amount BIGINT NOT NULL CHECK (amount >= 0),
+  rollover BOOLEAN NOT NULL DEFAULT false,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (user_id, category_id, period)
+);
+
+COMMENT ON COLUMN budgets.amount IS 'minor units (cents)';
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS budgets;
@@ -0,0 +1,61 @@
+-- Write your migrate up statements here
+-- A recurring transaction is a template that the scheduler materializes into
+-- transactions. Occurrence k of a series is computed from start_date, so
+-- month-end clamping (31st -> 28th) never drifts the following occurrences.
+CREATE TYPE recurrence_frequency AS ENUM ('daily', 'weekly', 'monthly', 'last_business_day', 'yearly');
+
+CREATE TABLE IF NOT EXISTS recurring_transactions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+  category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+  description VARCHAR NOT NULL,
+  amount BIGINT NOT NULL CHECK (amount > 0),
+  type transaction_type NOT NULL,
+  frequency recurrence_frequency NOT NULL,
+  interval_count INT NOT NULL DEFAULT 1 CHECK (interval_count > 0),
+  day_of_month INT CHECK (day_of_month BETWEEN 1 AND 31),
+  start_date DATE NOT NULL,
+  end_date DATE,
+  max_occurrences INT CHECK (max_occurrences > 0),
+  occurrences_count INT NOT NULL DEFAULT 0,
+  next_occurrence DATE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS recurring_transactions_next_occurrence_idx
+  ON recurring_transactions (next_occurrence)
+  WHERE next_occurrence IS NOT NULL;
+
+-- Per-occurrence overrides: 'skip' drops the occurrence, 'modify' replaces
+-- its description and/or amount.
+CREATE TABLE IF NOT EXISTS recurring_exceptions (
+  recurring_id UUID NOT NULL REFERENCES recurring_transactions(id) ON DELETE CASCADE,
+  occurrence_date DATE NOT NULL,
+  action VARCHAR NOT NULL CHECK (action IN ('skip', 'modify')),
+  description VARCHAR,
+  amount BIGINT CHECK (amount > 0),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (recurring_id, occurrence_date)
+);
+
+ALTER TABLE transactions
+  ADD COLUMN recurring_id UUID REFERENCES recurring_transactions(id) ON DELETE SET NULL,
+  ADD COLUMN occurrence_date DATE;
+
+-- Materializing the same occurrence twice is a no-op.
+CREATE UNIQUE INDEX IF NOT EXISTS transactions_recurring_occurrence_idx
+  ON transactions (recurring_id, occurrence_date);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS transactions_recurring_occurrence_idx;
+
+ALTER TABLE transactions
+  DROP COLUMN IF EXISTS occurrence_date,
+  DROP COLUMN IF EXISTS recurring_id;
+
+DROP TABLE IF EXISTS recurring_exceptions;
+DROP TABLE IF EXISTS recurring_transactions;
+DROP TYPE IF EXISTS recurrence_frequency;
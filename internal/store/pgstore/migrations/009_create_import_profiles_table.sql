@@ -0,0 +1,35 @@
+-- Write your migrate up statements here
+-- An import profile tells how to read a bank's CSV export. Columns are
+-- header names when has_header is set and 1-based positions otherwise.
+CREATE TABLE IF NOT EXISTS import_profiles (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name VARCHAR NOT NULL,
+  delimiter VARCHAR(1) NOT NULL DEFAULT ',',
+  has_header BOOLEAN NOT NULL DEFAULT true,
+  date_column VARCHAR NOT NULL,
+  date_format VARCHAR NOT NULL,
+  description_column VARCHAR NOT NULL,
+  amount_column VARCHAR NOT NULL,
+  decimal_separator VARCHAR(1) NOT NULL DEFAULT '.' CHECK (decimal_separator IN ('.', ',')),
+  sign_convention VARCHAR NOT NULL DEFAULT 'negative_expense'
+    CHECK (sign_convention IN ('negative_expense', 'positive_expense')),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (user_id, name)
+);
+
+-- import_hash identifies a statement line so importing the same file twice
+-- does not duplicate transactions.
+ALTER TABLE transactions ADD COLUMN import_hash VARCHAR;
+
+CREATE UNIQUE INDEX IF NOT EXISTS transactions_import_hash_idx
+  ON transactions (account_id, import_hash);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS transactions_import_hash_idx;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS import_hash;
+
+DROP TABLE IF EXISTS import_profiles;
@@ -0,0 +1,30 @@
+-- Write your migrate up statements here
+-- A session is a refresh token family. Each refresh rotates the token but
+-- keeps the session; revoking the session logs out every token issued in it.
+CREATE TABLE IF NOT EXISTS sessions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS sessions_user_id_idx ON sessions (user_id);
+
+-- Only the SHA-256 of a refresh token is stored. used_at is set when the
+-- token is rotated, so presenting it again means it was stolen.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+  token_hash VARCHAR NOT NULL UNIQUE,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_session_id_idx ON refresh_tokens (session_id);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS refresh_tokens;
+
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,22 @@
+-- Write your migrate up statements here
+CREATE TYPE user_role AS ENUM ('user', 'admin');
+
+-- There is no endpoint to promote the first admin; run
+-- UPDATE users SET role = 'admin' WHERE email = '...';
+ALTER TABLE users
+  ADD COLUMN role user_role NOT NULL DEFAULT 'user',
+  ADD COLUMN disabled_at TIMESTAMPTZ;
+
+-- impersonator_id is the admin who opened the session on behalf of the user.
+ALTER TABLE sessions
+  ADD COLUMN impersonator_id UUID REFERENCES users(id) ON DELETE CASCADE;
+
+---- create above / drop below ----
+
+ALTER TABLE sessions DROP COLUMN IF EXISTS impersonator_id;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS disabled_at,
+  DROP COLUMN IF EXISTS role;
+
+DROP TYPE IF EXISTS user_role;
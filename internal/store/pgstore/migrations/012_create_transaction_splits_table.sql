@@ -0,0 +1,35 @@
+-- Write your migrate up statements here
+-- A split divides a transaction among categories. Amounts are positive like
+-- the parent amount and add up to it; the service enforces the total. The
+-- parent keeps the category of its first split.
+CREATE TABLE IF NOT EXISTS transaction_splits (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+  category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+  amount BIGINT NOT NULL CHECK (amount > 0),
+  memo VARCHAR NOT NULL DEFAULT '',
+  position INT NOT NULL,
+  UNIQUE (transaction_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS transaction_splits_category_id_idx ON transaction_splits (category_id);
+
+-- transaction_lines has one row per category a transaction counts towards:
+-- its splits, or the transaction itself when it has none. Anything that
+-- groups or filters by category should read from here.
+CREATE OR REPLACE VIEW transaction_lines AS
+SELECT t.id AS transaction_id, t.user_id, t.account_id, t.date, t.type,
+       s.category_id, s.amount
+  FROM transactions t
+  JOIN transaction_splits s ON s.transaction_id = t.id
+UNION ALL
+SELECT t.id AS transaction_id, t.user_id, t.account_id, t.date, t.type,
+       t.category_id, t.amount
+  FROM transactions t
+ WHERE NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id);
+
+---- create above / drop below ----
+
+DROP VIEW IF EXISTS transaction_lines;
+
+DROP TABLE IF EXISTS transaction_splits;
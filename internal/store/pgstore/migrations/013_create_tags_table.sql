@@ -0,0 +1,25 @@
+-- Write your migrate up statements here
+-- Tags are free labels that cut across categories. Names are stored in
+-- lowercase, so they are unique per user regardless of case.
+CREATE TABLE IF NOT EXISTS tags (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name VARCHAR NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS transaction_tags (
+  transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+  tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  PRIMARY KEY (transaction_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS transaction_tags_tag_id_idx ON transaction_tags (tag_id);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS transaction_tags;
+
+DROP TABLE IF EXISTS tags;
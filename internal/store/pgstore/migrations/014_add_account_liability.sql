@@ -0,0 +1,16 @@
+-- Write your migrate up statements here
+-- Liability accounts (credit cards, loans) hold money owed. Their balance
+-- goes negative as it is used and is subtracted from the net worth.
+ALTER TABLE accounts
+  ADD COLUMN liability BOOLEAN NOT NULL DEFAULT false;
+
+-- Balances are rebuilt by summing the transactions of an account after a
+-- date.
+CREATE INDEX IF NOT EXISTS transactions_account_id_date_idx ON transactions (account_id, date);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS transactions_account_id_date_idx;
+
+ALTER TABLE accounts
+  DROP COLUMN IF EXISTS liability;
@@ -0,0 +1,85 @@
+-- Write your migrate up statements here
+CREATE TYPE account_type AS ENUM (
+  'checking',
+  'savings',
+  'cash',
+  'credit_card',
+  'loan',
+  'investment',
+  'wallet'
+);
+
+-- Free-text types are matched by their usual English and Portuguese names,
+-- ignoring case, accents and punctuation. Anything else becomes a checking
+-- account, or a credit card when it was marked as a liability.
+ALTER TABLE accounts
+  ALTER COLUMN type TYPE account_type USING (
+    CASE regexp_replace(
+           lower(translate(btrim(type), 'ÃÁÀÂÇÉÊÍÓÔÕÚãáàâçéêíóôõú', 'AAAACEEIOOOUaaaaceeiooou')),
+           '[^a-z]+', '_', 'g')
+      WHEN 'checking' THEN 'checking'
+      WHEN 'checking_account' THEN 'checking'
+      WHEN 'current' THEN 'checking'
+      WHEN 'current_account' THEN 'checking'
+      WHEN 'bank' THEN 'checking'
+      WHEN 'conta' THEN 'checking'
+      WHEN 'conta_corrente' THEN 'checking'
+      WHEN 'corrente' THEN 'checking'
+      WHEN 'banco' THEN 'checking'
+      WHEN 'savings' THEN 'savings'
+      WHEN 'saving' THEN 'savings'
+      WHEN 'savings_account' THEN 'savings'
+      WHEN 'poupanca' THEN 'savings'
+      WHEN 'conta_poupanca' THEN 'savings'
+      WHEN 'cash' THEN 'cash'
+      WHEN 'dinheiro' THEN 'cash'
+      WHEN 'especie' THEN 'cash'
+      WHEN 'credit_card' THEN 'credit_card'
+      WHEN 'creditcard' THEN 'credit_card'
+      WHEN 'credit' THEN 'credit_card'
+      WHEN 'card' THEN 'credit_card'
+      WHEN 'cartao' THEN 'credit_card'
+      WHEN 'cartao_de_credito' THEN 'credit_card'
+      WHEN 'cartao_credito' THEN 'credit_card'
+      WHEN 'loan' THEN 'loan'
+      WHEN 'debt' THEN 'loan'
+      WHEN 'emprestimo' THEN 'loan'
+      WHEN 'financiamento' THEN 'loan'
+      WHEN 'divida' THEN 'loan'
+      WHEN 'investment' THEN 'investment'
+      WHEN 'investments' THEN 'investment'
+      WHEN 'brokerage' THEN 'investment'
+      WHEN 'investimento' THEN 'investment'
+      WHEN 'investimentos' THEN 'investment'
+      WHEN 'corretora' THEN 'investment'
+      WHEN 'wallet' THEN 'wallet'
+      WHEN 'digital_wallet' THEN 'wallet'
+      WHEN 'e_wallet' THEN 'wallet'
+      WHEN 'carteira' THEN 'wallet'
+      WHEN 'carteira_digital' THEN 'wallet'
+      ELSE CASE WHEN liability THEN 'credit_card' ELSE 'checking' END
+    END
+  )::account_type;
+
+-- Being a liability now follows from the type. A generated column is
+-- nullable unless declared otherwise, and liability never is NULL.
+ALTER TABLE accounts
+  DROP COLUMN liability;
+
+ALTER TABLE accounts
+  ADD COLUMN liability BOOLEAN NOT NULL GENERATED ALWAYS AS (type IN ('credit_card', 'loan')) STORED;
+
+---- create above / drop below ----
+
+ALTER TABLE accounts
+  DROP COLUMN liability;
+
+ALTER TABLE accounts
+  ADD COLUMN liability BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE accounts SET liability = type IN ('credit_card', 'loan');
+
+ALTER TABLE accounts
+  ALTER COLUMN type TYPE VARCHAR(255) USING type::text;
+
+DROP TYPE IF EXISTS account_type;
@@ -0,0 +1,30 @@
+-- Write your migrate up statements here
+-- Credit cards close a statement on closing_day and must be paid by due_day;
+-- in shorter months both fall on the last day. Only credit cards have them.
+ALTER TABLE accounts
+  ADD COLUMN credit_limit BIGINT CHECK (credit_limit >= 0),
+  ADD COLUMN closing_day SMALLINT CHECK (closing_day BETWEEN 1 AND 31),
+  ADD COLUMN due_day SMALLINT CHECK (due_day BETWEEN 1 AND 31),
+  ADD CONSTRAINT accounts_credit_card_fields_check CHECK (
+    type = 'credit_card' OR (credit_limit IS NULL AND closing_day IS NULL AND due_day IS NULL)
+  );
+
+COMMENT ON COLUMN accounts.credit_limit IS 'minor units (cents)';
+
+-- A statement payment is the transfer_in leg of a transfer into the card,
+-- tied to the closing date of the statement it pays. Deleting the transfer
+-- removes the payment.
+CREATE TABLE IF NOT EXISTS card_statement_payments (
+  transaction_id UUID PRIMARY KEY REFERENCES transactions(id) ON DELETE CASCADE,
+  statement_date DATE NOT NULL
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS card_statement_payments;
+
+ALTER TABLE accounts
+  DROP CONSTRAINT IF EXISTS accounts_credit_card_fields_check,
+  DROP COLUMN IF EXISTS credit_limit,
+  DROP COLUMN IF EXISTS closing_day,
+  DROP COLUMN IF EXISTS due_day;
@@ -0,0 +1,24 @@
+-- Write your migrate up statements here
+-- An installment purchase is a series of transactions sharing the same
+-- installment_id, one per month. installment_number goes from 1 to
+-- installment_count and is shown as "3/10".
+ALTER TABLE transactions
+  ADD COLUMN installment_id UUID,
+  ADD COLUMN installment_number SMALLINT,
+  ADD COLUMN installment_count SMALLINT,
+  ADD CONSTRAINT transactions_installment_check CHECK (
+    (installment_id IS NULL AND installment_number IS NULL AND installment_count IS NULL)
+    OR (installment_id IS NOT NULL AND installment_number BETWEEN 1 AND installment_count)
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS transactions_installment_idx ON transactions (installment_id, installment_number);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS transactions_installment_idx;
+
+ALTER TABLE transactions
+  DROP CONSTRAINT IF EXISTS transactions_installment_check,
+  DROP COLUMN IF EXISTS installment_id,
+  DROP COLUMN IF EXISTS installment_number,
+  DROP COLUMN IF EXISTS installment_count;
@@ -0,0 +1,31 @@
+-- Write your migrate up statements here
+-- A goal is a target amount to save, optionally by a date and into an
+-- account. Its progress is the sum of the transactions linked to it as
+-- contributions: money coming in (income, transfer_in) adds to it and money
+-- going out (expense, transfer_out) is a withdrawal.
+CREATE TABLE IF NOT EXISTS goals (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name VARCHAR NOT NULL,
+  target_amount BIGINT NOT NULL CHECK (target_amount > 0),
+  target_date DATE,
+  account_id UUID REFERENCES accounts(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN goals.target_amount IS 'minor units (cents)';
+
+-- A transaction contributes to at most one goal.
+CREATE TABLE IF NOT EXISTS goal_contributions (
+  transaction_id UUID PRIMARY KEY REFERENCES transactions(id) ON DELETE CASCADE,
+  goal_id UUID NOT NULL REFERENCES goals(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS goal_contributions_goal_id_idx ON goal_contributions (goal_id);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS goal_contributions;
+DROP TABLE IF EXISTS goals;
@@ -0,0 +1,137 @@
+-- Write your migrate up statements here
+-- Amounts are always stored in minor units of the account currency, which
+-- is fixed when the account is created. Transactions copy the currency of
+-- their account so reports can convert them without joining accounts.
+ALTER TABLE users
+  ADD COLUMN base_currency CHAR(3) NOT NULL DEFAULT 'BRL'
+    CHECK (base_currency ~ '^[A-Z]{3}$');
+
+ALTER TABLE accounts
+  ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'BRL'
+    CHECK (currency ~ '^[A-Z]{3}$');
+
+ALTER TABLE transactions
+  ADD COLUMN currency CHAR(3),
+  ADD COLUMN exchange_rate NUMERIC(20, 10) CHECK (exchange_rate > 0);
+
+UPDATE transactions t
+   SET currency = a.currency
+  FROM accounts a
+ WHERE a.id = t.account_id;
+
+ALTER TABLE transactions ALTER COLUMN currency SET NOT NULL;
+
+CREATE OR REPLACE FUNCTION set_transaction_currency() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  SELECT currency INTO NEW.currency FROM accounts WHERE id = NEW.account_id;
+  RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER transactions_set_currency
+  BEFORE INSERT OR UPDATE OF account_id ON transactions
+  FOR EACH ROW EXECUTE FUNCTION set_transaction_currency();
+
+CREATE OR REPLACE VIEW transaction_lines AS
+SELECT t.id AS transaction_id, t.user_id, t.account_id, t.date, t.type,
+       s.category_id, s.amount, t.currency
+  FROM transactions t
+  JOIN transaction_splits s ON s.transaction_id = t.id
+UNION ALL
+SELECT t.id AS transaction_id, t.user_id, t.account_id, t.date, t.type,
+       t.category_id, t.amount, t.currency
+  FROM transactions t
+ WHERE NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id);
+
+-- A rate converts one unit of from_currency into to_currency and holds from
+-- its date until the next rate of the same pair.
+CREATE TABLE IF NOT EXISTS exchange_rates (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  from_currency CHAR(3) NOT NULL CHECK (from_currency ~ '^[A-Z]{3}$'),
+  to_currency CHAR(3) NOT NULL CHECK (to_currency ~ '^[A-Z]{3}$'),
+  date DATE NOT NULL,
+  rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CHECK (from_currency <> to_currency),
+  UNIQUE (user_id, from_currency, to_currency, date)
+);
+
+-- exchange_rate returns the latest rate on or before p_date, falling back to
+-- the inverse of the opposite pair, or NULL when there is none.
+CREATE OR REPLACE FUNCTION exchange_rate(p_user_id UUID, p_from CHAR(3), p_to CHAR(3), p_date DATE)
+RETURNS NUMERIC LANGUAGE sql STABLE AS $$
+  SELECT CASE WHEN p_from = p_to THEN 1 ELSE (
+    SELECT r.rate
+      FROM (
+        SELECT date, rate
+          FROM exchange_rates
+         WHERE user_id = p_user_id AND from_currency = p_from AND to_currency = p_to AND date <= p_date
+        UNION ALL
+        SELECT date, 1 / rate
+          FROM exchange_rates
+         WHERE user_id = p_user_id AND from_currency = p_to AND to_currency = p_from AND date <= p_date
+      ) r
+     ORDER BY r.date DESC
+     LIMIT 1
+  ) END;
+$$;
+
+-- to_base_currency converts an amount into the base currency of the user.
+-- A missing rate raises no_data_found (P0002) instead of silently dropping
+-- the amount from a report.
+CREATE OR REPLACE FUNCTION to_base_currency(p_user_id UUID, p_amount BIGINT, p_currency CHAR(3), p_date DATE)
+RETURNS BIGINT LANGUAGE plpgsql STABLE AS $$
+DECLARE
+  v_base CHAR(3);
+  v_rate NUMERIC;
+BEGIN
+  SELECT base_currency INTO v_base FROM users WHERE id = p_user_id;
+  IF p_currency = v_base THEN
+    RETURN p_amount;
+  END IF;
+
+  v_rate := exchange_rate(p_user_id, p_currency, v_base, p_date);
+  IF v_rate IS NULL THEN
+    RAISE EXCEPTION 'from % to % on or before %', p_currency, v_base, p_date
+      USING ERRCODE = 'no_data_found';
+  END IF;
+
+  RETURN round(p_amount * v_rate);
+END;
+$$;
+
+---- create above / drop below ----
+
+DROP FUNCTION IF EXISTS to_base_currency(UUID, BIGINT, CHAR(3), DATE);
+
+DROP FUNCTION IF EXISTS exchange_rate(UUID, CHAR(3), CHAR(3), DATE);
+
+DROP TABLE IF EXISTS exchange_rates;
+
+DROP VIEW IF EXISTS transaction_lines;
+
+CREATE VIEW transaction_lines AS
+SELECT t.id AS transaction_id, t.user_id, t.account_id, t.date, t.type,
+       s.category_id, s.amount
+  FROM transactions t
+  JOIN transaction_splits s ON s.transaction_id = t.id
+UNION ALL
+SELECT t.id AS transaction_id, t.user_id, t.account_id, t.date, t.type,
+       t.category_id, t.amount
+  FROM transactions t
+ WHERE NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id);
+
+DROP TRIGGER IF EXISTS transactions_set_currency ON transactions;
+
+DROP FUNCTION IF EXISTS set_transaction_currency();
+
+ALTER TABLE transactions
+  DROP COLUMN IF EXISTS currency,
+  DROP COLUMN IF EXISTS exchange_rate;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE users DROP COLUMN IF EXISTS base_currency;
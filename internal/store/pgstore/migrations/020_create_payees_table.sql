@@ -0,0 +1,42 @@
+-- Write your migrate up statements here
+-- A payee groups the different descriptions a merchant shows up with in
+-- statements. Names are unique per user regardless of case.
+CREATE TABLE IF NOT EXISTS payees (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name VARCHAR NOT NULL,
+  default_category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS payees_user_id_name_idx ON payees (user_id, lower(name));
+
+-- An alias maps descriptions containing pattern, ignoring case, to a payee.
+-- When several aliases match, the longest pattern wins.
+CREATE TABLE IF NOT EXISTS payee_aliases (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  payee_id UUID NOT NULL REFERENCES payees(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  pattern VARCHAR NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS payee_aliases_user_id_pattern_idx ON payee_aliases (user_id, lower(pattern));
+
+CREATE INDEX IF NOT EXISTS payee_aliases_payee_id_idx ON payee_aliases (payee_id);
+
+ALTER TABLE transactions
+  ADD COLUMN payee_id UUID REFERENCES payees(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS transactions_payee_id_idx ON transactions (payee_id);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS transactions_payee_id_idx;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS payee_id;
+
+DROP TABLE IF EXISTS payee_aliases;
+
+DROP TABLE IF EXISTS payees;
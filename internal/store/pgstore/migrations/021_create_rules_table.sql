@@ -0,0 +1,31 @@
+-- Write your migrate up statements here
+-- A rule changes income and expenses as they are created or imported. Every
+-- condition that is set must match: description_contains ignores case,
+-- description_regex uses Go (RE2) syntax and amounts are in cents. Rules run
+-- in ascending priority; for each action the first matching rule that sets
+-- it wins, while tags add up.
+CREATE TABLE IF NOT EXISTS rules (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name VARCHAR NOT NULL,
+  priority INTEGER NOT NULL DEFAULT 0 CHECK (priority >= 0),
+  enabled BOOLEAN NOT NULL DEFAULT true,
+  description_contains VARCHAR,
+  description_regex VARCHAR,
+  min_amount BIGINT CHECK (min_amount > 0),
+  max_amount BIGINT CHECK (max_amount >= min_amount),
+  account_id UUID REFERENCES accounts(id) ON DELETE CASCADE,
+  type transaction_type CHECK (type IN ('income', 'expense')),
+  set_category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+  set_payee_id UUID REFERENCES payees(id) ON DELETE SET NULL,
+  add_tags TEXT[] NOT NULL DEFAULT '{}',
+  set_description VARCHAR,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS rules_user_id_priority_idx ON rules (user_id, priority);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS rules;
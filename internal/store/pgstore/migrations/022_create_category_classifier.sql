@@ -0,0 +1,107 @@
+-- Write your migrate up statements here
+-- Token counts of a naive Bayes classifier that suggests categories from
+-- descriptions. They are kept per user and category by a trigger on
+-- transactions, so every insert, edit and delete retrains the model in the
+-- same database transaction. Only income and expense transactions count.
+CREATE OR REPLACE FUNCTION description_tokens(description TEXT) RETURNS SETOF TEXT
+LANGUAGE sql IMMUTABLE AS $$
+  SELECT DISTINCT token
+    FROM regexp_split_to_table(lower(description), '[^[:alnum:]]+') AS token
+   WHERE length(token) >= 2 AND token !~ '^[0-9]+$'
+$$;
+
+CREATE TABLE IF NOT EXISTS classifier_categories (
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+  documents INT NOT NULL,
+  tokens INT NOT NULL,
+  PRIMARY KEY (user_id, category_id)
+);
+
+CREATE TABLE IF NOT EXISTS classifier_tokens (
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+  token TEXT NOT NULL,
+  count INT NOT NULL,
+  PRIMARY KEY (user_id, category_id, token)
+);
+
+CREATE INDEX IF NOT EXISTS classifier_tokens_user_id_token_idx ON classifier_tokens (user_id, token);
+
+CREATE OR REPLACE FUNCTION train_category_classifier() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+  IF TG_OP = 'UPDATE'
+     AND OLD.description = NEW.description
+     AND OLD.category_id IS NOT DISTINCT FROM NEW.category_id
+     AND OLD.type = NEW.type THEN
+    RETURN NULL;
+  END IF;
+
+  IF TG_OP <> 'INSERT' AND OLD.type IN ('income', 'expense') AND OLD.category_id IS NOT NULL THEN
+    UPDATE classifier_tokens
+       SET count = count - 1
+     WHERE user_id = OLD.user_id AND category_id = OLD.category_id
+       AND token IN (SELECT description_tokens(OLD.description));
+
+    DELETE FROM classifier_tokens
+     WHERE user_id = OLD.user_id AND category_id = OLD.category_id
+       AND token IN (SELECT description_tokens(OLD.description))
+       AND count <= 0;
+
+    UPDATE classifier_categories
+       SET documents = documents - 1,
+           tokens = tokens - (SELECT count(*) FROM description_tokens(OLD.description))
+     WHERE user_id = OLD.user_id AND category_id = OLD.category_id;
+
+    DELETE FROM classifier_categories
+     WHERE user_id = OLD.user_id AND category_id = OLD.category_id AND documents <= 0;
+  END IF;
+
+  IF TG_OP <> 'DELETE' AND NEW.type IN ('income', 'expense') AND NEW.category_id IS NOT NULL THEN
+    INSERT INTO classifier_tokens (user_id, category_id, token, count)
+    SELECT NEW.user_id, NEW.category_id, token, 1
+      FROM description_tokens(NEW.description) AS token
+        ON CONFLICT (user_id, category_id, token)
+        DO UPDATE SET count = classifier_tokens.count + 1;
+
+    INSERT INTO classifier_categories (user_id, category_id, documents, tokens)
+    VALUES (NEW.user_id, NEW.category_id, 1, (SELECT count(*) FROM description_tokens(NEW.description)))
+        ON CONFLICT (user_id, category_id)
+        DO UPDATE SET documents = classifier_categories.documents + 1,
+                      tokens = classifier_categories.tokens + EXCLUDED.tokens;
+  END IF;
+
+  RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER transactions_train_classifier
+  AFTER INSERT OR DELETE OR UPDATE OF description, category_id, type ON transactions
+  FOR EACH ROW EXECUTE FUNCTION train_category_classifier();
+
+INSERT INTO classifier_tokens (user_id, category_id, token, count)
+SELECT t.user_id, t.category_id, token, count(*)
+  FROM transactions t
+ CROSS JOIN LATERAL description_tokens(t.description) AS token
+ WHERE t.type IN ('income', 'expense') AND t.category_id IS NOT NULL
+ GROUP BY t.user_id, t.category_id, token;
+
+INSERT INTO classifier_categories (user_id, category_id, documents, tokens)
+SELECT t.user_id, t.category_id, count(*), sum(n.tokens)
+  FROM transactions t
+ CROSS JOIN LATERAL (SELECT count(*) AS tokens FROM description_tokens(t.description)) n
+ WHERE t.type IN ('income', 'expense') AND t.category_id IS NOT NULL
+ GROUP BY t.user_id, t.category_id;
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS transactions_train_classifier ON transactions;
+
+DROP FUNCTION IF EXISTS train_category_classifier();
+
+DROP TABLE IF EXISTS classifier_tokens;
+
+DROP TABLE IF EXISTS classifier_categories;
+
+DROP FUNCTION IF EXISTS description_tokens(TEXT);
@@ -0,0 +1,43 @@
+-- Write your migrate up statements here
+-- A transaction is cleared once it shows up on the bank statement and
+-- reconciled when a reconciliation including it is finalized. Reconciled
+-- transactions are locked until they are unlocked back to cleared.
+CREATE TYPE clearing_status AS ENUM ('uncleared', 'cleared', 'reconciled');
+
+-- A reconciliation checks the cleared balance of an account on the
+-- statement date against the balance the bank reports. It stays open until
+-- finalized, and an account has at most one open reconciliation.
+CREATE TABLE IF NOT EXISTS reconciliations (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+  statement_date DATE NOT NULL,
+  statement_balance BIGINT NOT NULL,
+  finalized_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN reconciliations.statement_balance IS 'minor units (cents)';
+
+CREATE UNIQUE INDEX IF NOT EXISTS reconciliations_account_id_open_idx ON reconciliations (account_id) WHERE finalized_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS reconciliations_user_id_idx ON reconciliations (user_id, statement_date);
+
+ALTER TABLE transactions
+  ADD COLUMN clearing_status clearing_status NOT NULL DEFAULT 'uncleared',
+  ADD COLUMN reconciliation_id UUID REFERENCES reconciliations(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS transactions_reconciliation_id_idx ON transactions (reconciliation_id);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS transactions_reconciliation_id_idx;
+
+ALTER TABLE transactions
+  DROP COLUMN IF EXISTS reconciliation_id,
+  DROP COLUMN IF EXISTS clearing_status;
+
+DROP TABLE IF EXISTS reconciliations;
+
+DROP TYPE IF EXISTS clearing_status;
@@ -0,0 +1,33 @@
+-- Write your migrate up statements here
+-- The books of a user are closed through closed_through: transactions dated
+-- on or before it can no longer be created, changed or deleted. A NULL date,
+-- like a missing row, means no period is closed.
+CREATE TABLE IF NOT EXISTS book_closings (
+  user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  closed_through DATE,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TYPE book_closing_action AS ENUM ('close', 'reopen');
+
+-- Every change of the closing date is kept, with the date before and after
+-- it. Reopening requires a reason.
+CREATE TABLE IF NOT EXISTS book_closing_events (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  action book_closing_action NOT NULL,
+  previous_date DATE,
+  closed_through DATE,
+  reason TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS book_closing_events_user_id_idx ON book_closing_events (user_id, created_at);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS book_closing_events;
+
+DROP TYPE IF EXISTS book_closing_action;
+
+DROP TABLE IF EXISTS book_closings;
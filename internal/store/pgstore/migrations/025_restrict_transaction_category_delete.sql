@@ -0,0 +1,15 @@
+-- Write your migrate up statements here
+-- Deleting a category deleted its transactions too, without reverting their
+-- effect on the account balances. A category in use can no longer be
+-- deleted.
+ALTER TABLE transactions
+  DROP CONSTRAINT IF EXISTS transactions_category_id_fkey,
+  ADD CONSTRAINT transactions_category_id_fkey
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE RESTRICT;
+
+---- create above / drop below ----
+
+ALTER TABLE transactions
+  DROP CONSTRAINT IF EXISTS transactions_category_id_fkey,
+  ADD CONSTRAINT transactions_category_id_fkey
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE;
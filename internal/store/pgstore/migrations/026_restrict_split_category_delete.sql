@@ -0,0 +1,15 @@
+-- Write your migrate up statements here
+-- Deleting a category removed only the split lines that used it, so the
+-- remaining splits of the transaction no longer added up to its amount. A
+-- category used by a split can no longer be deleted.
+ALTER TABLE transaction_splits
+  DROP CONSTRAINT IF EXISTS transaction_splits_category_id_fkey,
+  ADD CONSTRAINT transaction_splits_category_id_fkey
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE RESTRICT;
+
+---- create above / drop below ----
+
+ALTER TABLE transaction_splits
+  DROP CONSTRAINT IF EXISTS transaction_splits_category_id_fkey,
+  ADD CONSTRAINT transaction_splits_category_id_fkey
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE;
@@ -3,11 +3,18 @@ INSERT INTO accounts (
   user_id,
   name,
   type,
-  balance
-) VALUES ($1, $2, $3, $4);
+  balance,
+  credit_limit,
+  closing_day,
+  due_day,
+  currency
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7,
+  coalesce(sqlc.narg(currency)::varchar, (SELECT base_currency FROM users WHERE id = $1))
+);
 
 -- name: GetAccount :one
-SELECT * FROM accounts WHERE id = $1;
+SELECT * FROM accounts WHERE id = $1 AND user_id = $2;
 
 -- name: GetAccountsByUserId :many
 SELECT * FROM accounts WHERE user_id = $1;
@@ -16,8 +23,32 @@ SELECT * FROM accounts WHERE user_id = $1;
 UPDATE accounts
 SET name = $2,
     type = $3,
+    credit_limit = $4,
+    closing_day = $5,
+    due_day = $6,
     updated_at = now()
-WHERE id = $1;
+WHERE id = $1 AND user_id = $7;
 
 -- name: DeleteAccount :exec
-DELETE FROM accounts WHERE id = $1;
\ No newline at end of file
+DELETE FROM accounts WHERE id = $1 AND user_id = $2;
+
+-- name: GetAccountLedgerSummary :one
+-- Deleting an account deletes its transactions. first_date is NULL while the
+-- account has none.
+select min(t.date)::date as first_date,
+       coalesce(bool_or(t.clearing_status = 'reconciled'), false)::bool as reconciled,
+       coalesce(bool_or(t.transfer_id is not null), false)::bool as transfers
+  from transactions t
+ where t.account_id = $1;
+
+-- name: AdjustAccountBalance :execrows
+-- Only liabilities may end up with a negative balance. Money coming in is
+-- always accepted, so an overdrawn account can still be settled.
+UPDATE accounts
+SET balance = balance + sqlc.arg(delta)::bigint,
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+  AND (sqlc.arg(delta) >= 0 OR liability OR balance + sqlc.arg(delta) >= 0);
+
+-- name: AccountExists :one
+select exists(select 1 from accounts where id = $1);
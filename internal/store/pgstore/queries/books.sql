@@ -0,0 +1,47 @@
+-- name: GetBooksClosedThrough :one
+-- NULL while the user has no closed period.
+select b.closed_through
+  from users u
+  left join book_closings b on b.user_id = u.id
+ where u.id = sqlc.arg(user_id);
+
+-- name: GetBooksClosedThroughForShare :one
+-- Holds a share lock on the user until the transaction ends, so the books
+-- cannot be closed while a change to a transaction is being saved.
+select b.closed_through
+  from users u
+  left join book_closings b on b.user_id = u.id
+ where u.id = sqlc.arg(user_id)
+   for share of u;
+
+-- name: GetBooksClosedThroughForUpdate :one
+select b.closed_through
+  from users u
+  left join book_closings b on b.user_id = u.id
+ where u.id = sqlc.arg(user_id)
+   for update of u;
+
+-- name: SetBooksClosedThrough :exec
+insert into book_closings (user_id, closed_through)
+values ($1, $2)
+on conflict (user_id) do update
+   set closed_through = excluded.closed_through,
+       updated_at = now();
+
+-- name: CreateBookClosingEvent :one
+insert into book_closing_events (
+  user_id,
+  action,
+  previous_date,
+  closed_through,
+  reason
+)
+values ($1, $2, $3, $4, $5)
+returning *;
+
+-- name: GetBookClosingEvents :many
+-- The latest events come first.
+select *
+  from book_closing_events
+ where user_id = $1
+ order by created_at desc, id;
@@ -0,0 +1,62 @@
+-- name: CreateBudget :exec
+insert into budgets (
+  user_id,
+  category_id,
+  period,
+  amount,
+  rollover
+)
+values ($1, $2, $3, $4, $5);
+
+-- name: GetBudget :one
+select *
+  from budgets
+ where id = $1
+   and user_id = $2;
+
+-- name: GetBudgetsProgress :many
+-- spent only counts expenses of the budget category inside its month,
+-- including the split lines of that category, in the base currency.
+select b.id, b.user_id, b.category_id, b.period, b.amount, b.rollover, b.created_at, b.updated_at,
+       coalesce(sum(to_base_currency(t.user_id, t.amount, t.currency, t.date)), 0)::bigint as spent
+  from budgets b
+  left join transaction_lines t
+    on t.category_id = b.category_id
+   and t.user_id = b.user_id
+   and t.type = 'expense'
+   and t.date >= b.period
+   and t.date < (b.period + interval '1 month')::date
+ where b.user_id = $1
+   and b.period = $2
+ group by b.id
+ order by b.created_at;
+
+-- name: GetBudgetsHistory :many
+-- The budgets of the months before period, oldest first, with spent counted
+-- as in GetBudgetsProgress. Rollover carryovers are chained over them.
+select b.category_id, b.period, b.amount, b.rollover,
+       coalesce(sum(to_base_currency(t.user_id, t.amount, t.currency, t.date)), 0)::bigint as spent
+  from budgets b
+  left join transaction_lines t
+    on t.category_id = b.category_id
+   and t.user_id = b.user_id
+   and t.type = 'expense'
+   and t.date >= b.period
+   and t.date < (b.period + interval '1 month')::date
+ where b.user_id = $1
+   and b.period < $2
+ group by b.id
+ order by b.period;
+
+-- name: UpdateBudget :exec
+update budgets
+   set amount = $2,
+       rollover = $3,
+       updated_at = now()
+ where id = $1
+   and user_id = $4;
+
+-- name: DeleteBudget :exec
+delete from budgets
+ where id = $1
+   and user_id = $2;
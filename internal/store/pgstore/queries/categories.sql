@@ -9,7 +9,8 @@ values ($1, $2, $3);
 -- name: GetCategory :one
 select *
   from categories
- where id = $1;
+ where id = $1
+   and user_id = $2;
 
 -- name: GetAllCategoriesByUserId :many
 select *
@@ -21,8 +22,10 @@ update categories
    set name = $2,
        type = $3,
        updated_at = now()
- where id = $1;
+ where id = $1
+   and user_id = $4;
 
 -- name: DeleteCategory :exec
 delete from categories
- where id = $1;
\ No newline at end of file
+ where id = $1
+   and user_id = $2;
\ No newline at end of file
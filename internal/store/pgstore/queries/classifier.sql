@@ -0,0 +1,21 @@
+-- name: GetClassifierCategories :many
+-- Categories the user's model was trained on, optionally of a single type.
+select c.category_id, cat.name, cat.type, c.documents, c.tokens
+  from classifier_categories c
+  join categories cat on cat.id = c.category_id
+ where c.user_id = sqlc.arg(user_id)
+   and (sqlc.narg(type)::transaction_type is null or cat.type = sqlc.narg(type))
+ order by c.category_id;
+
+-- name: GetClassifierTokens :many
+-- Counts of the tokens of description seen in the user's transactions.
+-- Tokens the model never saw are left out.
+select category_id, token, count
+  from classifier_tokens
+ where user_id = sqlc.arg(user_id)
+   and token in (select description_tokens(sqlc.arg(description)));
+
+-- name: CountClassifierVocabulary :one
+select count(distinct token)
+  from classifier_tokens
+ where user_id = $1;
@@ -0,0 +1,35 @@
+-- name: UpsertExchangeRate :one
+-- A pair has a single rate per day, so sending it again replaces the rate.
+insert into exchange_rates (
+  user_id,
+  from_currency,
+  to_currency,
+  date,
+  rate
+)
+values ($1, $2, $3, $4, $5)
+on conflict (user_id, from_currency, to_currency, date)
+do update set rate = excluded.rate,
+              updated_at = now()
+returning *;
+
+-- name: ListExchangeRates :many
+select *
+  from exchange_rates
+ where user_id = sqlc.arg(user_id)
+   and (sqlc.narg(from_currency)::text is null or from_currency = sqlc.narg(from_currency))
+   and (sqlc.narg(to_currency)::text is null or to_currency = sqlc.narg(to_currency))
+   and (sqlc.narg(start_date)::date is null or date >= sqlc.narg(start_date))
+   and (sqlc.narg(end_date)::date is null or date <= sqlc.narg(end_date))
+ order by date desc, from_currency, to_currency;
+
+-- name: DeleteExchangeRate :execrows
+delete from exchange_rates
+ where id = $1
+   and user_id = $2;
+
+-- name: ConvertAmount :one
+-- Returns no row when there is no rate for the pair on or before the date.
+select round(sqlc.arg(amount)::bigint * r.rate)::bigint as amount
+  from exchange_rate(sqlc.arg(user_id), sqlc.arg(from_currency), sqlc.arg(to_currency), sqlc.arg(date)) as r(rate)
+ where r.rate is not null;
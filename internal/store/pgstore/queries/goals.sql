@@ -0,0 +1,74 @@
+-- name: CreateGoal :exec
+insert into goals (
+  user_id,
+  name,
+  target_amount,
+  target_date,
+  account_id
+)
+values ($1, $2, $3, $4, $5);
+
+-- name: GetGoal :one
+select *
+  from goals
+ where id = $1
+   and user_id = $2;
+
+-- name: GetGoalsProgress :many
+-- saved adds inflows and subtracts outflows of the linked transactions, in
+-- the base currency. first_contribution is NULL while the goal has none.
+-- goal_id is optional.
+select g.id, g.user_id, g.name, g.target_amount, g.target_date, g.account_id, g.created_at, g.updated_at,
+       coalesce(sum(to_base_currency(t.user_id, case when t.type in ('income', 'transfer_in') then t.amount else -t.amount end, t.currency, t.date)), 0)::bigint as saved,
+       count(t.id)::bigint as contributions,
+       min(t.date)::date as first_contribution
+  from goals g
+  left join goal_contributions c on c.goal_id = g.id
+  left join transactions t on t.id = c.transaction_id
+ where g.user_id = sqlc.arg(user_id)
+   and (sqlc.narg(goal_id)::uuid is null or g.id = sqlc.narg(goal_id))
+ group by g.id
+ order by g.created_at;
+
+-- name: UpdateGoal :exec
+update goals
+   set name = $2,
+       target_amount = $3,
+       target_date = $4,
+       account_id = $5,
+       updated_at = now()
+ where id = $1
+   and user_id = $6;
+
+-- name: DeleteGoal :exec
+delete from goals
+ where id = $1
+   and user_id = $2;
+
+-- name: CreateGoalContribution :execrows
+insert into goal_contributions (goal_id, transaction_id)
+select sqlc.arg(goal_id), id
+  from transactions
+ where id = sqlc.arg(transaction_id)
+   and user_id = sqlc.arg(user_id);
+
+-- name: CreateGoalTransferContribution :execrows
+-- Links the transfer_in leg of a transfer to the goal.
+insert into goal_contributions (goal_id, transaction_id)
+select sqlc.arg(goal_id), id
+  from transactions
+ where transfer_id = sqlc.arg(transfer_id)
+   and type = 'transfer_in'
+   and user_id = sqlc.arg(user_id);
+
+-- name: GetGoalContributions :many
+select t.id, t.date, t.description, t.amount, t.type, t.account_id, t.transfer_id
+  from goal_contributions c
+  join transactions t on t.id = c.transaction_id
+ where c.goal_id = $1
+ order by t.date, t.created_at;
+
+-- name: DeleteGoalContribution :execrows
+delete from goal_contributions
+ where goal_id = $1
+   and transaction_id = $2;
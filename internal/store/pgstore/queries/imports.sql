@@ -0,0 +1,64 @@
+-- name: CreateImportProfile :exec
+insert into import_profiles (
+  user_id,
+  name,
+  delimiter,
+  has_header,
+  date_column,
+  date_format,
+  description_column,
+  amount_column,
+  decimal_separator,
+  sign_convention
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+
+-- name: GetImportProfile :one
+select *
+  from import_profiles
+ where id = $1
+   and user_id = $2;
+
+-- name: GetImportProfiles :many
+select *
+  from import_profiles
+ where user_id = $1
+ order by name;
+
+-- name: UpdateImportProfile :exec
+update import_profiles
+   set name = $2,
+       delimiter = $3,
+       has_header = $4,
+       date_column = $5,
+       date_format = $6,
+       description_column = $7,
+       amount_column = $8,
+       decimal_separator = $9,
+       sign_convention = $10,
+       updated_at = now()
+ where id = $1
+   and user_id = $11;
+
+-- name: DeleteImportProfile :exec
+delete from import_profiles
+ where id = $1
+   and user_id = $2;
+
+-- name: GetImportedHashes :many
+select import_hash::text
+  from transactions
+ where account_id = sqlc.arg(account_id)
+   and import_hash = any(sqlc.arg(hashes)::text[]);
+
+-- name: GetAccountBalanceAsOf :one
+-- The balance of the account at the end of as_of: the current balance with
+-- every transaction dated after it undone.
+select (a.balance - coalesce((
+         select sum(case when t.type in ('expense', 'transfer_out') then -t.amount else t.amount end)
+           from transactions t
+          where t.account_id = a.id
+            and t.date > sqlc.arg(as_of)::date), 0))::bigint as balance
+  from accounts a
+ where a.id = sqlc.arg(account_id)
+   and a.user_id = sqlc.arg(user_id);
@@ -0,0 +1,120 @@
+-- name: CreatePayee :one
+insert into payees (
+  user_id,
+  name,
+  default_category_id
+)
+values ($1, $2, $3)
+returning *;
+
+-- name: GetPayee :one
+select *
+  from payees
+ where id = $1
+   and user_id = $2;
+
+-- name: GetPayees :many
+select *
+  from payees
+ where user_id = $1
+ order by name;
+
+-- name: UpdatePayee :exec
+update payees
+   set name = $2,
+       default_category_id = $3,
+       updated_at = now()
+ where id = $1
+   and user_id = $4;
+
+-- name: DeletePayee :exec
+delete from payees
+ where id = $1
+   and user_id = $2;
+
+-- name: CreatePayeeAlias :one
+insert into payee_aliases (
+  payee_id,
+  user_id,
+  pattern
+)
+values ($1, $2, $3)
+returning *;
+
+-- name: GetPayeeAliases :many
+select *
+  from payee_aliases
+ where payee_id = $1
+ order by pattern;
+
+-- name: DeletePayeeAlias :execrows
+delete from payee_aliases
+ where id = $1
+   and payee_id = $2
+   and user_id = $3;
+
+-- name: MatchPayee :one
+-- Picks the payee of the longest alias contained in the description; ties go
+-- to the alias created first.
+select p.*
+  from payee_aliases a
+  join payees p on p.id = a.payee_id
+ where a.user_id = sqlc.arg(user_id)
+   and strpos(lower(sqlc.arg(description)::text), lower(a.pattern)) > 0
+ order by length(a.pattern) desc, a.created_at
+ limit 1;
+
+-- name: SuggestPayeeCategory :one
+-- The category used most often by income and expenses whose description
+-- contains the name, the most recent one breaking ties.
+select category_id
+  from transactions
+ where user_id = sqlc.arg(user_id)
+   and type in ('income', 'expense')
+   and strpos(lower(description), lower(sqlc.arg(name)::text)) > 0
+ group by category_id
+ order by count(*) desc, max(date) desc
+ limit 1;
+
+-- name: ReassignPayeeTransactions :execrows
+update transactions
+   set payee_id = sqlc.arg(target_id)::uuid,
+       updated_at = now()
+ where user_id = sqlc.arg(user_id)
+   and payee_id = any(sqlc.arg(source_ids)::uuid[]);
+
+-- name: ReassignPayeeAliases :exec
+update payee_aliases
+   set payee_id = sqlc.arg(target_id)
+ where user_id = sqlc.arg(user_id)
+   and payee_id = any(sqlc.arg(source_ids)::uuid[]);
+
+-- name: AddPayeeNamesAsAliases :exec
+-- Keeps matching the descriptions the merged payees were named after. Names
+-- that already are an alias are skipped.
+insert into payee_aliases (payee_id, user_id, pattern)
+select sqlc.arg(target_id), p.user_id, p.name
+  from payees p
+ where p.user_id = sqlc.arg(user_id)
+   and p.id = any(sqlc.arg(source_ids)::uuid[])
+    on conflict do nothing;
+
+-- name: DeletePayees :exec
+delete from payees
+ where user_id = sqlc.arg(user_id)
+   and id = any(sqlc.arg(ids)::uuid[]);
+
+-- name: GetPayeeTotals :many
+-- Amounts are converted into the user's base currency like the reports.
+select p.id, p.name,
+       count(t.id)::bigint as transactions,
+       coalesce(sum(to_base_currency(t.user_id, t.amount, t.currency, t.date)) filter (where t.type = 'income'), 0)::bigint as income,
+       coalesce(sum(to_base_currency(t.user_id, t.amount, t.currency, t.date)) filter (where t.type = 'expense'), 0)::bigint as expense
+  from payees p
+  left join transactions t
+    on t.payee_id = p.id
+   and (sqlc.narg(start_date)::date is null or t.date >= sqlc.narg(start_date))
+   and (sqlc.narg(end_date)::date is null or t.date <= sqlc.narg(end_date))
+ where p.user_id = sqlc.arg(user_id)
+ group by p.id
+ order by p.name;
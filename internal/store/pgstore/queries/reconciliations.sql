@@ -0,0 +1,92 @@
+-- name: CreateReconciliation :one
+insert into reconciliations (
+  user_id,
+  account_id,
+  statement_date,
+  statement_balance
+)
+values ($1, $2, $3, $4)
+returning *;
+
+-- name: GetReconciliation :one
+select *
+  from reconciliations
+ where id = $1
+   and user_id = $2;
+
+-- name: GetReconciliationForUpdate :one
+select *
+  from reconciliations
+ where id = $1
+   and user_id = $2
+   for update;
+
+-- name: GetReconciliations :many
+-- account_id is optional. The latest statements come first.
+select *
+  from reconciliations
+ where user_id = sqlc.arg(user_id)
+   and (sqlc.narg(account_id)::uuid is null or account_id = sqlc.narg(account_id))
+ order by statement_date desc, created_at desc;
+
+-- name: GetLastReconciledDate :one
+-- NULL while the account has no finalized reconciliation.
+select max(statement_date)::date as statement_date
+  from reconciliations
+ where account_id = $1
+   and finalized_at is not null;
+
+-- name: GetClearedBalance :one
+-- The balance of the account on the statement date counting only cleared
+-- and reconciled transactions: the current balance with every uncleared
+-- transaction and every transaction dated after the statement undone.
+select (a.balance - coalesce((
+         select sum(case when t.type in ('expense', 'transfer_out') then -t.amount else t.amount end)
+           from transactions t
+          where t.account_id = a.id
+            and (t.clearing_status = 'uncleared' or t.date > sqlc.arg(statement_date)::date)), 0))::bigint as cleared_balance
+  from accounts a
+ where a.id = sqlc.arg(account_id);
+
+-- name: GetReconciliationTransactions :many
+-- While the reconciliation is open these are the transactions of the account
+-- up to the statement date that are not reconciled yet; once finalized,
+-- the ones it reconciled.
+select t.*
+  from transactions t
+  join reconciliations r on r.account_id = t.account_id
+ where r.id = sqlc.arg(id)
+   and case when r.finalized_at is null
+            then t.date <= r.statement_date and t.clearing_status <> 'reconciled'
+            else t.reconciliation_id = r.id
+       end
+ order by t.date, t.id;
+
+-- name: SetAccountClearingStatus :execrows
+-- Reconciled transactions and those of other accounts are left untouched.
+update transactions
+   set clearing_status = sqlc.arg(clearing_status),
+       updated_at = now()
+ where account_id = sqlc.arg(account_id)
+   and id = any(sqlc.arg(ids)::uuid[])
+   and clearing_status <> 'reconciled';
+
+-- name: ReconcileTransactions :execrows
+update transactions
+   set clearing_status = 'reconciled',
+       reconciliation_id = sqlc.arg(reconciliation_id),
+       updated_at = now()
+ where account_id = sqlc.arg(account_id)
+   and clearing_status = 'cleared'
+   and date <= sqlc.arg(statement_date);
+
+-- name: FinalizeReconciliation :exec
+update reconciliations
+   set finalized_at = now(),
+       updated_at = now()
+ where id = $1;
+
+-- name: DeleteReconciliation :exec
+delete from reconciliations
+ where id = $1
+   and user_id = $2;
@@ -0,0 +1,117 @@
+-- name: CreateRecurringTransaction :exec
+insert into recurring_transactions (
+  user_id,
+  account_id,
+  category_id,
+  description,
+  amount,
+  type,
+  frequency,
+  interval_count,
+  day_of_month,
+  start_date,
+  end_date,
+  max_occurrences,
+  next_occurrence
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);
+
+-- name: GetRecurringTransaction :one
+select *
+  from recurring_transactions
+ where id = $1
+   and user_id = $2;
+
+-- name: GetRecurringTransactionForUpdate :one
+select *
+  from recurring_transactions
+ where id = $1
+   and user_id = $2
+   for update;
+
+-- name: GetAllRecurringTransactions :many
+select *
+  from recurring_transactions
+ where user_id = $1
+ order by created_at;
+
+-- name: GetDueRecurringTransactions :many
+-- Pages by (next_occurrence, id) after the last series of the previous page,
+-- so series that keep failing do not hide the ones behind them. after_date
+-- is NULL on the first page.
+select id, next_occurrence
+  from recurring_transactions
+ where next_occurrence <= sqlc.arg(until)::date
+   and (sqlc.narg(after_date)::date is null
+        or (next_occurrence, id) > (sqlc.narg(after_date)::date, sqlc.narg(after_id)::uuid))
+ order by next_occurrence, id
+ limit sqlc.arg(row_limit);
+
+-- name: LockDueRecurringTransaction :one
+-- Rows locked by another scheduler instance are skipped instead of waited on.
+select *
+  from recurring_transactions
+ where id = $1
+   and next_occurrence <= $2
+   for update skip locked;
+
+-- name: UpdateRecurringTransaction :exec
+update recurring_transactions
+   set description = $2,
+       amount = $3,
+       account_id = $4,
+       category_id = $5,
+       end_date = $6,
+       max_occurrences = $7,
+       next_occurrence = $8,
+       updated_at = now()
+ where id = $1
+   and user_id = $9;
+
+-- name: AdvanceRecurringTransaction :exec
+update recurring_transactions
+   set occurrences_count = $2,
+       next_occurrence = $3,
+       updated_at = now()
+ where id = $1;
+
+-- name: DeleteRecurringTransaction :exec
+delete from recurring_transactions
+ where id = $1
+   and user_id = $2;
+
+-- name: UpsertRecurringException :exec
+insert into recurring_exceptions (
+  recurring_id,
+  occurrence_date,
+  action,
+  description,
+  amount
+)
+values ($1, $2, $3, $4, $5)
+on conflict (recurring_id, occurrence_date) do update
+   set action = excluded.action,
+       description = excluded.description,
+       amount = excluded.amount;
+
+-- name: GetRecurringExceptions :many
+select *
+  from recurring_exceptions
+ where recurring_id = $1
+   and occurrence_date >= $2
+ order by occurrence_date;
+
+-- name: CreateRecurringOccurrence :execrows
+insert into transactions (
+  description,
+  amount,
+  date,
+  type,
+  user_id,
+  account_id,
+  category_id,
+  recurring_id,
+  occurrence_date
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+on conflict (recurring_id, occurrence_date) do nothing;
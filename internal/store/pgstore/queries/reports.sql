@@ -0,0 +1,97 @@
+-- Reports read from transaction_lines so split transactions count towards
+-- each of their categories. Every filter is optional: a NULL argument
+-- disables it, like in ListTransactions. Amounts are converted into the
+-- user's base currency at the rate of their date by to_base_currency, which
+-- fails when a rate is missing.
+
+-- name: GetMonthlyIncomeExpense :many
+-- Transfers only move money between the user's accounts and are left out.
+select date_trunc('month', l.date)::date as month,
+       coalesce(sum(to_base_currency(l.user_id, l.amount, l.currency, l.date)) filter (where l.type = 'income'), 0)::bigint as income,
+       coalesce(sum(to_base_currency(l.user_id, l.amount, l.currency, l.date)) filter (where l.type = 'expense'), 0)::bigint as expense
+  from transaction_lines l
+ where l.user_id = sqlc.arg(user_id)
+   and l.type in ('income', 'expense')
+   and (sqlc.narg(account_id)::uuid is null or l.account_id = sqlc.narg(account_id))
+   and (sqlc.narg(category_id)::uuid is null or l.category_id = sqlc.narg(category_id))
+   and (sqlc.narg(start_date)::date is null or l.date >= sqlc.narg(start_date))
+   and (sqlc.narg(end_date)::date is null or l.date <= sqlc.narg(end_date))
+ group by month
+ order by month;
+
+-- name: GetSpendingByCategory :many
+select c.id, c.name,
+       count(distinct l.transaction_id)::bigint as transactions,
+       sum(to_base_currency(l.user_id, l.amount, l.currency, l.date))::bigint as total
+  from transaction_lines l
+  join categories c on c.id = l.category_id
+ where l.user_id = sqlc.arg(user_id)
+   and l.type = 'expense'
+   and (sqlc.narg(account_id)::uuid is null or l.account_id = sqlc.narg(account_id))
+   and (sqlc.narg(category_id)::uuid is null or l.category_id = sqlc.narg(category_id))
+   and (sqlc.narg(start_date)::date is null or l.date >= sqlc.narg(start_date))
+   and (sqlc.narg(end_date)::date is null or l.date <= sqlc.narg(end_date))
+ group by c.id
+ order by total desc, c.name;
+
+-- name: GetTopPayees :many
+-- Transactions linked to a payee are grouped under it. The others are
+-- grouped by their description, compared without case and surrounding
+-- spaces.
+select coalesce(p.name, min(btrim(t.description)))::text as payee,
+       p.id as payee_id,
+       count(distinct l.transaction_id)::bigint as transactions,
+       sum(to_base_currency(l.user_id, l.amount, l.currency, l.date))::bigint as total
+  from transaction_lines l
+  join transactions t on t.id = l.transaction_id
+  left join payees p on p.id = t.payee_id
+ where l.user_id = sqlc.arg(user_id)
+   and l.type = 'expense'
+   and (sqlc.narg(account_id)::uuid is null or l.account_id = sqlc.narg(account_id))
+   and (sqlc.narg(category_id)::uuid is null or l.category_id = sqlc.narg(category_id))
+   and (sqlc.narg(start_date)::date is null or l.date >= sqlc.narg(start_date))
+   and (sqlc.narg(end_date)::date is null or l.date <= sqlc.narg(end_date))
+ group by p.id, case when p.id is null then lower(btrim(t.description)) end
+ order by total desc, payee
+ limit sqlc.arg(row_limit)::int;
+
+-- name: GetDailyCashFlow :many
+-- Unlike the other reports transfers are included, since they move money in
+-- and out of the account, and amounts stay in the account currency.
+select l.date,
+       coalesce(sum(l.amount) filter (where l.type in ('income', 'transfer_in')), 0)::bigint as inflow,
+       coalesce(sum(l.amount) filter (where l.type in ('expense', 'transfer_out')), 0)::bigint as outflow
+  from transaction_lines l
+ where l.user_id = sqlc.arg(user_id)
+   and l.account_id = sqlc.arg(account_id)
+   and (sqlc.narg(category_id)::uuid is null or l.category_id = sqlc.narg(category_id))
+   and (sqlc.narg(start_date)::date is null or l.date >= sqlc.narg(start_date))
+   and (sqlc.narg(end_date)::date is null or l.date <= sqlc.narg(end_date))
+ group by l.date
+ order by l.date;
+
+-- name: GetNetWorthHistory :many
+-- Rebuilds the balance of every account at the end of each day by undoing
+-- the transactions dated after it. An account counts from its creation or
+-- its first transaction, whichever comes first. Balances are converted at
+-- the rate of each day. Liabilities are returned as the amount owed.
+select d.day::date as date,
+       coalesce(sum(b.balance) filter (where not b.liability), 0)::bigint as assets,
+       coalesce(-sum(b.balance) filter (where b.liability), 0)::bigint as liabilities
+  from unnest(sqlc.arg(days)::date[]) as d(day)
+  left join lateral (
+         select a.liability,
+                to_base_currency(a.user_id, (a.balance - coalesce((
+                  select sum(case when t.type in ('expense', 'transfer_out') then -t.amount else t.amount end)
+                    from transactions t
+                   where t.account_id = a.id
+                     and t.date > d.day), 0))::bigint, a.currency, d.day) as balance
+           from accounts a
+          where a.user_id = sqlc.arg(user_id)
+            and d.day >= least(a.created_at::date, coalesce((
+                  select min(t.date)
+                    from transactions t
+                   where t.account_id = a.id), a.created_at::date))
+       ) b on true
+ group by d.day
+ order by d.day;
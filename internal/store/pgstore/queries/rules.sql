@@ -0,0 +1,62 @@
+-- name: CreateRule :one
+insert into rules (
+  user_id,
+  name,
+  priority,
+  enabled,
+  description_contains,
+  description_regex,
+  min_amount,
+  max_amount,
+  account_id,
+  type,
+  set_category_id,
+  set_payee_id,
+  add_tags,
+  set_description
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+returning *;
+
+-- name: GetRule :one
+select *
+  from rules
+ where id = $1
+   and user_id = $2;
+
+-- name: GetRules :many
+select *
+  from rules
+ where user_id = $1
+ order by priority, created_at;
+
+-- name: GetEnabledRules :many
+select *
+  from rules
+ where user_id = $1
+   and enabled
+ order by priority, created_at;
+
+-- name: UpdateRule :execrows
+update rules
+   set name = $2,
+       priority = $3,
+       enabled = $4,
+       description_contains = $5,
+       description_regex = $6,
+       min_amount = $7,
+       max_amount = $8,
+       account_id = $9,
+       type = $10,
+       set_category_id = $11,
+       set_payee_id = $12,
+       add_tags = $13,
+       set_description = $14,
+       updated_at = now()
+ where id = $1
+   and user_id = $15;
+
+-- name: DeleteRule :execrows
+delete from rules
+ where id = $1
+   and user_id = $2;
@@ -0,0 +1,57 @@
+-- name: CreateSession :one
+insert into sessions (user_id, impersonator_id)
+values ($1, $2)
+returning id;
+
+-- name: IsSessionRevoked :one
+select (revoked_at is not null)::bool as revoked
+  from sessions
+ where id = $1;
+
+-- name: RevokeSession :exec
+update sessions
+   set revoked_at = now()
+ where id = $1
+   and revoked_at is null;
+
+-- name: RevokeUserSessions :exec
+update sessions
+   set revoked_at = now()
+ where user_id = $1
+   and revoked_at is null;
+
+-- name: RevokeImpersonatorSessions :exec
+-- The sessions an admin opened on behalf of other users.
+update sessions
+   set revoked_at = now()
+ where impersonator_id = $1
+   and revoked_at is null;
+
+-- name: RevokeSessionByRefreshToken :execrows
+-- Logging out with an already revoked session still counts as a match.
+update sessions
+   set revoked_at = coalesce(revoked_at, now())
+ where id = (select session_id from refresh_tokens where token_hash = $1);
+
+-- name: CreateRefreshToken :exec
+insert into refresh_tokens (
+  session_id,
+  token_hash,
+  expires_at
+)
+values ($1, $2, $3);
+
+-- name: GetRefreshTokenForUpdate :one
+-- Locks the token so concurrent refreshes with it are serialized and all but
+-- the first one see it as used.
+select rt.id, rt.session_id, rt.expires_at, rt.used_at, s.user_id, s.revoked_at, u.name, u.role
+  from refresh_tokens rt
+  join sessions s on s.id = rt.session_id
+  join users u on u.id = s.user_id
+ where rt.token_hash = $1
+   for update of rt;
+
+-- name: MarkRefreshTokenUsed :exec
+update refresh_tokens
+   set used_at = now()
+ where id = $1;
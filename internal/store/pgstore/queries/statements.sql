@@ -0,0 +1,24 @@
+-- name: CreateStatementPayment :exec
+-- Ties the transfer_in leg of a transfer to the statement it pays.
+insert into card_statement_payments (transaction_id, statement_date)
+select id, sqlc.arg(statement_date)
+  from transactions
+ where transfer_id = sqlc.arg(transfer_id)
+   and type = 'transfer_in';
+
+-- name: GetCardTransactions :many
+-- Transfers into the card are payments, not charges, and are left out.
+select id, date, description, amount, type
+  from transactions
+ where account_id = sqlc.arg(account_id)
+   and type <> 'transfer_in'
+   and date between sqlc.arg(start_date) and sqlc.arg(end_date)
+ order by date, created_at;
+
+-- name: GetStatementPayments :many
+select p.statement_date, sum(t.amount)::bigint as paid
+  from card_statement_payments p
+  join transactions t on t.id = p.transaction_id
+ where t.account_id = sqlc.arg(account_id)
+   and p.statement_date between sqlc.arg(start_date) and sqlc.arg(end_date)
+ group by p.statement_date;
@@ -0,0 +1,72 @@
+-- name: CreateTag :exec
+insert into tags (
+  user_id,
+  name
+)
+values ($1, $2);
+
+-- name: GetTag :one
+select *
+  from tags
+ where id = $1
+   and user_id = $2;
+
+-- name: GetTags :many
+select *
+  from tags
+ where user_id = $1
+ order by name;
+
+-- name: UpdateTag :exec
+update tags
+   set name = $2,
+       updated_at = now()
+ where id = $1
+   and user_id = $3;
+
+-- name: DeleteTag :exec
+delete from tags
+ where id = $1
+   and user_id = $2;
+
+-- name: UpsertTags :many
+-- Creates the names the user does not have yet and returns the id of every
+-- name.
+insert into tags (user_id, name)
+select sqlc.arg(user_id), unnest(sqlc.arg(names)::text[])
+    on conflict (user_id, name) do update
+   set name = excluded.name
+returning id;
+
+-- name: DeleteTransactionTags :exec
+delete from transaction_tags
+ where transaction_id = $1;
+
+-- name: AddTransactionTags :exec
+insert into transaction_tags (transaction_id, tag_id)
+select sqlc.arg(transaction_id), unnest(sqlc.arg(tag_ids)::uuid[])
+    on conflict do nothing;
+
+-- name: GetTransactionsTags :many
+select tt.transaction_id, t.name
+  from transaction_tags tt
+  join tags t on t.id = tt.tag_id
+ where tt.transaction_id = any(sqlc.arg(transaction_ids)::uuid[])
+ order by t.name;
+
+-- name: GetTagTotals :many
+-- Totals count the whole amount of every tagged transaction, in the base
+-- currency of the user; a transaction with two tags counts towards both.
+select t.id, t.name,
+       count(tr.id)::bigint as transactions,
+       coalesce(sum(to_base_currency(tr.user_id, tr.amount, tr.currency, tr.date)) filter (where tr.type = 'income'), 0)::bigint as income,
+       coalesce(sum(to_base_currency(tr.user_id, tr.amount, tr.currency, tr.date)) filter (where tr.type = 'expense'), 0)::bigint as expense
+  from tags t
+  left join transaction_tags tt on tt.tag_id = t.id
+  left join transactions tr
+    on tr.id = tt.transaction_id
+   and (sqlc.narg(start_date)::date is null or tr.date >= sqlc.narg(start_date))
+   and (sqlc.narg(end_date)::date is null or tr.date <= sqlc.narg(end_date))
+ where t.user_id = sqlc.arg(user_id)
+ group by t.id
+ order by t.name;
@@ -1,4 +1,4 @@
--- name: CreateTransaction :exec
+-- name: CreateTransaction :one
 insert into transactions (
   description,
   amount,
@@ -6,29 +6,71 @@ insert into transactions (
   type,
   user_id,
   account_id,
-  category_id
+  category_id,
+  payee_id
 )
-values ($1, $2, $3, $4, $5, $6, $7);
+values ($1, $2, $3, $4, $5, $6, $7, $8)
+returning id;
 
 -- name: GetTrasaction :one
 select *
   from transactions
- where id = $1;
+ where id = $1
+   and user_id = $2;
 
--- name: GetAllTransactions :many
+-- name: ListTransactions :many
+-- Every filter is optional: a NULL argument disables it. The category filter
+-- also matches split lines. all_tags requires every tag, any_tags at least
+-- one and no_tags none; the tag lists must not repeat names. The cursor holds
+-- the sort key and id of the last row of the previous page.
 select *
   from transactions
- where user_id = $1;
-
--- name: GetAllTransactionsByAccount :many
-select *
-  from transactions
- where account_id = $1;
-
--- name: GetAllTransactionsByCategory :many
-select *
-  from transactions
- where category_id = $1;
+ where transactions.user_id = sqlc.arg(user_id)
+   and (sqlc.narg(account_id)::uuid is null or account_id = sqlc.narg(account_id))
+   and (sqlc.narg(category_id)::uuid is null or exists (
+         select 1
+           from transaction_lines l
+          where l.transaction_id = transactions.id
+            and l.category_id = sqlc.narg(category_id)))
+   and (sqlc.narg(type)::transaction_type is null or type = sqlc.narg(type))
+   and (sqlc.narg(start_date)::date is null or date >= sqlc.narg(start_date))
+   and (sqlc.narg(end_date)::date is null or date <= sqlc.narg(end_date))
+   and (sqlc.narg(min_amount)::bigint is null or amount >= sqlc.narg(min_amount))
+   and (sqlc.narg(max_amount)::bigint is null or amount <= sqlc.narg(max_amount))
+   and (sqlc.narg(search)::text is null or strpos(lower(description), lower(sqlc.narg(search))) > 0)
+   and (sqlc.narg(payee_id)::uuid is null or payee_id = sqlc.narg(payee_id))
+   and (sqlc.narg(all_tags)::text[] is null or (
+         select count(*)
+           from transaction_tags tt
+           join tags tg on tg.id = tt.tag_id
+          where tt.transaction_id = transactions.id
+            and tg.name = any(sqlc.narg(all_tags))) = cardinality(sqlc.narg(all_tags)))
+   and (sqlc.narg(any_tags)::text[] is null or exists (
+         select 1
+           from transaction_tags tt
+           join tags tg on tg.id = tt.tag_id
+          where tt.transaction_id = transactions.id
+            and tg.name = any(sqlc.narg(any_tags))))
+   and (sqlc.narg(no_tags)::text[] is null or not exists (
+         select 1
+           from transaction_tags tt
+           join tags tg on tg.id = tt.tag_id
+          where tt.transaction_id = transactions.id
+            and tg.name = any(sqlc.narg(no_tags))))
+   and (sqlc.narg(cursor_id)::uuid is null or case sqlc.arg(sort)::text
+         when 'date_asc' then (date, id) > (sqlc.narg(cursor_date)::date, sqlc.narg(cursor_id))
+         when 'date_desc' then (date, id) < (sqlc.narg(cursor_date), sqlc.narg(cursor_id))
+         when 'amount_asc' then (amount, id) > (sqlc.narg(cursor_amount)::bigint, sqlc.narg(cursor_id))
+         when 'amount_desc' then (amount, id) < (sqlc.narg(cursor_amount), sqlc.narg(cursor_id))
+       end)
+ order by
+   case when sqlc.arg(sort) = 'date_asc' then date end asc,
+   case when sqlc.arg(sort) = 'date_desc' then date end desc,
+   case when sqlc.arg(sort) = 'amount_asc' then amount end asc,
+   case when sqlc.arg(sort) = 'amount_desc' then amount end desc,
+   case when sqlc.arg(sort) in ('date_asc', 'amount_asc') then id end asc,
+   case when sqlc.arg(sort) in ('date_desc', 'amount_desc') then id end desc
+ limit sqlc.arg(page_size)::int;
 
 -- name: UpdateTransaction :exec
 update transactions
@@ -36,9 +78,134 @@ update transactions
    amount = $3,
    date = $4,
    type = $5,
+   account_id = $6,
+   category_id = $7,
+   payee_id = $8,
    updated_at = now()
- where id = $1;
+ where id = $1
+   and user_id = $9;
 
 -- name: DeleteTransaction :exec
 delete from transactions
- where id = $1;
\ No newline at end of file
+ where id = $1
+   and user_id = $2;
+
+-- name: GetTransactionForUpdate :one
+select *
+  from transactions
+ where id = $1
+   and user_id = $2
+   for update;
+
+-- name: SetTransactionClearingStatus :exec
+update transactions
+   set clearing_status = $3,
+       updated_at = now()
+ where id = $1
+   and user_id = $2;
+
+-- name: UnlockTransaction :execrows
+-- A reconciled transaction goes back to cleared, so it can be edited and is
+-- picked up again by the next reconciliation of its account.
+update transactions
+   set clearing_status = 'cleared',
+       updated_at = now()
+ where id = $1
+   and user_id = $2
+   and clearing_status = 'reconciled';
+
+-- name: CreateImportedTransaction :one
+-- Lines whose import_hash already exists in the account are skipped and
+-- return no row.
+insert into transactions (
+  description,
+  amount,
+  date,
+  type,
+  user_id,
+  account_id,
+  category_id,
+  import_hash,
+  payee_id
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+on conflict (account_id, import_hash) do nothing
+returning id;
+
+
+-- name: CreateTransactionSplit :exec
+insert into transaction_splits (
+  transaction_id,
+  category_id,
+  amount,
+  memo,
+  position
+)
+values ($1, $2, $3, $4, $5);
+
+-- name: GetTransactionSplits :many
+select *
+  from transaction_splits
+ where transaction_id = $1
+ order by position;
+
+-- name: DeleteTransactionSplits :exec
+delete from transaction_splits
+ where transaction_id = $1;
+
+-- name: CreateInstallment :one
+insert into transactions (
+  description,
+  amount,
+  date,
+  type,
+  user_id,
+  account_id,
+  category_id,
+  installment_id,
+  installment_number,
+  installment_count,
+  payee_id
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+returning id;
+
+-- name: GetInstallments :many
+select *
+  from transactions
+ where installment_id = $1
+   and user_id = $2
+ order by installment_number;
+
+-- name: GetInstallmentsForUpdate :many
+select *
+  from transactions
+ where installment_id = $1
+   and user_id = $2
+ order by installment_number
+   for update;
+
+-- name: DeleteInstallments :exec
+delete from transactions
+ where installment_id = $1
+   and user_id = $2;
+
+-- name: ListRuleCandidates :many
+-- Income and expenses that rules can change, oldest first. Reconciled
+-- transactions and those in a closed period are locked and left out.
+select *
+  from transactions
+ where transactions.user_id = sqlc.arg(user_id)
+   and type in ('income', 'expense')
+   and clearing_status <> 'reconciled'
+   and date > coalesce((select b.closed_through from book_closings b where b.user_id = sqlc.arg(user_id)), '-infinity')
+   and (sqlc.narg(account_id)::uuid is null or account_id = sqlc.narg(account_id))
+   and (sqlc.narg(start_date)::date is null or date >= sqlc.narg(start_date))
+   and (sqlc.narg(end_date)::date is null or date <= sqlc.narg(end_date))
+ order by date, id
+ limit sqlc.arg(row_limit)::int;
+
+-- name: GetSplitTransactionIDs :many
+select distinct transaction_id
+  from transaction_splits
+ where transaction_id = any(sqlc.arg(transaction_ids)::uuid[]);
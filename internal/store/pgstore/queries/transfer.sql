@@ -0,0 +1,50 @@
+-- name: CreateTransferLeg :exec
+insert into transactions (
+  description,
+  amount,
+  date,
+  type,
+  user_id,
+  account_id,
+  transfer_id,
+  exchange_rate
+)
+values ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetTransferLegs :many
+select *
+  from transactions
+ where transfer_id = $1
+   and user_id = $2
+ order by type;
+
+-- name: GetTransferLegsForUpdate :many
+select *
+  from transactions
+ where transfer_id = $1
+   and user_id = $2
+ order by type
+   for update;
+
+-- name: GetAllTransferLegs :many
+select *
+  from transactions
+ where user_id = $1
+   and transfer_id is not null
+ order by date desc, transfer_id, type;
+
+-- name: UpdateTransferLeg :exec
+update transactions
+   set description = $2,
+   amount = $3,
+   date = $4,
+   account_id = $5,
+   exchange_rate = $6,
+   updated_at = now()
+ where id = $1
+   and user_id = $7;
+
+-- name: DeleteTransfer :exec
+delete from transactions
+ where transfer_id = $1
+   and user_id = $2;
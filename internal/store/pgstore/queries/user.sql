@@ -21,8 +21,23 @@ SET
   name = $2, 
   email = $3, 
   password = $4, 
+  base_currency = $5,
   updated_at = now() 
 WHERE id=$1;
 
 -- name: DeleteUser :exec
-DELETE FROM users WHERE id = $1;
\ No newline at end of file
+DELETE FROM users WHERE id = $1;
+
+-- name: DisableUser :exec
+UPDATE users
+SET
+  disabled_at = coalesce(disabled_at, now()),
+  updated_at = now()
+WHERE id = $1;
+
+-- name: EnableUser :exec
+UPDATE users
+SET
+  disabled_at = NULL,
+  updated_at = now()
+WHERE id = $1;
@@ -0,0 +1,46 @@
+package pgstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// UnitOfWork runs a group of queries atomically. The *db.Queries handed to fn
+// is bound to a single database transaction.
+type UnitOfWork interface {
+	ExecTx(ctx context.Context, fn func(q *db.Queries) error) error
+}
+
+type Store struct {
+	*db.Queries
+	pool *pgxpool.Pool
+}
+
+func NewStore(pool *pgxpool.Pool) *Store {
+	return &Store{
+		Queries: db.New(pool),
+		pool:    pool,
+	}
+}
+
+// ExecTx commits when fn returns nil and rolls back otherwise.
+func (s *Store) ExecTx(ctx context.Context, fn func(q *db.Queries) error) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error on begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(s.WithTx(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error on commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package tag
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+)
+
+// maxNameLength keeps tags short labels such as "vacation-2026".
+const maxNameLength = 50
+
+type TagReq struct {
+	Name string `json:"name"`
+}
+
+func (r *TagReq) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	r.Name = NormalizeName(r.Name)
+	eval.CheckField(ValidName(r.Name), "name", "this field must have up to 50 chars and no commas")
+
+	return eval
+}
+
+type TagRes struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func TagToResponse(t *db.Tag) TagRes {
+	return TagRes{
+		ID:        t.ID.String(),
+		Name:      t.Name,
+		CreatedAt: t.CreatedAt.Time,
+		UpdatedAt: t.UpdatedAt.Time,
+	}
+}
+
+type TagTotalsFilters struct {
+	StartDate *string `json:"start_date,omitempty"`
+	EndDate   *string `json:"end_date,omitempty"`
+}
+
+func (f *TagTotalsFilters) Valid(context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	if f.StartDate != nil {
+		eval.CheckField(validator.Date(*f.StartDate), "start_date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	if f.EndDate != nil {
+		eval.CheckField(validator.Date(*f.EndDate), "end_date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	return eval
+}
+
+type TagTotalRes struct {
+	ID           string      `json:"id"`
+	Name         string      `json:"name"`
+	Transactions int64       `json:"transactions"`
+	Income       money.Money `json:"income"`
+	Expense      money.Money `json:"expense"`
+	Net          money.Money `json:"net"`
+}
+
+// NormalizeName trims and lowercases a tag name, so "Vacation " and
+// "vacation" are the same tag.
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// ValidName reports whether a normalized name can be used as a tag. Commas
+// are rejected because tag filters take comma-separated lists.
+func ValidName(name string) bool {
+	return validator.NotBlank(name) && validator.MaxChars(name, maxNameLength) && !strings.Contains(name, ",")
+}
+
+// NormalizeNames normalizes every name and drops repeated ones, keeping the
+// first occurrence.
+func NormalizeNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+
+	for _, name := range names {
+		name = NormalizeName(name)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+
+	return result
+}
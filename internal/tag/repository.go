@@ -0,0 +1,90 @@
+package tag
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EduardoMark/my-finance-api/internal/exchangerate"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type Repository interface {
+	Create(ctx context.Context, arg db.CreateTagParams) error
+	GetTag(ctx context.Context, id, userID uuid.UUID) (*db.Tag, error)
+	GetTags(ctx context.Context, userID uuid.UUID) ([]*db.Tag, error)
+	Update(ctx context.Context, arg db.UpdateTagParams) error
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+	GetTotals(ctx context.Context, arg db.GetTagTotalsParams) ([]*db.GetTagTotalsRow, error)
+}
+
+type tagRepository struct {
+	db *db.Queries
+}
+
+func NewTagRepository(db *db.Queries) Repository {
+	return &tagRepository{db: db}
+}
+
+var ErrTagNotFound = errors.New("tag not found")
+var ErrDuplicatedTag = errors.New("tag already exist")
+
+func (r *tagRepository) Create(ctx context.Context, arg db.CreateTagParams) error {
+	var pgErr *pgconn.PgError
+
+	if err := r.db.CreateTag(ctx, arg); err != nil {
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrDuplicatedTag
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *tagRepository) GetTag(ctx context.Context, id, userID uuid.UUID) (*db.Tag, error) {
+	record, err := r.db.GetTag(ctx, db.GetTagParams{ID: id, UserID: userID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+func (r *tagRepository) GetTags(ctx context.Context, userID uuid.UUID) ([]*db.Tag, error) {
+	return r.db.GetTags(ctx, userID)
+}
+
+func (r *tagRepository) Update(ctx context.Context, arg db.UpdateTagParams) error {
+	var pgErr *pgconn.PgError
+
+	if _, err := r.GetTag(ctx, arg.ID, arg.UserID); err != nil {
+		return err
+	}
+
+	if err := r.db.UpdateTag(ctx, arg); err != nil {
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrDuplicatedTag
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *tagRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	if _, err := r.GetTag(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return r.db.DeleteTag(ctx, db.DeleteTagParams{ID: id, UserID: userID})
+}
+
+func (r *tagRepository) GetTotals(ctx context.Context, arg db.GetTagTotalsParams) ([]*db.GetTagTotalsRow, error) {
+	rows, err := r.db.GetTagTotals(ctx, arg)
+	return rows, exchangerate.MissingRate(err)
+}
@@ -0,0 +1,135 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Service interface {
+	Create(ctx context.Context, userID string, req *TagReq) error
+	GetTag(ctx context.Context, userID, id string) (*TagRes, error)
+	GetTags(ctx context.Context, userID string) ([]TagRes, error)
+	Update(ctx context.Context, userID, id string, req *TagReq) error
+	Delete(ctx context.Context, userID, id string) error
+	GetTotals(ctx context.Context, userID string, filters *TagTotalsFilters) ([]TagTotalRes, error)
+}
+
+type tagService struct {
+	repo Repository
+}
+
+func NewTagService(repo Repository) Service {
+	return &tagService{repo: repo}
+}
+
+func (s *tagService) Create(ctx context.Context, userID string, req *TagReq) error {
+	arg := db.CreateTagParams{
+		UserID: uuid.MustParse(userID),
+		Name:   req.Name,
+	}
+
+	if err := s.repo.Create(ctx, arg); err != nil {
+		if errors.Is(err, ErrDuplicatedTag) {
+			return err
+		}
+		return fmt.Errorf("service create: %w", err)
+	}
+
+	return nil
+}
+
+func (s *tagService) GetTag(ctx context.Context, userID, id string) (*TagRes, error) {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrTagNotFound
+	}
+
+	record, err := s.repo.GetTag(ctx, idUUID, uuid.MustParse(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	res := TagToResponse(record)
+	return &res, nil
+}
+
+func (s *tagService) GetTags(ctx context.Context, userID string) ([]TagRes, error) {
+	records, err := s.repo.GetTags(ctx, uuid.MustParse(userID))
+	if err != nil {
+		return nil, fmt.Errorf("service get tags: %w", err)
+	}
+
+	res := make([]TagRes, len(records))
+	for i, record := range records {
+		res[i] = TagToResponse(record)
+	}
+
+	return res, nil
+}
+
+func (s *tagService) Update(ctx context.Context, userID, id string, req *TagReq) error {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrTagNotFound
+	}
+
+	arg := db.UpdateTagParams{
+		ID:     idUUID,
+		Name:   req.Name,
+		UserID: uuid.MustParse(userID),
+	}
+
+	return s.repo.Update(ctx, arg)
+}
+
+func (s *tagService) Delete(ctx context.Context, userID, id string) error {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrTagNotFound
+	}
+
+	return s.repo.Delete(ctx, idUUID, uuid.MustParse(userID))
+}
+
+// GetTotals sums the income and expenses tagged with each tag. It expects
+// filters that already passed Valid.
+func (s *tagService) GetTotals(ctx context.Context, userID string, filters *TagTotalsFilters) ([]TagTotalRes, error) {
+	arg := db.GetTagTotalsParams{UserID: uuid.MustParse(userID)}
+
+	if filters.StartDate != nil {
+		date, _ := time.Parse("2006-01-02", *filters.StartDate)
+		arg.StartDate = pgtype.Date{Time: date, Valid: true}
+	}
+
+	if filters.EndDate != nil {
+		date, _ := time.Parse("2006-01-02", *filters.EndDate)
+		arg.EndDate = pgtype.Date{Time: date, Valid: true}
+	}
+
+	records, err := s.repo.GetTotals(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("service get totals: %w", err)
+	}
+
+	res := make([]TagTotalRes, len(records))
+	for i, record := range records {
+		income, expense := money.FromCents(record.Income), money.FromCents(record.Expense)
+		res[i] = TagTotalRes{
+			ID:           record.ID.String(),
+			Name:         record.Name,
+			Transactions: record.Transactions,
+			Income:       income,
+			Expense:      expense,
+			Net:          income - expense,
+		}
+	}
+
+	return res, nil
+}
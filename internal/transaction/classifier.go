@@ -0,0 +1,115 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/google/uuid"
+)
+
+// maxSuggestions is how many categories a suggestion returns at most.
+const maxSuggestions = 3
+
+// SuggestCategory ranks the user's categories for a description with a
+// multinomial naive Bayes model. Its counts are kept by the database as
+// transactions change, so there is no training step here.
+func (s *transactionService) SuggestCategory(ctx context.Context, userID string, filters *SuggestCategoryFilters) (*CategorySuggestionResponse, error) {
+	userUUID := uuid.MustParse(userID)
+
+	params := db.GetClassifierCategoriesParams{UserID: userUUID}
+	if filters.Type != nil {
+		params.Type = db.NullTransactionType{TransactionType: db.TransactionType(*filters.Type), Valid: true}
+	}
+
+	categories, err := s.repo.GetClassifierCategories(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("service suggest category: %w", err)
+	}
+
+	tokens, err := s.repo.GetClassifierTokens(ctx, db.GetClassifierTokensParams{
+		UserID:      userUUID,
+		Description: *filters.Description,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service suggest category: %w", err)
+	}
+
+	vocabulary, err := s.repo.CountClassifierVocabulary(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("service suggest category: %w", err)
+	}
+
+	res := &CategorySuggestionResponse{
+		Description: *filters.Description,
+		Suggestions: rankCategories(categories, tokens, vocabulary),
+	}
+	for _, c := range categories {
+		res.TrainedOn += int64(c.Documents)
+	}
+
+	return res, nil
+}
+
+// rankCategories scores each category by log P(category) plus the sum of
+// log P(token | category) over the known tokens of the description, with
+// add-one smoothing, and turns the scores into probabilities. Tokens no
+// category has seen carry no evidence and are ignored; when none is known
+// there is nothing to suggest.
+func rankCategories(categories []*db.GetClassifierCategoriesRow, tokens []*db.GetClassifierTokensRow, vocabulary int64) []CategorySuggestion {
+	suggestions := []CategorySuggestion{}
+	if len(categories) == 0 || len(tokens) == 0 {
+		return suggestions
+	}
+
+	known := make(map[string]bool)
+	counts := make(map[uuid.UUID]map[string]int32)
+	for _, t := range tokens {
+		known[t.Token] = true
+		if counts[t.CategoryID] == nil {
+			counts[t.CategoryID] = make(map[string]int32)
+		}
+		counts[t.CategoryID][t.Token] = t.Count
+	}
+
+	var documents int64
+	for _, c := range categories {
+		documents += int64(c.Documents)
+	}
+
+	scores := make([]float64, len(categories))
+	best := math.Inf(-1)
+	for i, c := range categories {
+		score := math.Log(float64(c.Documents) / float64(documents))
+		for token := range known {
+			score += math.Log(float64(counts[c.CategoryID][token]+1) / float64(int64(c.Tokens)+vocabulary))
+		}
+		scores[i] = score
+		best = max(best, score)
+	}
+
+	// Subtracting the best score before exponentiating keeps long
+	// descriptions from underflowing to zero.
+	var total float64
+	for i := range scores {
+		scores[i] = math.Exp(scores[i] - best)
+		total += scores[i]
+	}
+
+	for i, c := range categories {
+		suggestions = append(suggestions, CategorySuggestion{
+			CategoryID: c.CategoryID.String(),
+			Name:       c.Name,
+			Type:       string(c.Type),
+			Confidence: math.Round(scores[i]/total*1e4) / 1e4,
+		})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Confidence > suggestions[j].Confidence
+	})
+
+	return suggestions[:min(len(suggestions), maxSuggestions)]
+}
@@ -0,0 +1,69 @@
+package transaction
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursor is the keyset position after which the next page starts. It is
+// handed to clients as opaque base64.
+type cursor struct {
+	Sort  string    `json:"s"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func newCursor(sort string, last *db.Transaction) cursor {
+	c := cursor{Sort: sort, ID: last.ID}
+
+	switch sort {
+	case "amount_asc", "amount_desc":
+		c.Value = strconv.FormatInt(last.Amount.Cents(), 10)
+	default:
+		c.Value = last.Date.Time.Format("2006-01-02")
+	}
+
+	return c
+}
+
+func (c cursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	if !sortOptions[c.Sort] || c.ID == uuid.Nil {
+		return c, ErrInvalidCursor
+	}
+
+	switch c.Sort {
+	case "amount_asc", "amount_desc":
+		_, err = strconv.ParseInt(c.Value, 10, 64)
+	default:
+		_, err = time.Parse("2006-01-02", c.Value)
+	}
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	return c, nil
+}
@@ -2,19 +2,59 @@ package transaction
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/tag"
 	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
 )
 
 type TransactionCreateRequest struct {
-	Description string  `json:"description" validate:"required"`
-	Amount      float64 `json:"amount" validate:"required"`
-	Date        string  `json:"date" validate:"required"` // formato: YYYY-MM-DD
-	Type        string  `json:"type" validate:"required"` // "income" ou "expense"
-	AccountID   string  `json:"account_id" validate:"required"`
-	CategoryID  string  `json:"category_id" validate:"required"`
+	Description string         `json:"description" validate:"required"`
+	Amount      money.Money    `json:"amount" validate:"required"`
+	Date        string         `json:"date" validate:"required"` // formato: YYYY-MM-DD
+	Type        string         `json:"type" validate:"required"` // "income" ou "expense"
+	AccountID   string         `json:"account_id" validate:"required"`
+	CategoryID  string         `json:"category_id"` // omitido quando há splits
+	Splits      []SplitRequest `json:"splits,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+
+	// PayeeID is optional: when omitted the payee is found by the aliases
+	// contained in the description. Without a category the default category
+	// of the payee is used.
+	PayeeID string `json:"payee_id,omitempty"`
+
+	// Installments split the amount into a monthly series of 2 or more
+	// starting on Date; the cents left over go to the first installment.
+	Installments int `json:"installments,omitempty"`
+
+	// Currency is optional and only checked against the account currency;
+	// the amount is always in the currency of the account.
+	Currency string `json:"currency,omitempty"`
+
+	ImportHash string `json:"-"` // preenchido apenas por importações
+}
+
+const maxInstallments = 72
+
+// SplitRequest is one line of a split transaction. Amounts are positive and
+// the lines must add up to the transaction amount.
+type SplitRequest struct {
+	CategoryID string      `json:"category_id"`
+	Amount     money.Money `json:"amount"`
+	Memo       string      `json:"memo"`
+}
+
+type SplitResponse struct {
+	ID         string      `json:"id"`
+	CategoryID string      `json:"category_id"`
+	Amount     money.Money `json:"amount"`
+	Memo       string      `json:"memo"`
 }
 
 func (r *TransactionCreateRequest) Valid(ctx context.Context) validator.Evaluator {
@@ -25,28 +65,104 @@ func (r *TransactionCreateRequest) Valid(ctx context.Context) validator.Evaluato
 	eval.CheckField(validator.NotBlank(r.Date), "date", "this field cannot be empty")
 	eval.CheckField(r.Type == "income" || r.Type == "expense", "type", "this field must be 'income' or 'expense'")
 	eval.CheckField(validator.NotBlank(r.AccountID), "account_id", "this field cannot be empty")
-	eval.CheckField(validator.NotBlank(r.CategoryID), "category_id", "this field cannot be empty")
+
+	if r.Currency != "" {
+		eval.CheckField(validator.Currency(r.Currency), "currency", "this field must be an upper case ISO 4217 code such as BRL")
+	}
+
+	if r.PayeeID != "" {
+		eval.CheckField(validator.UUID(r.PayeeID), "payee_id", "this field must be a valid UUID")
+	}
+
+	if len(r.Splits) > 0 {
+		eval.CheckField(!validator.NotBlank(r.CategoryID), "category_id", "this field must be omitted when splits are sent")
+		checkSplits(&eval, r.Splits)
+		eval.CheckField(splitsTotal(r.Splits) == r.Amount, "splits", ErrSplitTotal.Error())
+	}
+
+	r.Tags = tag.NormalizeNames(r.Tags)
+	checkTags(&eval, r.Tags)
+
+	// 0 means the field was omitted.
+	eval.CheckField(r.Installments == 0 || (r.Installments >= 2 && r.Installments <= maxInstallments), "installments", fmt.Sprintf("this field must be between 2 and %d", maxInstallments))
+	if r.Installments > 1 {
+		eval.CheckField(len(r.Splits) == 0, "splits", "this field cannot be used with installments")
+		eval.CheckField(r.Amount.Cents() >= int64(r.Installments), "amount", "this field must be at least one cent per installment")
+	}
 
 	return eval
 }
 
+func checkSplits(eval *validator.Evaluator, splits []SplitRequest) {
+	for i, split := range splits {
+		field := fmt.Sprintf("splits[%d]", i)
+		eval.CheckField(validator.UUID(split.CategoryID), field+".category_id", "this field must be a valid UUID")
+		eval.CheckField(split.Amount > 0, field+".amount", "this field must be greater than 0")
+	}
+}
+
+func checkTags(eval *validator.Evaluator, tags []string) {
+	for i, name := range tags {
+		field := fmt.Sprintf("tags[%d]", i)
+		eval.CheckField(tag.ValidName(name), field, "this field must have up to 50 chars and no commas")
+	}
+}
+
+func splitsTotal(splits []SplitRequest) money.Money {
+	var total money.Money
+	for _, split := range splits {
+		total += split.Amount
+	}
+	return total
+}
+
 type TransactionUpdateRequest struct {
-	Description *string  `json:"description,omitempty"`
-	Amount      *float64 `json:"amount,omitempty"`
-	Date        *string  `json:"date,omitempty"` // formato: YYYY-MM-DD
-	Type        *string  `json:"type,omitempty"` // "income" ou "expense"
-	AccountID   *string  `json:"account_id,omitempty"`
-	CategoryID  *string  `json:"category_id,omitempty"`
+	Description *string      `json:"description,omitempty"`
+	Amount      *money.Money `json:"amount,omitempty"`
+	Date        *string      `json:"date,omitempty"` // formato: YYYY-MM-DD
+	Type        *string      `json:"type,omitempty"` // "income" ou "expense"
+	AccountID   *string      `json:"account_id,omitempty"`
+	CategoryID  *string      `json:"category_id,omitempty"`
+
+	// Splits replaces every split line when sent; an empty list removes
+	// them. They must add up to the resulting amount.
+	Splits *[]SplitRequest `json:"splits,omitempty"`
+
+	// Tags replaces every tag when sent; an empty list removes them.
+	Tags *[]string `json:"tags,omitempty"`
+
+	// PayeeID replaces the payee when sent; an empty string removes it.
+	PayeeID *string `json:"payee_id,omitempty"`
+
+	// Cleared marks the transaction as seen on the bank statement, or back
+	// as uncleared. Reconciled transactions must be unlocked first.
+	Cleared *bool `json:"cleared,omitempty"`
 }
 
 func (r *TransactionUpdateRequest) Valid(ctx context.Context) validator.Evaluator {
 	var eval validator.Evaluator
 
 	hasAtLeastOneField := r.Description != nil || r.Amount != nil || r.Date != nil ||
-		r.Type != nil || r.AccountID != nil || r.CategoryID != nil
+		r.Type != nil || r.AccountID != nil || r.CategoryID != nil || r.Splits != nil || r.Tags != nil ||
+		r.PayeeID != nil || r.Cleared != nil
 
 	eval.CheckField(hasAtLeastOneField, "fields", "at least one field must be sent to update")
 
+	if r.PayeeID != nil && *r.PayeeID != "" {
+		eval.CheckField(validator.UUID(*r.PayeeID), "payee_id", "this field must be a valid UUID")
+	}
+
+	if r.Splits != nil && len(*r.Splits) > 0 {
+		eval.CheckField(r.CategoryID == nil, "category_id", "this field must be omitted when splits are sent")
+		checkSplits(&eval, *r.Splits)
+	}
+
+	if r.Tags != nil {
+		tags := tag.NormalizeNames(*r.Tags)
+		r.Tags = &tags
+		checkTags(&eval, tags)
+	}
+
 	if r.Amount != nil {
 		eval.CheckField(*r.Amount > 0, "amount", "this field must be greater than 0")
 	}
@@ -59,16 +175,82 @@ func (r *TransactionUpdateRequest) Valid(ctx context.Context) validator.Evaluato
 }
 
 type TransactionResponse struct {
-	ID          string    `json:"id"`
-	Description string    `json:"description"`
-	Amount      float64   `json:"amount"`
-	Date        string    `json:"date"`
-	Type        string    `json:"type"`
-	AccountID   string    `json:"account_id"`
-	CategoryID  string    `json:"category_id"`
-	UserID      string    `json:"user_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID            string          `json:"id"`
+	Description   string          `json:"description"`
+	Amount        money.Money     `json:"amount"`
+	Currency      string          `json:"currency"`
+	Date          string          `json:"date"`
+	Type          string          `json:"type"`
+	AccountID     string          `json:"account_id"`
+	CategoryID    string          `json:"category_id,omitempty"`
+	PayeeID       string          `json:"payee_id,omitempty"`
+	TransferID    string          `json:"transfer_id,omitempty"`
+	RecurringID   string          `json:"recurring_id,omitempty"`
+	InstallmentID string          `json:"installment_id,omitempty"`
+	Installment   string          `json:"installment,omitempty"` // "3/10"
+	Splits        []SplitResponse `json:"splits,omitempty"`
+	Tags          []string        `json:"tags,omitempty"`
+	Status        string          `json:"status"` // "uncleared", "cleared" ou "reconciled"
+	UserID        string          `json:"user_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// InstallmentUpdateRequest edits every installment of a series. Amount is
+// the total of the purchase and Date the date of the first installment; both
+// are spread over the series again.
+type InstallmentUpdateRequest struct {
+	Description *string      `json:"description,omitempty"`
+	Amount      *money.Money `json:"amount,omitempty"`
+	Date        *string      `json:"date,omitempty"` // formato: YYYY-MM-DD
+	AccountID   *string      `json:"account_id,omitempty"`
+	CategoryID  *string      `json:"category_id,omitempty"`
+	Tags        *[]string    `json:"tags,omitempty"`
+}
+
+func (r *InstallmentUpdateRequest) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	hasAtLeastOneField := r.Description != nil || r.Amount != nil || r.Date != nil ||
+		r.AccountID != nil || r.CategoryID != nil || r.Tags != nil
+
+	eval.CheckField(hasAtLeastOneField, "fields", "at least one field must be sent to update")
+
+	if r.Description != nil {
+		eval.CheckField(validator.NotBlank(*r.Description), "description", "this field cannot be empty")
+	}
+
+	if r.Amount != nil {
+		eval.CheckField(*r.Amount > 0, "amount", "this field must be greater than 0")
+	}
+
+	if r.Date != nil {
+		eval.CheckField(validator.Date(*r.Date), "date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	if r.AccountID != nil {
+		eval.CheckField(validator.UUID(*r.AccountID), "account_id", "this field must be a valid UUID")
+	}
+
+	if r.CategoryID != nil {
+		eval.CheckField(validator.UUID(*r.CategoryID), "category_id", "this field must be a valid UUID")
+	}
+
+	if r.Tags != nil {
+		tags := tag.NormalizeNames(*r.Tags)
+		r.Tags = &tags
+		checkTags(&eval, tags)
+	}
+
+	return eval
+}
+
+type InstallmentSeriesResponse struct {
+	ID           string                 `json:"id"`
+	Description  string                 `json:"description"`
+	Amount       money.Money            `json:"amount"` // total da compra
+	Count        int                    `json:"count"`
+	Installments []*TransactionResponse `json:"installments"`
 }
 
 type TransactionFilters struct {
@@ -77,6 +259,159 @@ type TransactionFilters struct {
 	Type       *string `json:"type,omitempty"`
 	StartDate  *string `json:"start_date,omitempty"`
 	EndDate    *string `json:"end_date,omitempty"`
+	MinAmount  *string `json:"min_amount,omitempty"`
+	MaxAmount  *string `json:"max_amount,omitempty"`
+	Search     *string `json:"q,omitempty"`
+	PayeeID    *string `json:"payee_id,omitempty"`
+	AllTags    *string `json:"tag,omitempty"`     // nomes separados por vírgula
+	AnyTags    *string `json:"any_tag,omitempty"` // nomes separados por vírgula
+	NoTags     *string `json:"no_tag,omitempty"`  // nomes separados por vírgula
+	Sort       *string `json:"sort,omitempty"`    // date_desc (padrão), date_asc, amount_desc ou amount_asc
+	Limit      *string `json:"limit,omitempty"`
+	Cursor     *string `json:"cursor,omitempty"`
+}
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+var typeOptions = map[string]bool{
+	string(db.TransactionTypeIncome):      true,
+	string(db.TransactionTypeExpense):     true,
+	string(db.TransactionTypeTransferIn):  true,
+	string(db.TransactionTypeTransferOut): true,
+}
+
+var sortOptions = map[string]bool{
+	"date_desc":   true,
+	"date_asc":    true,
+	"amount_desc": true,
+	"amount_asc":  true,
+}
+
+func (f *TransactionFilters) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	if f.AccountID != nil {
+		eval.CheckField(validator.UUID(*f.AccountID), "account_id", "this field must be a valid UUID")
+	}
+
+	if f.CategoryID != nil {
+		eval.CheckField(validator.UUID(*f.CategoryID), "category_id", "this field must be a valid UUID")
+	}
+
+	if f.PayeeID != nil {
+		eval.CheckField(validator.UUID(*f.PayeeID), "payee_id", "this field must be a valid UUID")
+	}
+
+	if f.Type != nil {
+		eval.CheckField(typeOptions[*f.Type], "type", "this field must be 'income', 'expense', 'transfer_in' or 'transfer_out'")
+	}
+
+	if f.StartDate != nil {
+		eval.CheckField(validator.Date(*f.StartDate), "start_date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	if f.EndDate != nil {
+		eval.CheckField(validator.Date(*f.EndDate), "end_date", "this field must be a date in the format YYYY-MM-DD")
+	}
+
+	if f.MinAmount != nil {
+		_, err := money.Parse(*f.MinAmount)
+		eval.CheckField(err == nil, "min_amount", "this field must be a decimal amount")
+	}
+
+	if f.MaxAmount != nil {
+		_, err := money.Parse(*f.MaxAmount)
+		eval.CheckField(err == nil, "max_amount", "this field must be a decimal amount")
+	}
+
+	for key, value := range map[string]*string{"tag": f.AllTags, "any_tag": f.AnyTags, "no_tag": f.NoTags} {
+		if value != nil {
+			eval.CheckField(len(tagList(*value)) > 0, key, "this field must be a comma-separated list of tag names")
+		}
+	}
+
+	if f.Sort != nil {
+		eval.CheckField(sortOptions[*f.Sort], "sort", "this field must be date_desc, date_asc, amount_desc or amount_asc")
+	}
+
+	if f.Limit != nil {
+		limit, err := strconv.Atoi(*f.Limit)
+		eval.CheckField(err == nil && limit > 0 && limit <= maxPageSize, "limit", fmt.Sprintf("this field must be between 1 and %d", maxPageSize))
+	}
+
+	if f.Cursor != nil {
+		c, err := decodeCursor(*f.Cursor)
+		eval.CheckField(err == nil && c.Sort == f.sort(), "cursor", "this field is invalid for the requested sort")
+	}
+
+	return eval
+}
+
+func (f *TransactionFilters) sort() string {
+	if f.Sort == nil {
+		return "date_desc"
+	}
+	return *f.Sort
+}
+
+func (f *TransactionFilters) pageSize() int {
+	if f.Limit == nil {
+		return defaultPageSize
+	}
+
+	limit, _ := strconv.Atoi(*f.Limit)
+	return limit
+}
+
+// tagList splits a comma-separated filter into normalized tag names,
+// ignoring empty items.
+func tagList(value string) []string {
+	var names []string
+	for _, name := range tag.NormalizeNames(strings.Split(value, ",")) {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// SuggestCategoryFilters are the query parameters of a category suggestion.
+type SuggestCategoryFilters struct {
+	Description *string `json:"description,omitempty"`
+	Type        *string `json:"type,omitempty"` // "income" ou "expense"
+}
+
+func (f *SuggestCategoryFilters) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(f.Description != nil && validator.NotBlank(*f.Description), "description", "this field cannot be empty")
+
+	if f.Type != nil {
+		eval.CheckField(*f.Type == "income" || *f.Type == "expense", "type", "this field must be 'income' or 'expense'")
+	}
+
+	return eval
+}
+
+type CategorySuggestion struct {
+	CategoryID string  `json:"category_id"`
+	Name       string  `json:"name"`
+	Type       string  `json:"type"`
+	Confidence float64 `json:"confidence"` // de 0 a 1
+}
+
+type CategorySuggestionResponse struct {
+	Description string               `json:"description"`
+	TrainedOn   int64                `json:"trained_on"` // transações usadas pelo modelo
+	Suggestions []CategorySuggestion `json:"suggestions"`
+}
+
+type TransactionListResponse struct {
+	Data       []*TransactionResponse `json:"data"`
+	NextCursor *string                `json:"next_cursor"`
 }
 
 func TransactionToResponse(t *db.Transaction) TransactionResponse {
@@ -85,16 +420,54 @@ func TransactionToResponse(t *db.Transaction) TransactionResponse {
 		dateStr = t.Date.Time.Format("2006-01-02")
 	}
 
-	return TransactionResponse{
+	response := TransactionResponse{
 		ID:          t.ID.String(),
 		Description: t.Description,
 		Amount:      t.Amount,
+		Currency:    t.Currency,
 		Date:        dateStr,
 		Type:        string(t.Type),
 		AccountID:   t.AccountID.String(),
-		CategoryID:  t.CategoryID.String(),
+		Status:      string(t.ClearingStatus),
 		UserID:      t.UserID.String(),
 		CreatedAt:   t.CreatedAt.Time,
 		UpdatedAt:   t.UpdatedAt.Time,
 	}
+
+	// Transfer legs carry no category.
+	if t.CategoryID != uuid.Nil {
+		response.CategoryID = t.CategoryID.String()
+	}
+
+	if t.PayeeID.Valid {
+		response.PayeeID = uuid.UUID(t.PayeeID.Bytes).String()
+	}
+
+	if t.TransferID.Valid {
+		response.TransferID = uuid.UUID(t.TransferID.Bytes).String()
+	}
+
+	if t.RecurringID.Valid {
+		response.RecurringID = uuid.UUID(t.RecurringID.Bytes).String()
+	}
+
+	if t.InstallmentID.Valid {
+		response.InstallmentID = uuid.UUID(t.InstallmentID.Bytes).String()
+		response.Installment = fmt.Sprintf("%d/%d", t.InstallmentNumber.Int16, t.InstallmentCount.Int16)
+	}
+
+	return response
+}
+
+func SplitsToResponse(splits []*db.TransactionSplit) []SplitResponse {
+	response := make([]SplitResponse, len(splits))
+	for i, split := range splits {
+		response[i] = SplitResponse{
+			ID:         split.ID.String(),
+			CategoryID: split.CategoryID.String(),
+			Amount:     split.Amount,
+			Memo:       split.Memo,
+		}
+	}
+	return response
 }
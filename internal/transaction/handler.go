@@ -3,8 +3,15 @@ package transaction
 import (
 	"errors"
 	"net/http"
+	"net/url"
 
+	"github.com/EduardoMark/my-finance-api/internal/account"
+	"github.com/EduardoMark/my-finance-api/internal/books"
+	"github.com/EduardoMark/my-finance-api/internal/exchangerate"
 	"github.com/EduardoMark/my-finance-api/internal/middlewares"
+	"github.com/EduardoMark/my-finance-api/internal/reconciliation"
+	"github.com/EduardoMark/my-finance-api/internal/transfer"
+	"github.com/EduardoMark/my-finance-api/internal/validator"
 	"github.com/EduardoMark/my-finance-api/pkg/httputils"
 	"github.com/EduardoMark/my-finance-api/pkg/token"
 	"github.com/go-chi/chi/v5"
@@ -28,9 +35,14 @@ func (h *TransactionHandler) RegisterRoutes(r chi.Router) {
 
 		r.Post("/", h.Create)
 		r.Get("/", h.GetAllTransactions)
+		r.Get("/suggest-category", h.SuggestCategory)
+		r.Get("/installments/{id}", h.GetInstallments)
+		r.Put("/installments/{id}", h.UpdateInstallments)
+		r.Delete("/installments/{id}", h.DeleteInstallments)
 		r.Get("/{id}", h.GetTransaction)
 		r.Put("/{id}", h.UpdateTransaction)
 		r.Delete("/{id}", h.DeleteTransaction)
+		r.Post("/{id}/unlock", h.Unlock)
 	})
 }
 
@@ -50,10 +62,18 @@ func (h *TransactionHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.svc.Create(ctx, userID, *data); err != nil {
+		if problems := ownershipProblems(err); problems != nil {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+			return
+		}
 		if errors.Is(err, ErrTransactionNotFound) {
 			httputils.NotFound(w)
 			return
 		}
+		if errors.Is(err, account.ErrNegativeBalance) || errors.Is(err, books.ErrClosed) {
+			httputils.Error(w, r, http.StatusConflict, err.Error())
+			return
+		}
 		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -76,7 +96,7 @@ func (h *TransactionHandler) GetTransaction(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	transaction, err := h.svc.GetTransaction(ctx, id)
+	transaction, err := h.svc.GetTransaction(ctx, userID, id)
 	if err != nil {
 		if errors.Is(err, ErrTransactionNotFound) {
 			httputils.NotFound(w)
@@ -98,39 +118,68 @@ func (h *TransactionHandler) GetAllTransactions(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	filters := &TransactionFilters{}
+	query := r.URL.Query()
+	filters := &TransactionFilters{
+		AccountID:  queryParam(query, "account_id"),
+		CategoryID: queryParam(query, "category_id"),
+		Type:       queryParam(query, "type"),
+		StartDate:  queryParam(query, "start_date"),
+		EndDate:    queryParam(query, "end_date"),
+		MinAmount:  queryParam(query, "min_amount"),
+		MaxAmount:  queryParam(query, "max_amount"),
+		Search:     queryParam(query, "q"),
+		PayeeID:    queryParam(query, "payee_id"),
+		AllTags:    queryParam(query, "tag"),
+		AnyTags:    queryParam(query, "any_tag"),
+		NoTags:     queryParam(query, "no_tag"),
+		Sort:       queryParam(query, "sort"),
+		Limit:      queryParam(query, "limit"),
+		Cursor:     queryParam(query, "cursor"),
+	}
 
-	if accountID := r.URL.Query().Get("account_id"); accountID != "" {
-		filters.AccountID = &accountID
+	if problems := filters.Valid(ctx); len(problems) > 0 {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
 	}
 
-	if categoryID := r.URL.Query().Get("category_id"); categoryID != "" {
-		filters.CategoryID = &categoryID
+	transactions, err := h.svc.GetAllTransactions(ctx, userID, filters)
+	if err != nil {
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+		return
 	}
 
-	if transactionType := r.URL.Query().Get("type"); transactionType != "" {
-		filters.Type = &transactionType
+	_ = httputils.EncodeJson(w, r, http.StatusOK, transactions)
+}
+
+// SuggestCategory ranks categories for ?description= by how the user
+// categorized similar descriptions before.
+func (h *TransactionHandler) SuggestCategory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
 	}
 
-	if startDate := r.URL.Query().Get("start_date"); startDate != "" {
-		filters.StartDate = &startDate
+	query := r.URL.Query()
+	filters := &SuggestCategoryFilters{
+		Description: queryParam(query, "description"),
+		Type:        queryParam(query, "type"),
 	}
 
-	if endDate := r.URL.Query().Get("end_date"); endDate != "" {
-		filters.EndDate = &endDate
+	if problems := filters.Valid(ctx); len(problems) > 0 {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
 	}
 
-	transactions, err := h.svc.GetAllTransactions(ctx, userID, filters)
+	res, err := h.svc.SuggestCategory(ctx, userID, filters)
 	if err != nil {
-		if errors.Is(err, ErrTransactionNotFound) {
-			_ = httputils.EncodeJson(w, r, http.StatusOK, []*TransactionResponse{})
-			return
-		}
 		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	_ = httputils.EncodeJson(w, r, http.StatusOK, transactions)
+	_ = httputils.EncodeJson(w, r, http.StatusOK, res)
 }
 
 func (h *TransactionHandler) UpdateTransaction(w http.ResponseWriter, r *http.Request) {
@@ -154,11 +203,41 @@ func (h *TransactionHandler) UpdateTransaction(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if err := h.svc.UpdateTransaction(ctx, id, *data); err != nil {
+	if err := h.svc.UpdateTransaction(ctx, userID, id, *data); err != nil {
+		if problems := ownershipProblems(err); problems != nil {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+			return
+		}
+		if errors.Is(err, ErrTransferLegField) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"fields": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrSplitTotal) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"splits": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrSplitCategory) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"category_id": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrInstallmentField) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"fields": err.Error()})
+			return
+		}
+		if errors.Is(err, transfer.ErrTransferNotFound) || errors.Is(err, transfer.ErrInvalidAccount) ||
+			errors.Is(err, transfer.ErrSameAccount) || errors.Is(err, transfer.ErrToAmount) ||
+			errors.Is(err, exchangerate.ErrMissingRate) {
+			transfer.WriteUpdateError(w, r, err)
+			return
+		}
 		if errors.Is(err, ErrTransactionNotFound) {
 			httputils.NotFound(w)
 			return
 		}
+		if errors.Is(err, account.ErrNegativeBalance) || errors.Is(err, reconciliation.ErrLocked) || errors.Is(err, books.ErrClosed) {
+			httputils.Error(w, r, http.StatusConflict, err.Error())
+			return
+		}
 		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
 		return
 	}
@@ -181,14 +260,167 @@ func (h *TransactionHandler) DeleteTransaction(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	if err := h.svc.DeleteTransaction(ctx, id); err != nil {
+	if err := h.svc.DeleteTransaction(ctx, userID, id); err != nil {
+		if errors.Is(err, ErrTransactionNotFound) || errors.Is(err, transfer.ErrTransferNotFound) ||
+			errors.Is(err, ErrInstallmentNotFound) {
+			httputils.NotFound(w)
+			return
+		}
+		if errors.Is(err, account.ErrNegativeBalance) || errors.Is(err, reconciliation.ErrLocked) || errors.Is(err, books.ErrClosed) {
+			httputils.Error(w, r, http.StatusConflict, err.Error())
+			return
+		}
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Unlock turns a reconciled transaction back into cleared so it can be
+// edited again.
+func (h *TransactionHandler) Unlock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	if err := h.svc.Unlock(ctx, userID, chi.URLParam(r, "id")); err != nil {
 		if errors.Is(err, ErrTransactionNotFound) {
 			httputils.NotFound(w)
 			return
 		}
+		if errors.Is(err, ErrNotReconciled) {
+			httputils.Error(w, r, http.StatusConflict, err.Error())
+			return
+		}
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TransactionHandler) GetInstallments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	series, err := h.svc.GetInstallments(ctx, userID, chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, ErrInstallmentNotFound) {
+			httputils.NotFound(w)
+			return
+		}
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, series)
+}
+
+func (h *TransactionHandler) UpdateInstallments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*InstallmentUpdateRequest](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if err := h.svc.UpdateInstallments(ctx, userID, chi.URLParam(r, "id"), *data); err != nil {
+		if problems := ownershipProblems(err); problems != nil {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+			return
+		}
+		if errors.Is(err, ErrInstallmentAmount) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"amount": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrInstallmentNotFound) {
+			httputils.NotFound(w)
+			return
+		}
+		if errors.Is(err, account.ErrNegativeBalance) || errors.Is(err, reconciliation.ErrLocked) || errors.Is(err, books.ErrClosed) {
+			httputils.Error(w, r, http.StatusConflict, err.Error())
+			return
+		}
 		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (h *TransactionHandler) DeleteInstallments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	if err := h.svc.DeleteInstallments(ctx, userID, chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, ErrInstallmentNotFound) {
+			httputils.NotFound(w)
+			return
+		}
+		if errors.Is(err, account.ErrNegativeBalance) || errors.Is(err, reconciliation.ErrLocked) || errors.Is(err, books.ErrClosed) {
+			httputils.Error(w, r, http.StatusConflict, err.Error())
+			return
+		}
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func ownershipProblems(err error) validator.Evaluator {
+	var eval validator.Evaluator
+
+	if errors.Is(err, ErrInvalidAccount) || errors.Is(err, account.ErrAccountNotFound) {
+		eval.AddFieldError("account_id", err.Error())
+	}
+
+	if errors.Is(err, ErrInvalidCategory) || errors.Is(err, ErrCategoryRequired) {
+		eval.AddFieldError("category_id", err.Error())
+	}
+
+	if errors.Is(err, ErrInvalidPayee) {
+		eval.AddFieldError("payee_id", err.Error())
+	}
+
+	if errors.Is(err, ErrInvalidSplitCategory) {
+		eval.AddFieldError("splits", err.Error())
+	}
+
+	if errors.Is(err, ErrCurrencyMismatch) {
+		eval.AddFieldError("currency", err.Error())
+	}
+
+	return eval
+}
+
+// queryParam returns nil for absent or empty query parameters.
+func queryParam(query url.Values, key string) *string {
+	value := query.Get(key)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,312 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/reconciliation"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// installmentAmounts splits total into count parts; the cents that do not
+// divide evenly go to the first one.
+func installmentAmounts(total money.Money, count int) []money.Money {
+	base := total.Cents() / int64(count)
+	amounts := make([]money.Money, count)
+	for i := range amounts {
+		amounts[i] = money.FromCents(base)
+	}
+	amounts[0] += money.FromCents(total.Cents() % int64(count))
+	return amounts
+}
+
+// installmentDate returns the date of installment i (0-based): the day of
+// the first installment, i months later, or the last day of shorter months.
+func installmentDate(first time.Time, i int) time.Time {
+	year, month := first.Year(), first.Month()+time.Month(i)
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	day := first.Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// createInstallments stores the series described by params, whose Amount is
+// the total of the purchase.
+func (s *transactionService) createInstallments(ctx context.Context, params db.CreateTransactionParams, count int, tags []string) error {
+	installmentID := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	amounts := installmentAmounts(params.Amount, count)
+
+	err := s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+
+		// The first installment is the earliest one.
+		if err := s.books.WithTx(q).CheckOpen(ctx, params.UserID, params.Date); err != nil {
+			return err
+		}
+
+		for i, amount := range amounts {
+			id, err := repo.CreateInstallment(ctx, db.CreateInstallmentParams{
+				Description:       params.Description,
+				Amount:            amount,
+				Date:              pgtype.Date{Time: installmentDate(params.Date.Time, i), Valid: true},
+				Type:              params.Type,
+				UserID:            params.UserID,
+				AccountID:         params.AccountID,
+				CategoryID:        params.CategoryID,
+				InstallmentID:     installmentID,
+				InstallmentNumber: pgtype.Int2{Int16: int16(i + 1), Valid: true},
+				InstallmentCount:  pgtype.Int2{Int16: int16(count), Valid: true},
+				PayeeID:           params.PayeeID,
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(tags) > 0 {
+				if err := repo.SetTags(ctx, params.UserID, id, tags); err != nil {
+					return err
+				}
+			}
+		}
+
+		return s.accounts.WithTx(q).AdjustBalance(ctx, params.AccountID, signedAmount(params.Type, params.Amount))
+	})
+	if err != nil {
+		return fmt.Errorf("service create installments: %w", err)
+	}
+
+	return nil
+}
+
+func (s *transactionService) GetInstallments(ctx context.Context, userID, id string) (*InstallmentSeriesResponse, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	installmentUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInstallmentNotFound
+	}
+
+	records, err := s.repo.GetInstallments(ctx, installmentUUID, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("service get installments: %w", err)
+	}
+
+	ids := make([]uuid.UUID, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+
+	tags, err := s.repo.GetTags(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("service get installments: %w", err)
+	}
+
+	response := &InstallmentSeriesResponse{
+		ID:           installmentUUID.String(),
+		Description:  records[0].Description,
+		Count:        int(records[0].InstallmentCount.Int16),
+		Installments: make([]*TransactionResponse, len(records)),
+	}
+
+	for i, record := range records {
+		item := TransactionToResponse(record)
+		item.Tags = tags[record.ID]
+		response.Installments[i] = &item
+		response.Amount += record.Amount
+	}
+
+	return response, nil
+}
+
+// UpdateInstallments applies the changes to every installment. A new amount
+// or date is spread over the series again, so each installment keeps its
+// position.
+func (s *transactionService) UpdateInstallments(ctx context.Context, userID, id string, dto InstallmentUpdateRequest) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	installmentUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrInstallmentNotFound
+	}
+
+	var accountUUID, categoryUUID *uuid.UUID
+	if dto.AccountID != nil {
+		parsed := uuid.MustParse(*dto.AccountID)
+		accountUUID = &parsed
+	}
+
+	if dto.CategoryID != nil {
+		parsed := uuid.MustParse(*dto.CategoryID)
+		categoryUUID = &parsed
+	}
+
+	if err := s.checkOwnership(ctx, userUUID, accountUUID, categoryUUID); err != nil {
+		return err
+	}
+
+	err = s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+		accounts := s.accounts.WithTx(q)
+
+		records, err := repo.GetInstallmentsForUpdate(ctx, installmentUUID, userUUID)
+		if err != nil {
+			return err
+		}
+
+		if err := reconciliation.CheckUnlocked(records...); err != nil {
+			return err
+		}
+
+		var total money.Money
+		for _, record := range records {
+			total += record.Amount
+		}
+
+		first := records[0].Date.Time
+		if dto.Amount != nil {
+			total = *dto.Amount
+		}
+
+		if dto.Date != nil {
+			if first, err = time.Parse("2006-01-02", *dto.Date); err != nil {
+				return fmt.Errorf("invalid date format: %w", err)
+			}
+		}
+
+		if total.Cents() < int64(len(records)) {
+			return ErrInstallmentAmount
+		}
+
+		if accountUUID != nil {
+			if err := checkCurrency(ctx, accounts, userUUID, *accountUUID, records[0].Currency); err != nil {
+				return err
+			}
+		}
+
+		// Installments come in order, so checking the first one, before and
+		// after the change, covers the whole series.
+		if err := s.books.WithTx(q).CheckOpen(ctx, userUUID, records[0].Date, pgtype.Date{Time: first, Valid: true}); err != nil {
+			return err
+		}
+
+		amounts := installmentAmounts(total, len(records))
+		deltas := make(map[uuid.UUID]money.Money)
+
+		for i, record := range records {
+			params := db.UpdateTransactionParams{
+				ID:          record.ID,
+				Description: record.Description,
+				Amount:      amounts[i],
+				Date:        pgtype.Date{Time: installmentDate(first, i), Valid: true},
+				Type:        record.Type,
+				AccountID:   record.AccountID,
+				CategoryID:  record.CategoryID,
+				PayeeID:     record.PayeeID,
+				UserID:      userUUID,
+			}
+
+			if dto.Description != nil {
+				params.Description = *dto.Description
+			}
+
+			if accountUUID != nil {
+				params.AccountID = *accountUUID
+			}
+
+			if categoryUUID != nil {
+				params.CategoryID = *categoryUUID
+			}
+
+			if err := repo.Update(ctx, params); err != nil {
+				return err
+			}
+
+			if dto.Tags != nil {
+				if err := repo.SetTags(ctx, userUUID, record.ID, *dto.Tags); err != nil {
+					return err
+				}
+			}
+
+			deltas[record.AccountID] -= signedAmount(record.Type, record.Amount)
+			deltas[params.AccountID] += signedAmount(params.Type, params.Amount)
+		}
+
+		for accountID, delta := range deltas {
+			if err := accounts.AdjustBalance(ctx, accountID, delta); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("service update installments: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteInstallments cancels the purchase, removing every installment.
+func (s *transactionService) DeleteInstallments(ctx context.Context, userID, id string) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	installmentUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrInstallmentNotFound
+	}
+
+	err = s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+
+		records, err := repo.GetInstallmentsForUpdate(ctx, installmentUUID, userUUID)
+		if err != nil {
+			return err
+		}
+
+		if err := reconciliation.CheckUnlocked(records...); err != nil {
+			return err
+		}
+
+		// The first installment is the earliest one.
+		if err := s.books.WithTx(q).CheckOpen(ctx, userUUID, records[0].Date); err != nil {
+			return err
+		}
+
+		if err := repo.DeleteInstallments(ctx, installmentUUID, userUUID); err != nil {
+			return err
+		}
+
+		deltas := make(map[uuid.UUID]money.Money)
+		for _, record := range records {
+			deltas[record.AccountID] -= signedAmount(record.Type, record.Amount)
+		}
+
+		for accountID, delta := range deltas {
+			if err := s.accounts.WithTx(q).AdjustBalance(ctx, accountID, delta); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("service delete installments: %w", err)
+	}
+
+	return nil
+}
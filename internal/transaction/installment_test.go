@@ -0,0 +1,89 @@
+package transaction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func date(s string) time.Time {
+	d, _ := time.Parse("2006-01-02", s)
+	return d
+}
+
+func TestInstallmentAmounts(t *testing.T) {
+	tests := []struct {
+		total money.Money
+		count int
+		want  []money.Money
+	}{
+		{10000, 3, []money.Money{3334, 3333, 3333}},
+		{10000, 4, []money.Money{2500, 2500, 2500, 2500}},
+		{1001, 2, []money.Money{501, 500}},
+		{5, 5, []money.Money{1, 1, 1, 1, 1}},
+		{7, 2, []money.Money{4, 3}},
+		{99999, 12, []money.Money{8336, 8333, 8333, 8333, 8333, 8333, 8333, 8333, 8333, 8333, 8333, 8333}},
+	}
+
+	for _, tt := range tests {
+		got := installmentAmounts(tt.total, tt.count)
+		if len(got) != len(tt.want) {
+			t.Fatalf("installmentAmounts(%s, %d) has %d installments, want %d", tt.total, tt.count, len(got), len(tt.want))
+		}
+
+		var sum money.Money
+		for i := range got {
+			sum += got[i]
+			if got[i] != tt.want[i] {
+				t.Errorf("installmentAmounts(%s, %d)[%d] = %s, want %s", tt.total, tt.count, i, got[i], tt.want[i])
+			}
+		}
+		if sum != tt.total {
+			t.Errorf("installmentAmounts(%s, %d) adds up to %s", tt.total, tt.count, sum)
+		}
+	}
+}
+
+func TestInstallmentDate(t *testing.T) {
+	tests := []struct {
+		name  string
+		first string
+		want  []string
+	}{
+		{"day 31 clamps to shorter months", "2025-01-31", []string{"2025-01-31", "2025-02-28", "2025-03-31", "2025-04-30", "2025-05-31"}},
+		{"leap February", "2024-01-30", []string{"2024-01-30", "2024-02-29", "2024-03-30"}},
+		{"day 29 in a common year", "2025-01-29", []string{"2025-01-29", "2025-02-28", "2025-03-29"}},
+		{"across the year", "2025-11-15", []string{"2025-11-15", "2025-12-15", "2026-01-15", "2026-02-15"}},
+		{"December 31", "2025-12-31", []string{"2025-12-31", "2026-01-31", "2026-02-28"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, want := range tt.want {
+				if got := installmentDate(date(tt.first), i); !got.Equal(date(want)) {
+					t.Errorf("installmentDate(%s, %d) = %s, want %s", tt.first, i, got.Format("2006-01-02"), want)
+				}
+			}
+		})
+	}
+}
+
+func TestInstallmentLabel(t *testing.T) {
+	record := &db.Transaction{
+		InstallmentID:     pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		InstallmentNumber: pgtype.Int2{Int16: 3, Valid: true},
+		InstallmentCount:  pgtype.Int2{Int16: 10, Valid: true},
+	}
+
+	if got := TransactionToResponse(record).Installment; got != "3/10" {
+		t.Errorf("Installment = %q, want %q", got, "3/10")
+	}
+
+	if got := TransactionToResponse(&db.Transaction{}).Installment; got != "" {
+		t.Errorf("Installment of a single transaction = %q, want empty", got)
+	}
+}
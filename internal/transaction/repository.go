@@ -8,16 +8,33 @@ import (
 
 	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Repository interface {
-	Create(ctx context.Context, args db.CreateTransactionParams) error
-	GetTransaction(ctx context.Context, id uuid.UUID) (*db.Transaction, error)
-	GetAllTransaction(ctx context.Context, userID uuid.UUID) ([]*db.Transaction, error)
-	GetAllTransasctionsByAccount(ctx context.Context, accountID uuid.UUID) ([]*db.Transaction, error)
-	GetAllTransasctionsByCategory(ctx context.Context, categoryID uuid.UUID) ([]*db.Transaction, error)
+	Create(ctx context.Context, args db.CreateTransactionParams) (uuid.UUID, error)
+	CreateImported(ctx context.Context, args db.CreateImportedTransactionParams) (uuid.UUID, bool, error)
+	GetTransaction(ctx context.Context, id, userID uuid.UUID) (*db.Transaction, error)
+	GetTransactionForUpdate(ctx context.Context, id, userID uuid.UUID) (*db.Transaction, error)
+	List(ctx context.Context, args db.ListTransactionsParams) ([]*db.Transaction, error)
+	ListRuleCandidates(ctx context.Context, args db.ListRuleCandidatesParams) ([]*db.Transaction, error)
 	Update(ctx context.Context, args db.UpdateTransactionParams) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+	CreateInstallment(ctx context.Context, args db.CreateInstallmentParams) (uuid.UUID, error)
+	GetInstallments(ctx context.Context, installmentID, userID uuid.UUID) ([]*db.Transaction, error)
+	GetInstallmentsForUpdate(ctx context.Context, installmentID, userID uuid.UUID) ([]*db.Transaction, error)
+	DeleteInstallments(ctx context.Context, installmentID, userID uuid.UUID) error
+	GetSplits(ctx context.Context, transactionID uuid.UUID) ([]*db.TransactionSplit, error)
+	ReplaceSplits(ctx context.Context, transactionID uuid.UUID, splits []db.CreateTransactionSplitParams) error
+	GetSplitIDs(ctx context.Context, transactionIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+	GetTags(ctx context.Context, transactionIDs []uuid.UUID) (map[uuid.UUID][]string, error)
+	SetTags(ctx context.Context, userID, transactionID uuid.UUID, names []string) error
+	SetClearingStatus(ctx context.Context, id, userID uuid.UUID, status db.ClearingStatus) error
+	Unlock(ctx context.Context, id, userID uuid.UUID) error
+	GetClassifierCategories(ctx context.Context, args db.GetClassifierCategoriesParams) ([]*db.GetClassifierCategoriesRow, error)
+	GetClassifierTokens(ctx context.Context, args db.GetClassifierTokensParams) ([]*db.GetClassifierTokensRow, error)
+	CountClassifierVocabulary(ctx context.Context, userID uuid.UUID) (int64, error)
+	WithTx(q *db.Queries) Repository
 }
 
 type transactionRepository struct {
@@ -31,30 +48,45 @@ func NewTransactionRepo(db *db.Queries) Repository {
 }
 
 var ErrTransactionNotFound = errors.New("transaction not found")
+var ErrInvalidAccount = errors.New("account not found")
+var ErrInvalidCategory = errors.New("category not found")
+var ErrTransferLegField = errors.New("type, category and payee cannot be changed on a transfer")
+var ErrInvalidSplitCategory = errors.New("split category not found")
+var ErrSplitTotal = errors.New("the split amounts must add up to the transaction amount")
+var ErrSplitCategory = errors.New("category_id cannot be set on a split transaction, edit its splits instead")
+var ErrInstallmentNotFound = errors.New("installment series not found")
+var ErrInstallmentAmount = errors.New("the amount must be at least one cent per installment")
+var ErrCurrencyMismatch = errors.New("currency must match the account currency")
+var ErrInvalidPayee = errors.New("payee not found")
+var ErrCategoryRequired = errors.New("category_id is required when the payee has no default category")
+var ErrNotReconciled = errors.New("transaction is not reconciled")
+var ErrInstallmentField = errors.New("type, amount, date, account and splits of an installment are edited on its series")
 
-func (r *transactionRepository) Create(ctx context.Context, args db.CreateTransactionParams) error {
-	if err := r.db.CreateTransaction(ctx, args); err != nil {
-		return fmt.Errorf("repository create: %w", err)
+func (r *transactionRepository) Create(ctx context.Context, args db.CreateTransactionParams) (uuid.UUID, error) {
+	id, err := r.db.CreateTransaction(ctx, args)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("repository create: %w", err)
 	}
 
-	return nil
+	return id, nil
 }
 
-func (r *transactionRepository) GetTransaction(ctx context.Context, id uuid.UUID) (*db.Transaction, error) {
-	record, err := r.db.GetTrasaction(ctx, id)
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, ErrTransactionNotFound
-	}
-
+// CreateImported reports false when a transaction with the same import hash
+// already exists in the account.
+func (r *transactionRepository) CreateImported(ctx context.Context, args db.CreateImportedTransactionParams) (uuid.UUID, bool, error) {
+	id, err := r.db.CreateImportedTransaction(ctx, args)
 	if err != nil {
-		return nil, fmt.Errorf("repository getTransaction: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, false, nil
+		}
+		return uuid.Nil, false, fmt.Errorf("repository createImported: %w", err)
 	}
 
-	return record, nil
+	return id, true, nil
 }
 
-func (r *transactionRepository) GetAllTransaction(ctx context.Context, userID uuid.UUID) ([]*db.Transaction, error) {
-	records, err := r.db.GetAllTransactions(ctx, userID)
+func (r *transactionRepository) GetTransaction(ctx context.Context, id, userID uuid.UUID) (*db.Transaction, error) {
+	record, err := r.db.GetTrasaction(ctx, db.GetTrasactionParams{ID: id, UserID: userID})
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrTransactionNotFound
 	}
@@ -63,37 +95,42 @@ func (r *transactionRepository) GetAllTransaction(ctx context.Context, userID uu
 		return nil, fmt.Errorf("repository getTransaction: %w", err)
 	}
 
-	return records, nil
+	return record, nil
 }
 
-func (r *transactionRepository) GetAllTransasctionsByAccount(ctx context.Context, accountID uuid.UUID) ([]*db.Transaction, error) {
-	records, err := r.db.GetAllTransactionsByAccount(ctx, accountID)
+func (r *transactionRepository) GetTransactionForUpdate(ctx context.Context, id, userID uuid.UUID) (*db.Transaction, error) {
+	record, err := r.db.GetTransactionForUpdate(ctx, db.GetTransactionForUpdateParams{ID: id, UserID: userID})
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrTransactionNotFound
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("repository getTransaction: %w", err)
+		return nil, fmt.Errorf("repository getTransactionForUpdate: %w", err)
 	}
 
-	return records, nil
+	return record, nil
 }
 
-func (r *transactionRepository) GetAllTransasctionsByCategory(ctx context.Context, categoryID uuid.UUID) ([]*db.Transaction, error) {
-	records, err := r.db.GetAllTransactionsByCategory(ctx, categoryID)
-	if errors.Is(err, sql.ErrNoRows) {
-		return nil, ErrTransactionNotFound
+func (r *transactionRepository) List(ctx context.Context, args db.ListTransactionsParams) ([]*db.Transaction, error) {
+	records, err := r.db.ListTransactions(ctx, args)
+	if err != nil {
+		return nil, fmt.Errorf("repository list: %w", err)
 	}
 
+	return records, nil
+}
+
+func (r *transactionRepository) ListRuleCandidates(ctx context.Context, args db.ListRuleCandidatesParams) ([]*db.Transaction, error) {
+	records, err := r.db.ListRuleCandidates(ctx, args)
 	if err != nil {
-		return nil, fmt.Errorf("repository getTransaction: %w", err)
+		return nil, fmt.Errorf("repository listRuleCandidates: %w", err)
 	}
 
 	return records, nil
 }
 
 func (r *transactionRepository) Update(ctx context.Context, args db.UpdateTransactionParams) error {
-	_, err := r.GetTransaction(ctx, args.ID)
+	_, err := r.GetTransaction(ctx, args.ID, args.UserID)
 	if err != nil {
 		return err
 	}
@@ -105,15 +142,220 @@ func (r *transactionRepository) Update(ctx context.Context, args db.UpdateTransa
 	return nil
 }
 
-func (r *transactionRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	_, err := r.GetTransaction(ctx, id)
+func (r *transactionRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	_, err := r.GetTransaction(ctx, id, userID)
 	if err != nil {
 		return err
 	}
 
-	if err := r.db.DeleteTransaction(ctx, id); err != nil {
+	if err := r.db.DeleteTransaction(ctx, db.DeleteTransactionParams{ID: id, UserID: userID}); err != nil {
 		return fmt.Errorf("repository delete: %w", err)
 	}
 
 	return nil
 }
+
+func (r *transactionRepository) CreateInstallment(ctx context.Context, args db.CreateInstallmentParams) (uuid.UUID, error) {
+	id, err := r.db.CreateInstallment(ctx, args)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("repository createInstallment: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetInstallments returns the installments of a series in order.
+func (r *transactionRepository) GetInstallments(ctx context.Context, installmentID, userID uuid.UUID) ([]*db.Transaction, error) {
+	records, err := r.db.GetInstallments(ctx, db.GetInstallmentsParams{
+		InstallmentID: pgtype.UUID{Bytes: installmentID, Valid: true},
+		UserID:        userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repository getInstallments: %w", err)
+	}
+
+	if len(records) == 0 {
+		return nil, ErrInstallmentNotFound
+	}
+
+	return records, nil
+}
+
+func (r *transactionRepository) GetInstallmentsForUpdate(ctx context.Context, installmentID, userID uuid.UUID) ([]*db.Transaction, error) {
+	records, err := r.db.GetInstallmentsForUpdate(ctx, db.GetInstallmentsForUpdateParams{
+		InstallmentID: pgtype.UUID{Bytes: installmentID, Valid: true},
+		UserID:        userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repository getInstallmentsForUpdate: %w", err)
+	}
+
+	if len(records) == 0 {
+		return nil, ErrInstallmentNotFound
+	}
+
+	return records, nil
+}
+
+func (r *transactionRepository) DeleteInstallments(ctx context.Context, installmentID, userID uuid.UUID) error {
+	err := r.db.DeleteInstallments(ctx, db.DeleteInstallmentsParams{
+		InstallmentID: pgtype.UUID{Bytes: installmentID, Valid: true},
+		UserID:        userID,
+	})
+	if err != nil {
+		return fmt.Errorf("repository deleteInstallments: %w", err)
+	}
+
+	return nil
+}
+
+func (r *transactionRepository) GetSplits(ctx context.Context, transactionID uuid.UUID) ([]*db.TransactionSplit, error) {
+	records, err := r.db.GetTransactionSplits(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("repository getSplits: %w", err)
+	}
+
+	return records, nil
+}
+
+// ReplaceSplits swaps every split of the transaction for the given ones, in
+// order. An empty list turns it back into a single-category transaction.
+func (r *transactionRepository) ReplaceSplits(ctx context.Context, transactionID uuid.UUID, splits []db.CreateTransactionSplitParams) error {
+	if err := r.db.DeleteTransactionSplits(ctx, transactionID); err != nil {
+		return fmt.Errorf("repository replaceSplits: %w", err)
+	}
+
+	for i, split := range splits {
+		split.TransactionID = transactionID
+		split.Position = int32(i)
+
+		if err := r.db.CreateTransactionSplit(ctx, split); err != nil {
+			return fmt.Errorf("repository replaceSplits: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetSplitIDs reports which of the transactions are split.
+func (r *transactionRepository) GetSplitIDs(ctx context.Context, transactionIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	split := make(map[uuid.UUID]bool)
+	if len(transactionIDs) == 0 {
+		return split, nil
+	}
+
+	ids, err := r.db.GetSplitTransactionIDs(ctx, transactionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("repository getSplitIDs: %w", err)
+	}
+
+	for _, id := range ids {
+		split[id] = true
+	}
+
+	return split, nil
+}
+
+// GetTags returns the tag names of each transaction, sorted by name.
+// Transactions without tags are absent from the map.
+func (r *transactionRepository) GetTags(ctx context.Context, transactionIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	tags := make(map[uuid.UUID][]string)
+	if len(transactionIDs) == 0 {
+		return tags, nil
+	}
+
+	records, err := r.db.GetTransactionsTags(ctx, transactionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("repository getTags: %w", err)
+	}
+
+	for _, record := range records {
+		tags[record.TransactionID] = append(tags[record.TransactionID], record.Name)
+	}
+
+	return tags, nil
+}
+
+// SetTags replaces the tags of the transaction. Names the user does not have
+// yet are created; names must already be normalized.
+func (r *transactionRepository) SetTags(ctx context.Context, userID, transactionID uuid.UUID, names []string) error {
+	if err := r.db.DeleteTransactionTags(ctx, transactionID); err != nil {
+		return fmt.Errorf("repository setTags: %w", err)
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	ids, err := r.db.UpsertTags(ctx, db.UpsertTagsParams{UserID: userID, Names: names})
+	if err != nil {
+		return fmt.Errorf("repository setTags: %w", err)
+	}
+
+	err = r.db.AddTransactionTags(ctx, db.AddTransactionTagsParams{TransactionID: transactionID, TagIds: ids})
+	if err != nil {
+		return fmt.Errorf("repository setTags: %w", err)
+	}
+
+	return nil
+}
+
+func (r *transactionRepository) SetClearingStatus(ctx context.Context, id, userID uuid.UUID, status db.ClearingStatus) error {
+	err := r.db.SetTransactionClearingStatus(ctx, db.SetTransactionClearingStatusParams{
+		ID:             id,
+		UserID:         userID,
+		ClearingStatus: status,
+	})
+	if err != nil {
+		return fmt.Errorf("repository setClearingStatus: %w", err)
+	}
+
+	return nil
+}
+
+// Unlock fails with ErrNotReconciled when the transaction is not locked.
+func (r *transactionRepository) Unlock(ctx context.Context, id, userID uuid.UUID) error {
+	rows, err := r.db.UnlockTransaction(ctx, db.UnlockTransactionParams{ID: id, UserID: userID})
+	if err != nil {
+		return fmt.Errorf("repository unlock: %w", err)
+	}
+
+	if rows == 0 {
+		return ErrNotReconciled
+	}
+
+	return nil
+}
+
+func (r *transactionRepository) GetClassifierCategories(ctx context.Context, args db.GetClassifierCategoriesParams) ([]*db.GetClassifierCategoriesRow, error) {
+	records, err := r.db.GetClassifierCategories(ctx, args)
+	if err != nil {
+		return nil, fmt.Errorf("repository getClassifierCategories: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *transactionRepository) GetClassifierTokens(ctx context.Context, args db.GetClassifierTokensParams) ([]*db.GetClassifierTokensRow, error) {
+	records, err := r.db.GetClassifierTokens(ctx, args)
+	if err != nil {
+		return nil, fmt.Errorf("repository getClassifierTokens: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *transactionRepository) CountClassifierVocabulary(ctx context.Context, userID uuid.UUID) (int64, error) {
+	count, err := r.db.CountClassifierVocabulary(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("repository countClassifierVocabulary: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *transactionRepository) WithTx(q *db.Queries) Repository {
+	return &transactionRepository{
+		db: q,
+	}
+}
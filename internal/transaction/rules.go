@@ -0,0 +1,209 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/category"
+	"github.com/EduardoMark/my-finance-api/internal/rule"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxRuleRun caps how many transactions one run of the rules goes through.
+const maxRuleRun = 5000
+
+func (s *transactionService) ruleSet(ctx context.Context, userID uuid.UUID) (*rule.Set, error) {
+	records, err := s.rules.GetEnabled(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service load rules: %w", err)
+	}
+
+	categories, err := s.categories.GetAllCategoriesByUserId(ctx, userID)
+	if err != nil && !errors.Is(err, category.ErrCategoriesNotFound) {
+		return nil, fmt.Errorf("service load rules: %w", err)
+	}
+
+	return rule.NewSet(records, categories), nil
+}
+
+// applyRules fills a new transaction with the actions of the matching rules.
+// They only fill what the request leaves empty, except the category of
+// imports, which is a catch-all for the whole file that rules refine. A
+// rewritten description and tags always apply.
+func applyRules(rules *rule.Set, accountID uuid.UUID, dto *TransactionCreateRequest, imported bool) {
+	outcome := rules.Apply(rule.Input{
+		Description: dto.Description,
+		Amount:      dto.Amount,
+		AccountID:   accountID,
+		Type:        db.TransactionType(dto.Type),
+	})
+	if !outcome.Matched() {
+		return
+	}
+
+	if outcome.Description != nil {
+		dto.Description = *outcome.Description
+	}
+
+	if outcome.CategoryID != nil && len(dto.Splits) == 0 && (dto.CategoryID == "" || imported) {
+		dto.CategoryID = outcome.CategoryID.String()
+	}
+
+	if outcome.PayeeID != nil && dto.PayeeID == "" {
+		dto.PayeeID = outcome.PayeeID.String()
+	}
+
+	for _, name := range outcome.Tags {
+		if !slices.Contains(dto.Tags, name) {
+			dto.Tags = append(dto.Tags, name)
+		}
+	}
+}
+
+// RunRules runs the enabled rules again on existing income and expenses.
+// Unlike on create, their actions replace the current values. Each changed
+// transaction goes through UpdateTransaction; split transactions keep their
+// categories. The request must already have passed Valid.
+func (s *transactionService) RunRules(ctx context.Context, userID string, req *rule.RunReq) (*rule.RunRes, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	rules, err := s.ruleSet(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := db.ListRuleCandidatesParams{UserID: userUUID, RowLimit: maxRuleRun + 1}
+
+	if req.AccountID != nil {
+		params.AccountID = pgtype.UUID{Bytes: uuid.MustParse(*req.AccountID), Valid: true}
+	}
+
+	if req.StartDate != nil {
+		date, _ := time.Parse("2006-01-02", *req.StartDate)
+		params.StartDate = pgtype.Date{Time: date, Valid: true}
+	}
+
+	if req.EndDate != nil {
+		date, _ := time.Parse("2006-01-02", *req.EndDate)
+		params.EndDate = pgtype.Date{Time: date, Valid: true}
+	}
+
+	transactions, err := s.repo.ListRuleCandidates(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("service run rules: %w", err)
+	}
+
+	if len(transactions) > maxRuleRun {
+		return nil, rule.ErrTooManyTransactions
+	}
+
+	ids := make([]uuid.UUID, len(transactions))
+	for i, transaction := range transactions {
+		ids[i] = transaction.ID
+	}
+
+	tags, err := s.repo.GetTags(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("service run rules: %w", err)
+	}
+
+	split, err := s.repo.GetSplitIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("service run rules: %w", err)
+	}
+
+	res := &rule.RunRes{DryRun: req.DryRun, Checked: len(transactions), Changes: []rule.ChangeRes{}}
+
+	for _, transaction := range transactions {
+		outcome := rules.Apply(rule.Input{
+			Description: transaction.Description,
+			Amount:      transaction.Amount,
+			AccountID:   transaction.AccountID,
+			Type:        transaction.Type,
+		})
+		if !outcome.Matched() {
+			continue
+		}
+
+		change, update := ruleChange(transaction, outcome, tags[transaction.ID], split[transaction.ID])
+		if update == nil {
+			continue
+		}
+
+		if !req.DryRun {
+			if err := s.UpdateTransaction(ctx, userID, transaction.ID.String(), *update); err != nil {
+				return nil, fmt.Errorf("service run rules on transaction %s: %w", transaction.ID, err)
+			}
+		}
+
+		res.Changes = append(res.Changes, change)
+	}
+
+	res.Changed = len(res.Changes)
+	return res, nil
+}
+
+// ruleChange compares a transaction with the outcome of the rules. It
+// returns a nil update when nothing would change.
+func ruleChange(t *db.Transaction, outcome rule.Outcome, tags []string, split bool) (rule.ChangeRes, *TransactionUpdateRequest) {
+	change := rule.ChangeRes{
+		TransactionID: t.ID.String(),
+		Date:          t.Date.Time.Format("2006-01-02"),
+		RuleIDs:       make([]string, len(outcome.RuleIDs)),
+	}
+	for i, id := range outcome.RuleIDs {
+		change.RuleIDs[i] = id.String()
+	}
+
+	var update TransactionUpdateRequest
+	changed := false
+
+	if outcome.Description != nil && *outcome.Description != t.Description {
+		change.Description = &rule.DiffRes{From: t.Description, To: *outcome.Description}
+		update.Description = outcome.Description
+		changed = true
+	}
+
+	if outcome.CategoryID != nil && *outcome.CategoryID != t.CategoryID && !split {
+		to := outcome.CategoryID.String()
+		change.CategoryID = &rule.DiffRes{From: t.CategoryID.String(), To: to}
+		update.CategoryID = &to
+		changed = true
+	}
+
+	if outcome.PayeeID != nil && (!t.PayeeID.Valid || *outcome.PayeeID != uuid.UUID(t.PayeeID.Bytes)) {
+		to := outcome.PayeeID.String()
+		change.PayeeID = &rule.DiffRes{To: to}
+		if t.PayeeID.Valid {
+			change.PayeeID.From = uuid.UUID(t.PayeeID.Bytes).String()
+		}
+		update.PayeeID = &to
+		changed = true
+	}
+
+	for _, name := range outcome.Tags {
+		if !slices.Contains(tags, name) {
+			change.AddedTags = append(change.AddedTags, name)
+		}
+	}
+
+	if len(change.AddedTags) > 0 {
+		all := append(slices.Clone(tags), change.AddedTags...)
+		update.Tags = &all
+		changed = true
+	}
+
+	if !changed {
+		return change, nil
+	}
+
+	return change, &update
+}
@@ -2,29 +2,61 @@ package transaction
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/EduardoMark/my-finance-api/internal/account"
+	"github.com/EduardoMark/my-finance-api/internal/books"
+	"github.com/EduardoMark/my-finance-api/internal/category"
+	"github.com/EduardoMark/my-finance-api/internal/payee"
+	"github.com/EduardoMark/my-finance-api/internal/reconciliation"
+	"github.com/EduardoMark/my-finance-api/internal/rule"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore"
 	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/transfer"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Service interface {
 	Create(ctx context.Context, userID string, dto TransactionCreateRequest) error
-	GetTransaction(ctx context.Context, id string) (*TransactionResponse, error)
-	GetAllTransactions(ctx context.Context, userID string, filters *TransactionFilters) ([]*TransactionResponse, error)
-	UpdateTransaction(ctx context.Context, id string, dto TransactionUpdateRequest) error
-	DeleteTransaction(ctx context.Context, id string) error
+	CreateBatch(ctx context.Context, userID string, dtos []TransactionCreateRequest) ([]bool, error)
+	GetTransaction(ctx context.Context, userID, id string) (*TransactionResponse, error)
+	GetAllTransactions(ctx context.Context, userID string, filters *TransactionFilters) (*TransactionListResponse, error)
+	UpdateTransaction(ctx context.Context, userID, id string, dto TransactionUpdateRequest) error
+	DeleteTransaction(ctx context.Context, userID, id string) error
+	Unlock(ctx context.Context, userID, id string) error
+	GetInstallments(ctx context.Context, userID, id string) (*InstallmentSeriesResponse, error)
+	UpdateInstallments(ctx context.Context, userID, id string, dto InstallmentUpdateRequest) error
+	DeleteInstallments(ctx context.Context, userID, id string) error
+	RunRules(ctx context.Context, userID string, req *rule.RunReq) (*rule.RunRes, error)
+	SuggestCategory(ctx context.Context, userID string, filters *SuggestCategoryFilters) (*CategorySuggestionResponse, error)
 }
 
 type transactionService struct {
-	repo Repository
+	repo       Repository
+	accounts   account.Repository
+	categories category.Repository
+	payees     payee.Repository
+	rules      rule.Repository
+	books      books.Repository
+	transfers  transfer.Service
+	uow        pgstore.UnitOfWork
 }
 
-func NewTransactionService(repo Repository) Service {
+func NewTransactionService(repo Repository, accounts account.Repository, categories category.Repository, payees payee.Repository, rules rule.Repository, books books.Repository, transfers transfer.Service, uow pgstore.UnitOfWork) Service {
 	return &transactionService{
-		repo: repo,
+		repo:       repo,
+		accounts:   accounts,
+		categories: categories,
+		payees:     payees,
+		rules:      rules,
+		books:      books,
+		transfers:  transfers,
+		uow:        uow,
 	}
 }
 
@@ -39,14 +71,52 @@ func (s *transactionService) Create(ctx context.Context, userID string, dto Tran
 		return fmt.Errorf("invalid account ID: %w", err)
 	}
 
-	categoryUUID, err := uuid.Parse(dto.CategoryID)
+	date, err := time.Parse("2006-01-02", dto.Date)
 	if err != nil {
-		return fmt.Errorf("invalid category ID: %w", err)
+		return fmt.Errorf("invalid date format: %w", err)
 	}
 
-	date, err := time.Parse("2006-01-02", dto.Date)
+	rules, err := s.ruleSet(ctx, userUUID)
 	if err != nil {
-		return fmt.Errorf("invalid date format: %w", err)
+		return err
+	}
+
+	// Payee aliases are matched against the description as sent, before a
+	// rule rewrites it.
+	description := dto.Description
+	applyRules(rules, accountUUID, &dto, false)
+
+	splits, err := s.splitParams(ctx, userUUID, dto.Splits)
+	if err != nil {
+		return err
+	}
+
+	payeeRecord, err := s.resolvePayee(ctx, userUUID, dto.PayeeID, description)
+	if err != nil {
+		return err
+	}
+
+	// A split transaction keeps the category of its first line.
+	var categoryUUID uuid.UUID
+	if len(splits) > 0 {
+		categoryUUID = splits[0].CategoryID
+	} else if categoryUUID, err = categoryFor(dto.CategoryID, payeeRecord); err != nil {
+		return err
+	}
+
+	if err := s.checkOwnership(ctx, userUUID, &accountUUID, &categoryUUID); err != nil {
+		return err
+	}
+
+	if dto.Currency != "" {
+		record, err := s.accounts.GetAccount(ctx, accountUUID, userUUID)
+		if err != nil {
+			return fmt.Errorf("service check currency: %w", err)
+		}
+
+		if record.Currency != dto.Currency {
+			return ErrCurrencyMismatch
+		}
 	}
 
 	params := db.CreateTransactionParams{
@@ -57,170 +127,822 @@ func (s *transactionService) Create(ctx context.Context, userID string, dto Tran
 		AccountID:   accountUUID,
 		CategoryID:  categoryUUID,
 		UserID:      userUUID,
+		PayeeID:     payeeID(payeeRecord),
 	}
 
-	if err := s.repo.Create(ctx, params); err != nil {
+	if dto.Installments > 1 {
+		return s.createInstallments(ctx, params, dto.Installments, dto.Tags)
+	}
+
+	err = s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+
+		if err := s.books.WithTx(q).CheckOpen(ctx, userUUID, params.Date); err != nil {
+			return err
+		}
+
+		id, err := repo.Create(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		if len(splits) > 0 {
+			if err := repo.ReplaceSplits(ctx, id, splits); err != nil {
+				return err
+			}
+		}
+
+		if len(dto.Tags) > 0 {
+			if err := repo.SetTags(ctx, userUUID, id, dto.Tags); err != nil {
+				return err
+			}
+		}
+
+		return s.accounts.WithTx(q).AdjustBalance(ctx, params.AccountID, signedAmount(params.Type, params.Amount))
+	})
+	if err != nil {
 		return fmt.Errorf("service create transaction: %w", err)
 	}
 
 	return nil
 }
 
-func (s *transactionService) GetTransaction(ctx context.Context, id string) (*TransactionResponse, error) {
+// CreateBatch creates all transactions in a single database transaction and
+// reports which ones were inserted: a request whose ImportHash already exists
+// in its account is skipped. The requests must already have passed Valid.
+func (s *transactionService) CreateBatch(ctx context.Context, userID string, dtos []TransactionCreateRequest) ([]bool, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	rules, err := s.ruleSet(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]db.CreateImportedTransactionParams, len(dtos))
+	tags := make([][]string, len(dtos))
+	checked := make(map[uuid.UUID]bool)
+
+	for i, dto := range dtos {
+		accountUUID, err := uuid.Parse(dto.AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid account ID: %w", err)
+		}
+
+		description := dto.Description
+		applyRules(rules, accountUUID, &dto, true)
+
+		payeeRecord, err := s.resolvePayee(ctx, userUUID, dto.PayeeID, description)
+		if err != nil {
+			return nil, err
+		}
+
+		categoryUUID, err := categoryFor(dto.CategoryID, payeeRecord)
+		if err != nil {
+			return nil, err
+		}
+
+		date, err := time.Parse("2006-01-02", dto.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format: %w", err)
+		}
+
+		if !checked[accountUUID] || !checked[categoryUUID] {
+			if err := s.checkOwnership(ctx, userUUID, &accountUUID, &categoryUUID); err != nil {
+				return nil, err
+			}
+			checked[accountUUID], checked[categoryUUID] = true, true
+		}
+
+		params[i] = db.CreateImportedTransactionParams{
+			Description: dto.Description,
+			Amount:      dto.Amount,
+			Date:        pgtype.Date{Time: date, Valid: true},
+			Type:        db.TransactionType(dto.Type),
+			AccountID:   accountUUID,
+			CategoryID:  categoryUUID,
+			UserID:      userUUID,
+			ImportHash:  pgtype.Text{String: dto.ImportHash, Valid: dto.ImportHash != ""},
+			PayeeID:     payeeID(payeeRecord),
+		}
+		tags[i] = dto.Tags
+	}
+
+	inserted := make([]bool, len(params))
+
+	err = s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+		deltas := make(map[uuid.UUID]money.Money)
+		var dates []pgtype.Date
+
+		for i, p := range params {
+			id, ok, err := repo.CreateImported(ctx, p)
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				continue
+			}
+
+			if len(tags[i]) > 0 {
+				if err := repo.SetTags(ctx, userUUID, id, tags[i]); err != nil {
+					return err
+				}
+			}
+
+			deltas[p.AccountID] += signedAmount(p.Type, p.Amount)
+			dates = append(dates, p.Date)
+			inserted[i] = true
+		}
+
+		// Only what was inserted counts: lines already imported before the
+		// books were closed are skipped as duplicates.
+		if err := s.books.WithTx(q).CheckOpen(ctx, userUUID, dates...); err != nil {
+			return err
+		}
+
+		for accountID, delta := range deltas {
+			if err := s.accounts.WithTx(q).AdjustBalance(ctx, accountID, delta); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service create batch: %w", err)
+	}
+
+	return inserted, nil
+}
+
+func (s *transactionService) GetTransaction(ctx context.Context, userID, id string) (*TransactionResponse, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
 	transactionUUID, err := uuid.Parse(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid transaction ID: %w", err)
+		return nil, ErrTransactionNotFound
 	}
 
-	transaction, err := s.repo.GetTransaction(ctx, transactionUUID)
+	transaction, err := s.repo.GetTransaction(ctx, transactionUUID, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("service get transaction: %w", err)
+	}
+
+	splits, err := s.repo.GetSplits(ctx, transactionUUID)
+	if err != nil {
+		return nil, fmt.Errorf("service get transaction: %w", err)
+	}
+
+	tags, err := s.repo.GetTags(ctx, []uuid.UUID{transactionUUID})
 	if err != nil {
 		return nil, fmt.Errorf("service get transaction: %w", err)
 	}
 
 	response := TransactionToResponse(transaction)
+	response.Splits = SplitsToResponse(splits)
+	response.Tags = tags[transactionUUID]
 	return &response, nil
 }
 
-func (s *transactionService) GetAllTransactions(ctx context.Context, userID string, filters *TransactionFilters) ([]*TransactionResponse, error) {
+// GetAllTransactions expects filters that already passed Valid.
+func (s *transactionService) GetAllTransactions(ctx context.Context, userID string, filters *TransactionFilters) (*TransactionListResponse, error) {
 	userUUID, err := uuid.Parse(userID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid user ID: %w", err)
 	}
 
-	var transactions []*db.Transaction
+	if filters == nil {
+		filters = &TransactionFilters{}
+	}
+
+	params, err := listParams(userUUID, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.repo.List(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("service get all transactions: %w", err)
+	}
+
+	response := &TransactionListResponse{Data: []*TransactionResponse{}}
+
+	// One extra row is fetched to know whether another page exists.
+	pageSize := filters.pageSize()
+	if len(transactions) > pageSize {
+		transactions = transactions[:pageSize]
+		next := newCursor(filters.sort(), transactions[pageSize-1]).encode()
+		response.NextCursor = &next
+	}
+
+	ids := make([]uuid.UUID, len(transactions))
+	for i, transaction := range transactions {
+		ids[i] = transaction.ID
+	}
+
+	tags, err := s.repo.GetTags(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("service get all transactions: %w", err)
+	}
+
+	for _, transaction := range transactions {
+		item := TransactionToResponse(transaction)
+		item.Tags = tags[transaction.ID]
+		response.Data = append(response.Data, &item)
+	}
+
+	return response, nil
+}
+
+func listParams(userID uuid.UUID, filters *TransactionFilters) (db.ListTransactionsParams, error) {
+	params := db.ListTransactionsParams{
+		UserID:   userID,
+		Sort:     filters.sort(),
+		PageSize: int32(filters.pageSize() + 1),
+	}
+
+	if filters.AccountID != nil {
+		params.AccountID = pgtype.UUID{Bytes: uuid.MustParse(*filters.AccountID), Valid: true}
+	}
+
+	if filters.CategoryID != nil {
+		params.CategoryID = pgtype.UUID{Bytes: uuid.MustParse(*filters.CategoryID), Valid: true}
+	}
+
+	if filters.Type != nil {
+		params.Type = db.NullTransactionType{TransactionType: db.TransactionType(*filters.Type), Valid: true}
+	}
 
-	if filters != nil && filters.AccountID != nil {
-		accountUUID, parseErr := uuid.Parse(*filters.AccountID)
-		if parseErr != nil {
-			return nil, fmt.Errorf("invalid account ID: %w", parseErr)
+	if filters.StartDate != nil {
+		date, err := time.Parse("2006-01-02", *filters.StartDate)
+		if err != nil {
+			return params, fmt.Errorf("invalid start date: %w", err)
 		}
-		transactions, err = s.repo.GetAllTransasctionsByAccount(ctx, accountUUID)
+		params.StartDate = pgtype.Date{Time: date, Valid: true}
+	}
+
+	if filters.EndDate != nil {
+		date, err := time.Parse("2006-01-02", *filters.EndDate)
 		if err != nil {
-			return nil, fmt.Errorf("service get all transactions: %w", err)
+			return params, fmt.Errorf("invalid end date: %w", err)
 		}
-		return s.processTransactions(transactions, filters), nil
+		params.EndDate = pgtype.Date{Time: date, Valid: true}
 	}
 
-	if filters != nil && filters.CategoryID != nil {
-		categoryUUID, parseErr := uuid.Parse(*filters.CategoryID)
-		if parseErr != nil {
-			return nil, fmt.Errorf("invalid category ID: %w", parseErr)
+	if filters.MinAmount != nil {
+		amount, err := money.Parse(*filters.MinAmount)
+		if err != nil {
+			return params, fmt.Errorf("invalid min amount: %w", err)
 		}
-		transactions, err = s.repo.GetAllTransasctionsByCategory(ctx, categoryUUID)
+		params.MinAmount = pgtype.Int8{Int64: amount.Cents(), Valid: true}
+	}
+
+	if filters.MaxAmount != nil {
+		amount, err := money.Parse(*filters.MaxAmount)
 		if err != nil {
-			return nil, fmt.Errorf("service get all transactions: %w", err)
+			return params, fmt.Errorf("invalid max amount: %w", err)
 		}
-		return s.processTransactions(transactions, filters), nil
+		params.MaxAmount = pgtype.Int8{Int64: amount.Cents(), Valid: true}
 	}
 
-	transactions, err = s.repo.GetAllTransaction(ctx, userUUID)
+	if filters.Search != nil {
+		params.Search = pgtype.Text{String: *filters.Search, Valid: true}
+	}
 
-	if err != nil {
-		return nil, fmt.Errorf("service get all transactions: %w", err)
+	if filters.PayeeID != nil {
+		params.PayeeID = pgtype.UUID{Bytes: uuid.MustParse(*filters.PayeeID), Valid: true}
 	}
 
-	return s.processTransactions(transactions, filters), nil
-}
+	if filters.AllTags != nil {
+		params.AllTags = tagList(*filters.AllTags)
+	}
+
+	if filters.AnyTags != nil {
+		params.AnyTags = tagList(*filters.AnyTags)
+	}
+
+	if filters.NoTags != nil {
+		params.NoTags = tagList(*filters.NoTags)
+	}
+
+	if filters.Cursor != nil {
+		c, err := decodeCursor(*filters.Cursor)
+		if err != nil {
+			return params, err
+		}
 
-func (s *transactionService) processTransactions(transactions []*db.Transaction, filters *TransactionFilters) []*TransactionResponse {
-	filteredTransactions := s.applyFilters(transactions, filters)
+		params.CursorID = pgtype.UUID{Bytes: c.ID, Valid: true}
 
-	var responses []*TransactionResponse
-	for _, transaction := range filteredTransactions {
-		response := TransactionToResponse(transaction)
-		responses = append(responses, &response)
+		switch c.Sort {
+		case "amount_asc", "amount_desc":
+			cents, _ := strconv.ParseInt(c.Value, 10, 64)
+			params.CursorAmount = pgtype.Int8{Int64: cents, Valid: true}
+		default:
+			date, _ := time.Parse("2006-01-02", c.Value)
+			params.CursorDate = pgtype.Date{Time: date, Valid: true}
+		}
 	}
 
-	return responses
+	return params, nil
 }
 
-func (s *transactionService) UpdateTransaction(ctx context.Context, id string, dto TransactionUpdateRequest) error {
+func (s *transactionService) UpdateTransaction(ctx context.Context, userID, id string, dto TransactionUpdateRequest) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
 	transactionUUID, err := uuid.Parse(id)
 	if err != nil {
-		return fmt.Errorf("invalid transaction ID: %w", err)
+		return ErrTransactionNotFound
 	}
 
-	existing, err := s.repo.GetTransaction(ctx, transactionUUID)
+	current, err := s.repo.GetTransaction(ctx, transactionUUID, userUUID)
 	if err != nil {
 		return fmt.Errorf("service update transaction: %w", err)
 	}
 
-	params := db.UpdateTransactionParams{
-		ID:          transactionUUID,
-		Description: existing.Description,
-		Amount:      existing.Amount,
-		Date:        existing.Date,
-		Type:        existing.Type,
+	if err := reconciliation.CheckUnlocked(current); err != nil {
+		return err
 	}
 
-	if dto.Description != nil {
-		params.Description = *dto.Description
+	if err := s.books.CheckOpen(ctx, userUUID, current.Date); err != nil {
+		return err
 	}
 
-	if dto.Amount != nil {
-		params.Amount = *dto.Amount
+	if current.TransferID.Valid {
+		return s.updateTransferLeg(ctx, userID, current, dto)
 	}
 
+	// Only what does not affect the rest of the series can be changed on a
+	// single installment.
+	if current.InstallmentID.Valid &&
+		(dto.Type != nil || dto.Amount != nil || dto.Date != nil || dto.AccountID != nil || dto.Splits != nil) {
+		return ErrInstallmentField
+	}
+
+	var date *pgtype.Date
 	if dto.Date != nil {
-		date, err := time.Parse("2006-01-02", *dto.Date)
+		parsed, err := time.Parse("2006-01-02", *dto.Date)
 		if err != nil {
 			return fmt.Errorf("invalid date format: %w", err)
 		}
-		params.Date = pgtype.Date{Time: date, Valid: true}
+		date = &pgtype.Date{Time: parsed, Valid: true}
 	}
 
-	if dto.Type != nil {
-		params.Type = db.TransactionType(*dto.Type)
+	var accountUUID, categoryUUID *uuid.UUID
+	if dto.AccountID != nil {
+		parsed, err := uuid.Parse(*dto.AccountID)
+		if err != nil {
+			return fmt.Errorf("invalid account ID: %w", err)
+		}
+		accountUUID = &parsed
 	}
 
-	if err := s.repo.Update(ctx, params); err != nil {
+	if dto.CategoryID != nil {
+		parsed, err := uuid.Parse(*dto.CategoryID)
+		if err != nil {
+			return fmt.Errorf("invalid category ID: %w", err)
+		}
+		categoryUUID = &parsed
+	}
+
+	if err := s.checkOwnership(ctx, userUUID, accountUUID, categoryUUID); err != nil {
+		return err
+	}
+
+	var newPayee *pgtype.UUID
+	if dto.PayeeID != nil {
+		newPayee = &pgtype.UUID{}
+		if *dto.PayeeID != "" {
+			payeeRecord, err := s.resolvePayee(ctx, userUUID, *dto.PayeeID, "")
+			if err != nil {
+				return err
+			}
+			*newPayee = payeeID(payeeRecord)
+		}
+	}
+
+	var newSplits []db.CreateTransactionSplitParams
+	if dto.Splits != nil {
+		if newSplits, err = s.splitParams(ctx, userUUID, *dto.Splits); err != nil {
+			return err
+		}
+	}
+
+	err = s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+		accounts := s.accounts.WithTx(q)
+
+		existing, err := repo.GetTransactionForUpdate(ctx, transactionUUID, userUUID)
+		if err != nil {
+			return err
+		}
+
+		if err := reconciliation.CheckUnlocked(existing); err != nil {
+			return err
+		}
+
+		params := db.UpdateTransactionParams{
+			ID:          transactionUUID,
+			Description: existing.Description,
+			Amount:      existing.Amount,
+			Date:        existing.Date,
+			Type:        existing.Type,
+			AccountID:   existing.AccountID,
+			CategoryID:  existing.CategoryID,
+			PayeeID:     existing.PayeeID,
+			UserID:      userUUID,
+		}
+
+		if dto.Description != nil {
+			params.Description = *dto.Description
+		}
+
+		if dto.Amount != nil {
+			params.Amount = *dto.Amount
+		}
+
+		if date != nil {
+			params.Date = *date
+		}
+
+		if dto.Type != nil {
+			params.Type = db.TransactionType(*dto.Type)
+		}
+
+		if accountUUID != nil {
+			if err := checkCurrency(ctx, accounts, userUUID, *accountUUID, existing.Currency); err != nil {
+				return err
+			}
+			params.AccountID = *accountUUID
+		}
+
+		if categoryUUID != nil {
+			params.CategoryID = *categoryUUID
+		}
+
+		if newPayee != nil {
+			params.PayeeID = *newPayee
+		}
+
+		// Splits are edited as a unit: the lines sent replace the current
+		// ones, and either way they must still add up to the amount.
+		var total money.Money
+		split := false
+
+		if dto.Splits != nil {
+			for _, line := range newSplits {
+				total += line.Amount
+			}
+			split = len(newSplits) > 0
+		} else {
+			current, err := repo.GetSplits(ctx, transactionUUID)
+			if err != nil {
+				return err
+			}
+			for _, line := range current {
+				total += line.Amount
+			}
+			split = len(current) > 0
+
+			if split && categoryUUID != nil {
+				return ErrSplitCategory
+			}
+		}
+
+		if split {
+			if total != params.Amount {
+				return ErrSplitTotal
+			}
+			if dto.Splits != nil {
+				params.CategoryID = newSplits[0].CategoryID
+			}
+		}
+
+		if err := s.books.WithTx(q).CheckOpen(ctx, userUUID, existing.Date, params.Date); err != nil {
+			return err
+		}
+
+		if err := repo.Update(ctx, params); err != nil {
+			return err
+		}
+
+		if dto.Splits != nil {
+			if err := repo.ReplaceSplits(ctx, transactionUUID, newSplits); err != nil {
+				return err
+			}
+		}
+
+		if dto.Tags != nil {
+			if err := repo.SetTags(ctx, userUUID, transactionUUID, *dto.Tags); err != nil {
+				return err
+			}
+		}
+
+		if dto.Cleared != nil {
+			if err := repo.SetClearingStatus(ctx, transactionUUID, userUUID, clearingStatus(*dto.Cleared)); err != nil {
+				return err
+			}
+		}
+
+		// Revert the old effect and apply the new one, so changes of amount,
+		// type and account are all covered by the same two steps. They are
+		// summed per account first: only the final balance must be valid.
+		deltas := map[uuid.UUID]money.Money{existing.AccountID: -signedAmount(existing.Type, existing.Amount)}
+		deltas[params.AccountID] += signedAmount(params.Type, params.Amount)
+
+		for accountID, delta := range deltas {
+			if err := accounts.AdjustBalance(ctx, accountID, delta); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("service update transaction: %w", err)
 	}
 
 	return nil
 }
 
-func (s *transactionService) DeleteTransaction(ctx context.Context, id string) error {
+func (s *transactionService) DeleteTransaction(ctx context.Context, userID, id string) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
 	transactionUUID, err := uuid.Parse(id)
 	if err != nil {
-		return fmt.Errorf("invalid transaction ID: %w", err)
+		return ErrTransactionNotFound
+	}
+
+	current, err := s.repo.GetTransaction(ctx, transactionUUID, userUUID)
+	if err != nil {
+		return fmt.Errorf("service delete transaction: %w", err)
+	}
+
+	if err := reconciliation.CheckUnlocked(current); err != nil {
+		return err
 	}
 
-	if err := s.repo.Delete(ctx, transactionUUID); err != nil {
+	if err := s.books.CheckOpen(ctx, userUUID, current.Date); err != nil {
+		return err
+	}
+
+	if current.TransferID.Valid {
+		return s.transfers.Delete(ctx, userID, uuid.UUID(current.TransferID.Bytes).String())
+	}
+
+	if current.InstallmentID.Valid {
+		return s.DeleteInstallments(ctx, userID, uuid.UUID(current.InstallmentID.Bytes).String())
+	}
+
+	err = s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+
+		existing, err := repo.GetTransactionForUpdate(ctx, transactionUUID, userUUID)
+		if err != nil {
+			return err
+		}
+
+		if err := reconciliation.CheckUnlocked(existing); err != nil {
+			return err
+		}
+
+		if err := s.books.WithTx(q).CheckOpen(ctx, userUUID, existing.Date); err != nil {
+			return err
+		}
+
+		if err := repo.Delete(ctx, transactionUUID, userUUID); err != nil {
+			return err
+		}
+
+		return s.accounts.WithTx(q).AdjustBalance(ctx, existing.AccountID, -signedAmount(existing.Type, existing.Amount))
+	})
+	if err != nil {
 		return fmt.Errorf("service delete transaction: %w", err)
 	}
 
 	return nil
 }
 
-func (s *transactionService) applyFilters(transactions []*db.Transaction, filters *TransactionFilters) []*db.Transaction {
-	if filters == nil {
-		return transactions
+func (s *transactionService) Unlock(ctx context.Context, userID, id string) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
 	}
 
-	var filtered []*db.Transaction
+	transactionUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrTransactionNotFound
+	}
 
-	for _, transaction := range transactions {
-		if filters.Type != nil && string(transaction.Type) != *filters.Type {
-			continue
+	if _, err := s.repo.GetTransaction(ctx, transactionUUID, userUUID); err != nil {
+		return fmt.Errorf("service unlock transaction: %w", err)
+	}
+
+	return s.repo.Unlock(ctx, transactionUUID, userUUID)
+}
+
+// updateTransferLeg forwards an edit of one transfer leg to the transfer
+// service, so both legs stay in sync. Tags and the cleared mark belong to
+// the leg itself.
+func (s *transactionService) updateTransferLeg(ctx context.Context, userID string, leg *db.Transaction, dto TransactionUpdateRequest) error {
+	if dto.Type != nil || dto.CategoryID != nil || dto.Splits != nil || dto.PayeeID != nil {
+		return ErrTransferLegField
+	}
+
+	if dto.Tags != nil || dto.Cleared != nil {
+		err := s.uow.ExecTx(ctx, func(q *db.Queries) error {
+			repo := s.repo.WithTx(q)
+
+			if dto.Tags != nil {
+				if err := repo.SetTags(ctx, leg.UserID, leg.ID, *dto.Tags); err != nil {
+					return err
+				}
+			}
+
+			if dto.Cleared != nil {
+				return repo.SetClearingStatus(ctx, leg.ID, leg.UserID, clearingStatus(*dto.Cleared))
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("service update transaction: %w", err)
 		}
+	}
 
-		if filters.StartDate != nil {
-			startDate, err := time.Parse("2006-01-02", *filters.StartDate)
-			if err == nil && transaction.Date.Valid && transaction.Date.Time.Before(startDate) {
-				continue
+	if dto.Description == nil && dto.Amount == nil && dto.Date == nil && dto.AccountID == nil {
+		return nil
+	}
+
+	req := transfer.TransferUpdateRequest{
+		Description: dto.Description,
+		Date:        dto.Date,
+	}
+
+	// The amount of the incoming leg is what arrives, which differs from the
+	// amount sent when the accounts have different currencies.
+	if leg.Type == db.TransactionTypeTransferOut {
+		req.Amount = dto.Amount
+		req.FromAccountID = dto.AccountID
+	} else {
+		req.ToAmount = dto.Amount
+		req.ToAccountID = dto.AccountID
+	}
+
+	return s.transfers.Update(ctx, userID, uuid.UUID(leg.TransferID.Bytes).String(), req)
+}
+
+// checkOwnership rejects account and category IDs that do not belong to the
+// user. Nil IDs are skipped, which lets partial updates reuse it.
+func (s *transactionService) checkOwnership(ctx context.Context, userID uuid.UUID, accountID, categoryID *uuid.UUID) error {
+	if accountID != nil {
+		if _, err := s.accounts.GetAccount(ctx, *accountID, userID); err != nil {
+			if errors.Is(err, account.ErrAccountNotFound) {
+				return ErrInvalidAccount
 			}
+			return fmt.Errorf("service check account: %w", err)
 		}
+	}
 
-		if filters.EndDate != nil {
-			endDate, err := time.Parse("2006-01-02", *filters.EndDate)
-			if err == nil && transaction.Date.Valid && transaction.Date.Time.After(endDate) {
-				continue
+	if categoryID != nil {
+		if _, err := s.categories.GetCategory(ctx, *categoryID, userID); err != nil {
+			if errors.Is(err, category.ErrCategoryNotFound) {
+				return ErrInvalidCategory
 			}
+			return fmt.Errorf("service check category: %w", err)
 		}
+	}
+
+	return nil
+}
+
+// checkCurrency rejects moving a transaction to an account in another
+// currency: the amount is kept as is, so it would be added to that balance
+// in the wrong currency.
+func checkCurrency(ctx context.Context, accounts account.Repository, userID, accountID uuid.UUID, currency string) error {
+	record, err := accounts.GetAccount(ctx, accountID, userID)
+	if err != nil {
+		return fmt.Errorf("service check currency: %w", err)
+	}
 
-		filtered = append(filtered, transaction)
+	if record.Currency != currency {
+		return ErrCurrencyMismatch
 	}
 
-	return filtered
+	return nil
+}
+
+// resolvePayee returns the payee id when one is given, checking that it
+// belongs to the user, or else the payee whose alias matches the
+// description. It returns nil when neither finds a payee.
+func (s *transactionService) resolvePayee(ctx context.Context, userID uuid.UUID, id, description string) (*db.Payee, error) {
+	if id != "" {
+		payeeUUID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, ErrInvalidPayee
+		}
+
+		record, err := s.payees.GetPayee(ctx, payeeUUID, userID)
+		if err != nil {
+			if errors.Is(err, payee.ErrPayeeNotFound) {
+				return nil, ErrInvalidPayee
+			}
+			return nil, fmt.Errorf("service check payee: %w", err)
+		}
+		return record, nil
+	}
+
+	if description == "" {
+		return nil, nil
+	}
+
+	record, err := s.payees.Match(ctx, userID, description)
+	if err != nil {
+		return nil, fmt.Errorf("service match payee: %w", err)
+	}
+	return record, nil
+}
+
+// categoryFor parses the category sent, falling back to the default
+// category of the payee.
+func categoryFor(categoryID string, p *db.Payee) (uuid.UUID, error) {
+	if categoryID != "" {
+		parsed, err := uuid.Parse(categoryID)
+		if err != nil {
+			return uuid.Nil, ErrInvalidCategory
+		}
+		return parsed, nil
+	}
+
+	if p != nil && p.DefaultCategoryID.Valid {
+		return uuid.UUID(p.DefaultCategoryID.Bytes), nil
+	}
+
+	return uuid.Nil, ErrCategoryRequired
+}
+
+func payeeID(p *db.Payee) pgtype.UUID {
+	if p == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: p.ID, Valid: true}
+}
+
+// splitParams parses split lines and checks that their categories belong to
+// the user. TransactionID and Position are filled in by ReplaceSplits.
+func (s *transactionService) splitParams(ctx context.Context, userID uuid.UUID, splits []SplitRequest) ([]db.CreateTransactionSplitParams, error) {
+	params := make([]db.CreateTransactionSplitParams, len(splits))
+	checked := make(map[uuid.UUID]bool)
+
+	for i, split := range splits {
+		categoryUUID, err := uuid.Parse(split.CategoryID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid split category ID: %w", err)
+		}
+
+		if !checked[categoryUUID] {
+			if err := s.checkOwnership(ctx, userID, nil, &categoryUUID); err != nil {
+				if errors.Is(err, ErrInvalidCategory) {
+					return nil, ErrInvalidSplitCategory
+				}
+				return nil, err
+			}
+			checked[categoryUUID] = true
+		}
+
+		params[i] = db.CreateTransactionSplitParams{
+			CategoryID: categoryUUID,
+			Amount:     split.Amount,
+			Memo:       split.Memo,
+		}
+	}
+
+	return params, nil
+}
+
+func clearingStatus(cleared bool) db.ClearingStatus {
+	if cleared {
+		return db.ClearingStatusCleared
+	}
+	return db.ClearingStatusUncleared
+}
+
+// signedAmount returns how much a transaction adds to its account balance.
+func signedAmount(t db.TransactionType, amount money.Money) money.Money {
+	if t == db.TransactionTypeExpense || t == db.TransactionTypeTransferOut {
+		return -amount
+	}
+	return amount
 }
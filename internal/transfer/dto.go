@@ -0,0 +1,122 @@
+package transfer
+
+import (
+	"context"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/internal/validator"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+)
+
+type TransferCreateRequest struct {
+	Description   string      `json:"description" validate:"required"`
+	Amount        money.Money `json:"amount" validate:"required"`
+	Date          string      `json:"date" validate:"required"` // formato: YYYY-MM-DD
+	FromAccountID string      `json:"from_account_id" validate:"required"`
+	ToAccountID   string      `json:"to_account_id" validate:"required"`
+	StatementDate string      `json:"-"` // preenchido apenas por pagamentos de fatura
+	GoalID        string      `json:"-"` // preenchido apenas por contribuições a metas
+
+	// ToAmount is what arrives in the destination account when the accounts
+	// have different currencies. Without it the amount is converted at the
+	// exchange rate of the transfer date.
+	ToAmount *money.Money `json:"to_amount,omitempty"`
+}
+
+func (r *TransferCreateRequest) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(r.Description), "description", "this field cannot be empty")
+	eval.CheckField(r.Amount > 0, "amount", "this field must be greater than 0")
+	eval.CheckField(validator.NotBlank(r.Date), "date", "this field cannot be empty")
+	eval.CheckField(validator.NotBlank(r.FromAccountID), "from_account_id", "this field cannot be empty")
+	eval.CheckField(validator.NotBlank(r.ToAccountID), "to_account_id", "this field cannot be empty")
+	eval.CheckField(r.FromAccountID != r.ToAccountID, "to_account_id", "this field must differ from from_account_id")
+
+	if r.ToAmount != nil {
+		eval.CheckField(*r.ToAmount > 0, "to_amount", "this field must be greater than 0")
+	}
+
+	return eval
+}
+
+type TransferUpdateRequest struct {
+	Description   *string      `json:"description,omitempty"`
+	Amount        *money.Money `json:"amount,omitempty"`
+	Date          *string      `json:"date,omitempty"` // formato: YYYY-MM-DD
+	FromAccountID *string      `json:"from_account_id,omitempty"`
+	ToAccountID   *string      `json:"to_account_id,omitempty"`
+
+	// Between currencies the amount that arrives is kept while amount and
+	// accounts stay the same; otherwise it is converted again unless sent.
+	ToAmount *money.Money `json:"to_amount,omitempty"`
+}
+
+func (r *TransferUpdateRequest) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	hasAtLeastOneField := r.Description != nil || r.Amount != nil || r.Date != nil ||
+		r.FromAccountID != nil || r.ToAccountID != nil || r.ToAmount != nil
+
+	eval.CheckField(hasAtLeastOneField, "fields", "at least one field must be sent to update")
+
+	if r.Amount != nil {
+		eval.CheckField(*r.Amount > 0, "amount", "this field must be greater than 0")
+	}
+
+	if r.ToAmount != nil {
+		eval.CheckField(*r.ToAmount > 0, "to_amount", "this field must be greater than 0")
+	}
+
+	return eval
+}
+
+type TransferResponse struct {
+	ID               string      `json:"id"`
+	Description      string      `json:"description"`
+	Amount           money.Money `json:"amount"`
+	ToAmount         money.Money `json:"to_amount"`
+	FromCurrency     string      `json:"from_currency"`
+	ToCurrency       string      `json:"to_currency"`
+	ExchangeRate     *float64    `json:"exchange_rate,omitempty"` // apenas entre moedas diferentes
+	Date             string      `json:"date"`
+	FromAccountID    string      `json:"from_account_id"`
+	ToAccountID      string      `json:"to_account_id"`
+	OutTransactionID string      `json:"out_transaction_id"`
+	InTransactionID  string      `json:"in_transaction_id"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+// TransferToResponse builds a transfer from its two legs.
+func TransferToResponse(out, in *db.Transaction) TransferResponse {
+	var dateStr string
+	if out.Date.Valid {
+		dateStr = out.Date.Time.Format("2006-01-02")
+	}
+
+	response := TransferResponse{
+		ID:               uuid.UUID(out.TransferID.Bytes).String(),
+		Description:      out.Description,
+		Amount:           out.Amount,
+		ToAmount:         in.Amount,
+		FromCurrency:     out.Currency,
+		ToCurrency:       in.Currency,
+		Date:             dateStr,
+		FromAccountID:    out.AccountID.String(),
+		ToAccountID:      in.AccountID.String(),
+		OutTransactionID: out.ID.String(),
+		InTransactionID:  in.ID.String(),
+		CreatedAt:        out.CreatedAt.Time,
+		UpdatedAt:        out.UpdatedAt.Time,
+	}
+
+	if out.ExchangeRate.Valid {
+		rate, _ := out.ExchangeRate.Float64Value()
+		response.ExchangeRate = &rate.Float64
+	}
+
+	return response
+}
@@ -0,0 +1,199 @@
+package transfer
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/EduardoMark/my-finance-api/internal/account"
+	"github.com/EduardoMark/my-finance-api/internal/books"
+	"github.com/EduardoMark/my-finance-api/internal/exchangerate"
+	"github.com/EduardoMark/my-finance-api/internal/middlewares"
+	"github.com/EduardoMark/my-finance-api/internal/reconciliation"
+	"github.com/EduardoMark/my-finance-api/pkg/httputils"
+	"github.com/EduardoMark/my-finance-api/pkg/token"
+	"github.com/go-chi/chi/v5"
+)
+
+type TransferHandler struct {
+	svc   Service
+	token *token.TokenManager
+}
+
+func NewTransferHandler(svc Service, token *token.TokenManager) TransferHandler {
+	return TransferHandler{
+		svc:   svc,
+		token: token,
+	}
+}
+
+func (h *TransferHandler) RegisterRoutes(r chi.Router) {
+	r.Route("/transfers", func(r chi.Router) {
+		r.Use(middlewares.AuthMiddleware(h.token))
+
+		r.Post("/", h.Create)
+		r.Get("/", h.GetAllTransfers)
+		r.Get("/{id}", h.GetTransfer)
+		r.Put("/{id}", h.Update)
+		r.Delete("/{id}", h.Delete)
+	})
+}
+
+func (h *TransferHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*TransferCreateRequest](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if err := h.svc.Create(ctx, userID, *data); err != nil {
+		if errors.Is(err, ErrInvalidAccount) || errors.Is(err, account.ErrAccountNotFound) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"account_id": err.Error()})
+			return
+		}
+		if errors.Is(err, ErrToAmount) || errors.Is(err, exchangerate.ErrMissingRate) {
+			_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"to_amount": err.Error()})
+			return
+		}
+		if errors.Is(err, account.ErrNegativeBalance) || errors.Is(err, books.ErrClosed) {
+			httputils.Error(w, r, http.StatusConflict, err.Error())
+			return
+		}
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *TransferHandler) GetTransfer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		httputils.Error(w, r, http.StatusBadRequest, "transfer ID is required")
+		return
+	}
+
+	transfer, err := h.svc.GetTransfer(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, ErrTransferNotFound) {
+			httputils.NotFound(w)
+			return
+		}
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, transfer)
+}
+
+func (h *TransferHandler) GetAllTransfers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	transfers, err := h.svc.GetAllTransfers(ctx, userID)
+	if err != nil {
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, transfers)
+}
+
+func (h *TransferHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		httputils.Error(w, r, http.StatusBadRequest, "transfer ID is required")
+		return
+	}
+
+	data, problems, err := httputils.DecodeValidJson[*TransferUpdateRequest](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if err := h.svc.Update(ctx, userID, id, *data); err != nil {
+		WriteUpdateError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TransferHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		httputils.Error(w, r, http.StatusBadRequest, "transfer ID is required")
+		return
+	}
+
+	if err := h.svc.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, ErrTransferNotFound) {
+			httputils.NotFound(w)
+			return
+		}
+		if errors.Is(err, account.ErrNegativeBalance) || errors.Is(err, reconciliation.ErrLocked) || errors.Is(err, books.ErrClosed) {
+			httputils.Error(w, r, http.StatusConflict, err.Error())
+			return
+		}
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// WriteUpdateError maps transfer update errors to responses. It is shared
+// with the transaction handler, which forwards edits of transfer legs here.
+func WriteUpdateError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrTransferNotFound):
+		httputils.NotFound(w)
+	case errors.Is(err, ErrInvalidAccount), errors.Is(err, account.ErrAccountNotFound):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"account_id": err.Error()})
+	case errors.Is(err, ErrSameAccount):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"account_id": err.Error()})
+	case errors.Is(err, ErrToAmount), errors.Is(err, exchangerate.ErrMissingRate):
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"to_amount": err.Error()})
+	case errors.Is(err, account.ErrNegativeBalance), errors.Is(err, reconciliation.ErrLocked), errors.Is(err, books.ErrClosed):
+		httputils.Error(w, r, http.StatusConflict, err.Error())
+	default:
+		httputils.Error(w, r, http.StatusInternalServerError, "Internal server error")
+	}
+}
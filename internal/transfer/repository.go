@@ -0,0 +1,157 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Repository interface {
+	CreateLeg(ctx context.Context, args db.CreateTransferLegParams) error
+	GetLegs(ctx context.Context, transferID, userID uuid.UUID) (out, in *db.Transaction, err error)
+	GetLegsForUpdate(ctx context.Context, transferID, userID uuid.UUID) (out, in *db.Transaction, err error)
+	GetAllLegs(ctx context.Context, userID uuid.UUID) ([]*db.Transaction, error)
+	UpdateLeg(ctx context.Context, args db.UpdateTransferLegParams) error
+	Delete(ctx context.Context, transferID, userID uuid.UUID) error
+	CreateStatementPayment(ctx context.Context, transferID uuid.UUID, statementDate pgtype.Date) error
+	CreateGoalContribution(ctx context.Context, transferID, goalID, userID uuid.UUID) error
+	WithTx(q *db.Queries) Repository
+}
+
+type transferRepository struct {
+	db *db.Queries
+}
+
+func NewTransferRepo(db *db.Queries) Repository {
+	return &transferRepository{
+		db: db,
+	}
+}
+
+var ErrTransferNotFound = errors.New("transfer not found")
+var ErrInvalidAccount = errors.New("account not found")
+var ErrSameAccount = errors.New("from and to accounts must differ")
+var ErrToAmount = errors.New("to_amount must equal amount between accounts of the same currency")
+
+func (r *transferRepository) CreateLeg(ctx context.Context, args db.CreateTransferLegParams) error {
+	if err := r.db.CreateTransferLeg(ctx, args); err != nil {
+		return fmt.Errorf("repository create leg: %w", err)
+	}
+
+	return nil
+}
+
+func (r *transferRepository) GetLegs(ctx context.Context, transferID, userID uuid.UUID) (*db.Transaction, *db.Transaction, error) {
+	records, err := r.db.GetTransferLegs(ctx, db.GetTransferLegsParams{
+		TransferID: toPgUUID(transferID),
+		UserID:     userID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("repository get legs: %w", err)
+	}
+
+	return splitLegs(records)
+}
+
+func (r *transferRepository) GetLegsForUpdate(ctx context.Context, transferID, userID uuid.UUID) (*db.Transaction, *db.Transaction, error) {
+	records, err := r.db.GetTransferLegsForUpdate(ctx, db.GetTransferLegsForUpdateParams{
+		TransferID: toPgUUID(transferID),
+		UserID:     userID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("repository get legs for update: %w", err)
+	}
+
+	return splitLegs(records)
+}
+
+func (r *transferRepository) GetAllLegs(ctx context.Context, userID uuid.UUID) ([]*db.Transaction, error) {
+	records, err := r.db.GetAllTransferLegs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository get all legs: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *transferRepository) UpdateLeg(ctx context.Context, args db.UpdateTransferLegParams) error {
+	if err := r.db.UpdateTransferLeg(ctx, args); err != nil {
+		return fmt.Errorf("repository update leg: %w", err)
+	}
+
+	return nil
+}
+
+func (r *transferRepository) Delete(ctx context.Context, transferID, userID uuid.UUID) error {
+	err := r.db.DeleteTransfer(ctx, db.DeleteTransferParams{
+		TransferID: toPgUUID(transferID),
+		UserID:     userID,
+	})
+	if err != nil {
+		return fmt.Errorf("repository delete: %w", err)
+	}
+
+	return nil
+}
+
+// CreateStatementPayment marks the incoming leg of a transfer as a payment
+// of the credit card statement that closed on statementDate.
+func (r *transferRepository) CreateStatementPayment(ctx context.Context, transferID uuid.UUID, statementDate pgtype.Date) error {
+	args := db.CreateStatementPaymentParams{
+		StatementDate: statementDate,
+		TransferID:    toPgUUID(transferID),
+	}
+
+	if err := r.db.CreateStatementPayment(ctx, args); err != nil {
+		return fmt.Errorf("repository create statement payment: %w", err)
+	}
+
+	return nil
+}
+
+// CreateGoalContribution links the incoming leg of a transfer to a goal.
+func (r *transferRepository) CreateGoalContribution(ctx context.Context, transferID, goalID, userID uuid.UUID) error {
+	args := db.CreateGoalTransferContributionParams{
+		GoalID:     goalID,
+		TransferID: toPgUUID(transferID),
+		UserID:     userID,
+	}
+
+	if _, err := r.db.CreateGoalTransferContribution(ctx, args); err != nil {
+		return fmt.Errorf("repository create goal contribution: %w", err)
+	}
+
+	return nil
+}
+
+func (r *transferRepository) WithTx(q *db.Queries) Repository {
+	return &transferRepository{
+		db: q,
+	}
+}
+
+// splitLegs returns the transfer_out and transfer_in legs of a transfer.
+func splitLegs(records []*db.Transaction) (out, in *db.Transaction, err error) {
+	for _, record := range records {
+		switch record.Type {
+		case db.TransactionTypeTransferOut:
+			out = record
+		case db.TransactionTypeTransferIn:
+			in = record
+		}
+	}
+
+	if out == nil || in == nil {
+		return nil, nil, ErrTransferNotFound
+	}
+
+	return out, in, nil
+}
+
+func toPgUUID(id uuid.UUID) pgtype.UUID {
+	return pgtype.UUID{Bytes: id, Valid: true}
+}
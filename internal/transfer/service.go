@@ -0,0 +1,437 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/internal/account"
+	"github.com/EduardoMark/my-finance-api/internal/books"
+	"github.com/EduardoMark/my-finance-api/internal/exchangerate"
+	"github.com/EduardoMark/my-finance-api/internal/reconciliation"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Service interface {
+	Create(ctx context.Context, userID string, dto TransferCreateRequest) error
+	GetTransfer(ctx context.Context, userID, id string) (*TransferResponse, error)
+	GetAllTransfers(ctx context.Context, userID string) ([]*TransferResponse, error)
+	Update(ctx context.Context, userID, id string, dto TransferUpdateRequest) error
+	Delete(ctx context.Context, userID, id string) error
+}
+
+type transferService struct {
+	repo     Repository
+	accounts account.Repository
+	rates    exchangerate.Repository
+	books    books.Repository
+	uow      pgstore.UnitOfWork
+}
+
+func NewTransferService(repo Repository, accounts account.Repository, rates exchangerate.Repository, books books.Repository, uow pgstore.UnitOfWork) Service {
+	return &transferService{
+		repo:     repo,
+		accounts: accounts,
+		rates:    rates,
+		books:    books,
+		uow:      uow,
+	}
+}
+
+func (s *transferService) Create(ctx context.Context, userID string, dto TransferCreateRequest) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	from, err := s.ownedAccount(ctx, userUUID, dto.FromAccountID)
+	if err != nil {
+		return err
+	}
+
+	to, err := s.ownedAccount(ctx, userUUID, dto.ToAccountID)
+	if err != nil {
+		return err
+	}
+
+	date, err := time.Parse("2006-01-02", dto.Date)
+	if err != nil {
+		return fmt.Errorf("invalid date format: %w", err)
+	}
+
+	transferUUID := uuid.New()
+	transferID := toPgUUID(transferUUID)
+
+	out := db.CreateTransferLegParams{
+		Description: dto.Description,
+		Amount:      dto.Amount,
+		Date:        pgtype.Date{Time: date, Valid: true},
+		Type:        db.TransactionTypeTransferOut,
+		UserID:      userUUID,
+		AccountID:   from.ID,
+		TransferID:  transferID,
+	}
+
+	if from.Currency == to.Currency && dto.ToAmount != nil && *dto.ToAmount != dto.Amount {
+		return ErrToAmount
+	}
+
+	in := out
+	in.Type = db.TransactionTypeTransferIn
+	in.AccountID = to.ID
+	if dto.ToAmount != nil {
+		in.Amount = *dto.ToAmount
+	}
+
+	err = s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+		accounts := s.accounts.WithTx(q)
+
+		if err := s.books.WithTx(q).CheckOpen(ctx, userUUID, out.Date); err != nil {
+			return err
+		}
+
+		if from.Currency != to.Currency {
+			if dto.ToAmount == nil {
+				converted, err := s.rates.WithTx(q).Convert(ctx, userUUID, out.Amount, from.Currency, to.Currency, out.Date)
+				if err != nil {
+					return err
+				}
+				in.Amount = converted
+			}
+
+			out.ExchangeRate = effectiveRate(out.Amount, in.Amount)
+			in.ExchangeRate = out.ExchangeRate
+		}
+
+		if err := repo.CreateLeg(ctx, out); err != nil {
+			return err
+		}
+
+		if err := repo.CreateLeg(ctx, in); err != nil {
+			return err
+		}
+
+		if err := accounts.AdjustBalance(ctx, from.ID, -out.Amount); err != nil {
+			return err
+		}
+
+		if err := accounts.AdjustBalance(ctx, to.ID, in.Amount); err != nil {
+			return err
+		}
+
+		if dto.GoalID != "" {
+			goalUUID, err := uuid.Parse(dto.GoalID)
+			if err != nil {
+				return fmt.Errorf("invalid goal ID: %w", err)
+			}
+
+			if err := repo.CreateGoalContribution(ctx, transferUUID, goalUUID, userUUID); err != nil {
+				return err
+			}
+		}
+
+		if dto.StatementDate == "" {
+			return nil
+		}
+
+		statementDate, err := time.Parse("2006-01-02", dto.StatementDate)
+		if err != nil {
+			return fmt.Errorf("invalid statement date format: %w", err)
+		}
+
+		return repo.CreateStatementPayment(ctx, transferUUID, pgtype.Date{Time: statementDate, Valid: true})
+	})
+	if err != nil {
+		return fmt.Errorf("service create transfer: %w", err)
+	}
+
+	return nil
+}
+
+func (s *transferService) GetTransfer(ctx context.Context, userID, id string) (*TransferResponse, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	transferUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrTransferNotFound
+	}
+
+	out, in, err := s.repo.GetLegs(ctx, transferUUID, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("service get transfer: %w", err)
+	}
+
+	response := TransferToResponse(out, in)
+	return &response, nil
+}
+
+func (s *transferService) GetAllTransfers(ctx context.Context, userID string) ([]*TransferResponse, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	records, err := s.repo.GetAllLegs(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("service get all transfers: %w", err)
+	}
+
+	// Legs are grouped by transfer_id instead of paired by position, so a
+	// transfer missing a leg is skipped without shifting the ones after it.
+	var order []uuid.UUID
+	legs := make(map[uuid.UUID][]*db.Transaction)
+	for _, record := range records {
+		id := uuid.UUID(record.TransferID.Bytes)
+		if _, ok := legs[id]; !ok {
+			order = append(order, id)
+		}
+		legs[id] = append(legs[id], record)
+	}
+
+	responses := []*TransferResponse{}
+	for _, id := range order {
+		out, in, err := splitLegs(legs[id])
+		if err != nil {
+			continue
+		}
+
+		response := TransferToResponse(out, in)
+		responses = append(responses, &response)
+	}
+
+	return responses, nil
+}
+
+func (s *transferService) Update(ctx context.Context, userID, id string, dto TransferUpdateRequest) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	transferUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrTransferNotFound
+	}
+
+	var from, to *db.Account
+	if dto.FromAccountID != nil {
+		if from, err = s.ownedAccount(ctx, userUUID, *dto.FromAccountID); err != nil {
+			return err
+		}
+	}
+
+	if dto.ToAccountID != nil {
+		if to, err = s.ownedAccount(ctx, userUUID, *dto.ToAccountID); err != nil {
+			return err
+		}
+	}
+
+	var date *pgtype.Date
+	if dto.Date != nil {
+		parsed, err := time.Parse("2006-01-02", *dto.Date)
+		if err != nil {
+			return fmt.Errorf("invalid date format: %w", err)
+		}
+		date = &pgtype.Date{Time: parsed, Valid: true}
+	}
+
+	err = s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+		accounts := s.accounts.WithTx(q)
+
+		out, in, err := repo.GetLegsForUpdate(ctx, transferUUID, userUUID)
+		if err != nil {
+			return err
+		}
+
+		// Either leg may be reconciled on its own account's statement.
+		if err := reconciliation.CheckUnlocked(out, in); err != nil {
+			return err
+		}
+
+		outParams := db.UpdateTransferLegParams{
+			ID:           out.ID,
+			Description:  out.Description,
+			Amount:       out.Amount,
+			Date:         out.Date,
+			AccountID:    out.AccountID,
+			ExchangeRate: out.ExchangeRate,
+			UserID:       userUUID,
+		}
+
+		if dto.Description != nil {
+			outParams.Description = *dto.Description
+		}
+
+		if dto.Amount != nil {
+			outParams.Amount = *dto.Amount
+		}
+
+		if date != nil {
+			outParams.Date = *date
+		}
+
+		if err := s.books.WithTx(q).CheckOpen(ctx, userUUID, out.Date, outParams.Date); err != nil {
+			return err
+		}
+
+		fromCurrency, toCurrency := out.Currency, in.Currency
+		if from != nil {
+			outParams.AccountID = from.ID
+			fromCurrency = from.Currency
+		}
+
+		inParams := outParams
+		inParams.ID = in.ID
+		inParams.AccountID = in.AccountID
+		if to != nil {
+			inParams.AccountID = to.ID
+			toCurrency = to.Currency
+		}
+
+		if outParams.AccountID == inParams.AccountID {
+			return ErrSameAccount
+		}
+
+		switch {
+		case fromCurrency == toCurrency:
+			// Editing the incoming leg of a transfer sends only to_amount.
+			if dto.ToAmount != nil && dto.Amount == nil {
+				outParams.Amount = *dto.ToAmount
+			}
+			if dto.ToAmount != nil && *dto.ToAmount != outParams.Amount {
+				return ErrToAmount
+			}
+			inParams.Amount = outParams.Amount
+		case dto.ToAmount != nil:
+			inParams.Amount = *dto.ToAmount
+		case dto.Amount == nil && fromCurrency == out.Currency && toCurrency == in.Currency:
+			inParams.Amount = in.Amount
+		default:
+			converted, err := s.rates.WithTx(q).Convert(ctx, userUUID, outParams.Amount, fromCurrency, toCurrency, outParams.Date)
+			if err != nil {
+				return err
+			}
+			inParams.Amount = converted
+		}
+
+		outParams.ExchangeRate = pgtype.Numeric{}
+		if fromCurrency != toCurrency {
+			outParams.ExchangeRate = effectiveRate(outParams.Amount, inParams.Amount)
+		}
+		inParams.ExchangeRate = outParams.ExchangeRate
+
+		if err := repo.UpdateLeg(ctx, outParams); err != nil {
+			return err
+		}
+
+		if err := repo.UpdateLeg(ctx, inParams); err != nil {
+			return err
+		}
+
+		// Both legs are reverted and applied again, summed per account so
+		// only the final balances must be valid.
+		deltas := map[uuid.UUID]money.Money{}
+		deltas[out.AccountID] += out.Amount
+		deltas[in.AccountID] -= in.Amount
+		deltas[outParams.AccountID] -= outParams.Amount
+		deltas[inParams.AccountID] += inParams.Amount
+
+		for accountID, delta := range deltas {
+			if err := accounts.AdjustBalance(ctx, accountID, delta); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("service update transfer: %w", err)
+	}
+
+	return nil
+}
+
+func (s *transferService) Delete(ctx context.Context, userID, id string) error {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	transferUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrTransferNotFound
+	}
+
+	err = s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+		accounts := s.accounts.WithTx(q)
+
+		out, in, err := repo.GetLegsForUpdate(ctx, transferUUID, userUUID)
+		if err != nil {
+			return err
+		}
+
+		// Either leg may be reconciled on its own account's statement.
+		if err := reconciliation.CheckUnlocked(out, in); err != nil {
+			return err
+		}
+
+		if err := s.books.WithTx(q).CheckOpen(ctx, userUUID, out.Date); err != nil {
+			return err
+		}
+
+		if err := repo.Delete(ctx, transferUUID, userUUID); err != nil {
+			return err
+		}
+
+		if err := accounts.AdjustBalance(ctx, out.AccountID, out.Amount); err != nil {
+			return err
+		}
+
+		return accounts.AdjustBalance(ctx, in.AccountID, -in.Amount)
+	})
+	if err != nil {
+		return fmt.Errorf("service delete transfer: %w", err)
+	}
+
+	return nil
+}
+
+func (s *transferService) ownedAccount(ctx context.Context, userID uuid.UUID, id string) (*db.Account, error) {
+	accountUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInvalidAccount
+	}
+
+	record, err := s.accounts.GetAccount(ctx, accountUUID, userID)
+	if err != nil {
+		if errors.Is(err, account.ErrAccountNotFound) {
+			return nil, ErrInvalidAccount
+		}
+		return nil, fmt.Errorf("service check account: %w", err)
+	}
+
+	return record, nil
+}
+
+// effectiveRate is the rate actually applied by a transfer between
+// currencies, toAmount divided by amount with ten decimal places.
+func effectiveRate(amount, toAmount money.Money) pgtype.Numeric {
+	// Adding half the divisor before dividing rounds half up.
+	scaled := new(big.Int).Mul(big.NewInt(toAmount.Cents()), big.NewInt(2e10))
+	scaled.Add(scaled, big.NewInt(amount.Cents()))
+	scaled.Quo(scaled, big.NewInt(2*amount.Cents()))
+
+	return pgtype.Numeric{Int: scaled, Exp: -10, Valid: true}
+}
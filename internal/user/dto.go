@@ -2,7 +2,9 @@ package user
 
 import (
 	"context"
+	"time"
 
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
 	"github.com/EduardoMark/my-finance-api/internal/validator"
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -33,8 +35,24 @@ type UserLoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// UserLoginResponse holds a short-lived access token and the refresh token
+// used to get the next pair.
 type UserLoginResponse struct {
-	Token string `json:"token"`
+	Token        string    `json:"token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+}
+
+type UserRefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (r *UserRefreshRequest) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+
+	eval.CheckField(validator.NotBlank(r.RefreshToken), "refresh_token", "this field cannot be empty")
+
+	return eval
 }
 
 func (r *UserLoginRequest) Valid(ctx context.Context) validator.Evaluator {
@@ -50,17 +68,34 @@ func (r *UserLoginRequest) Valid(ctx context.Context) validator.Evaluator {
 }
 
 type UserUpdateRequest struct {
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Password     string `json:"password"`
+	BaseCurrency string `json:"base_currency"`
 }
 
 type UserResponse struct {
-	ID        string             `json:"id"`
-	Name      string             `json:"name"`
-	Email     string             `json:"email"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	ID           string             `json:"id"`
+	Name         string             `json:"name"`
+	Email        string             `json:"email"`
+	Role         string             `json:"role"`
+	BaseCurrency string             `json:"base_currency"`
+	DisabledAt   pgtype.Timestamptz `json:"disabled_at"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+func UserToResponse(record *db.User) UserResponse {
+	return UserResponse{
+		ID:           record.ID.String(),
+		Name:         record.Name,
+		Email:        record.Email,
+		Role:         string(record.Role),
+		BaseCurrency: record.BaseCurrency,
+		DisabledAt:   record.DisabledAt,
+		CreatedAt:    record.CreatedAt,
+		UpdatedAt:    record.UpdatedAt,
+	}
 }
 
 func (r *UserUpdateRequest) Valid(ctx context.Context) validator.Evaluator {
@@ -69,7 +104,8 @@ func (r *UserUpdateRequest) Valid(ctx context.Context) validator.Evaluator {
 	eval.CheckField(
 		validator.NotBlank(r.Name) ||
 			validator.NotBlank(r.Email) ||
-			validator.NotBlank(r.Password),
+			validator.NotBlank(r.Password) ||
+			validator.NotBlank(r.BaseCurrency),
 		"fields", "at least one field must be sent to update",
 	)
 
@@ -85,5 +121,9 @@ func (r *UserUpdateRequest) Valid(ctx context.Context) validator.Evaluator {
 		eval.CheckField(validator.MinChars(r.Password, 8), "password", "this field need have min 8 chars")
 	}
 
+	if validator.NotBlank(r.BaseCurrency) {
+		eval.CheckField(validator.Currency(r.BaseCurrency), "base_currency", "this field must be an upper case ISO 4217 code such as BRL")
+	}
+
 	return eval
 }
@@ -5,9 +5,11 @@ import (
 	"net/http"
 
 	"github.com/EduardoMark/my-finance-api/internal/middlewares"
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
 	"github.com/EduardoMark/my-finance-api/pkg/httputils"
 	"github.com/EduardoMark/my-finance-api/pkg/token"
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 )
 
 type UserHandler struct {
@@ -25,14 +27,25 @@ func NewUserHandler(svc Service, token *token.TokenManager) *UserHandler {
 func (h *UserHandler) RegisterRoutes(r chi.Router) {
 	r.Post("/users/login", h.Login)
 	r.Post("/users/signup", h.Signup)
+	r.Post("/users/refresh", h.Refresh)
+	r.Post("/users/logout", h.Logout)
 
 	r.Route("/users", func(r chi.Router) {
 		r.Use(middlewares.AuthMiddleware(h.token))
 
-		r.Get("/{id}", h.GetUser)
-		r.Get("/", h.GetAllUsers)
-		r.Put("/{id}", h.Update)
-		r.Delete("/{id}", h.Delete)
+		r.Get("/me", h.GetMe)
+		r.Put("/me", h.UpdateMe)
+		r.Delete("/me", h.DeleteMe)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.RequireRole(db.UserRoleAdmin))
+
+			r.Get("/", h.GetAllUsers)
+			r.Get("/{id}", h.GetUser)
+			r.Post("/{id}/disable", h.Disable)
+			r.Post("/{id}/enable", h.Enable)
+			r.Post("/{id}/impersonate", h.Impersonate)
+		})
 	})
 }
 
@@ -46,22 +59,74 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
-	token, err := h.svc.Login(ctx, h.token, *data)
+	resp, err := h.svc.Login(ctx, h.token, *data)
 	if err != nil {
 		if errors.Is(err, ErrUserNotFound) {
 			httputils.Error(w, r, http.StatusBadRequest, map[string]string{"error": "invalid credential"})
 			return
 		}
 
+		if errors.Is(err, ErrUserDisabled) {
+			httputils.Error(w, r, http.StatusForbidden, map[string]string{"error": err.Error()})
+			return
+		}
+
 		httputils.Error(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 		return
 	}
 
-	resp := UserLoginResponse{Token: token}
+	_ = httputils.EncodeJson(w, r, http.StatusOK, resp)
+}
+
+func (h *UserHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, problems, err := httputils.DecodeValidJson[*UserRefreshRequest](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	resp, err := h.svc.Refresh(ctx, h.token, data.RefreshToken)
+	if err != nil {
+		if errors.Is(err, ErrInvalidRefreshToken) || errors.Is(err, ErrRefreshTokenReused) {
+			httputils.Error(w, r, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+			return
+		}
+
+		httputils.Error(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
 
 	_ = httputils.EncodeJson(w, r, http.StatusOK, resp)
 }
 
+// Logout takes the refresh token rather than the access token, so a client
+// can still log out after its access token expired.
+func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, problems, err := httputils.DecodeValidJson[*UserRefreshRequest](r)
+	if err != nil {
+		_ = httputils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.svc.Logout(ctx, data.RefreshToken); err != nil {
+		if errors.Is(err, ErrInvalidRefreshToken) {
+			httputils.Error(w, r, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+			return
+		}
+
+		httputils.Error(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
 func (h *UserHandler) Signup(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -85,32 +150,46 @@ func (h *UserHandler) Signup(w http.ResponseWriter, r *http.Request) {
 	httputils.Created(w)
 }
 
-func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+func (h *UserHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	h.writeUser(w, r, userID)
+}
+
+// GetUser lets an admin read any user.
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	record, err := h.svc.GetUser(ctx, id)
+	if _, err := uuid.Parse(id); err != nil {
+		httputils.Error(w, r, http.StatusNotFound, map[string]string{"error": "user not found"})
+		return
+	}
+
+	h.writeUser(w, r, id)
+}
+
+func (h *UserHandler) writeUser(w http.ResponseWriter, r *http.Request, id string) {
+	record, err := h.svc.GetUser(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, ErrUserNotFound) {
 			httputils.Error(w, r, http.StatusNotFound, map[string]string{"error": "user not found"})
 			return
 		}
 
-		httputils.Error(w, r, http.StatusInternalServerError, err.Error())
+		httputils.Error(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 		return
 	}
 
-	response := UserResponse{
-		ID:        record.ID.String(),
-		Name:      record.Name,
-		Email:     record.Email,
-		CreatedAt: record.CreatedAt,
-		UpdatedAt: record.UpdatedAt,
-	}
-
-	httputils.EncodeJson(w, r, http.StatusOK, response)
+	httputils.EncodeJson(w, r, http.StatusOK, UserToResponse(record))
 }
 
+// GetAllUsers lists every user. Only admins can reach it.
 func (h *UserHandler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -127,21 +206,20 @@ func (h *UserHandler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 
 	response := make([]UserResponse, len(records))
 	for i, record := range records {
-		response[i] = UserResponse{
-			ID:        record.ID.String(),
-			Name:      record.Name,
-			Email:     record.Email,
-			CreatedAt: record.CreatedAt,
-			UpdatedAt: record.UpdatedAt,
-		}
+		response[i] = UserToResponse(record)
 	}
 
 	httputils.EncodeJson(w, r, http.StatusOK, response)
 }
 
-func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
+func (h *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	id := chi.URLParam(r, "id")
+
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
 
 	data, problems, err := httputils.DecodeValidJson[*UserUpdateRequest](r)
 	if err != nil {
@@ -150,7 +228,7 @@ func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
-	if err := h.svc.Update(ctx, id, *data); err != nil {
+	if err := h.svc.Update(ctx, userID, *data); err != nil {
 		if errors.Is(err, ErrUserNotFound) {
 			httputils.Error(w, r, http.StatusNotFound, map[string]string{"error": "user not found"})
 			return
@@ -168,19 +246,89 @@ func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
 	httputils.NoContent(w)
 }
 
-func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
+func (h *UserHandler) DeleteMe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || userID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	if err := h.svc.Delete(ctx, userID); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			httputils.Error(w, r, http.StatusNotFound, map[string]string{"error": "user not found"})
+			return
+		}
+
+		httputils.Error(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	httputils.NoContent(w)
+}
+
+func (h *UserHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, true)
+}
+
+func (h *UserHandler) Enable(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, false)
+}
+
+func (h *UserHandler) setDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
 	ctx := r.Context()
 	id := chi.URLParam(r, "id")
 
-	if err := h.svc.Delete(ctx, id); err != nil {
+	adminID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || adminID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	if err := h.svc.SetDisabled(ctx, adminID, id, disabled); err != nil {
 		if errors.Is(err, ErrUserNotFound) {
 			httputils.Error(w, r, http.StatusNotFound, map[string]string{"error": "user not found"})
 			return
 		}
 
+		if errors.Is(err, ErrCannotDisableSelf) {
+			httputils.Error(w, r, http.StatusConflict, map[string]string{"error": err.Error()})
+			return
+		}
+
 		httputils.Error(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
 		return
 	}
 
 	httputils.NoContent(w)
 }
+
+func (h *UserHandler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := chi.URLParam(r, "id")
+
+	adminID, ok := ctx.Value(middlewares.ContextUserID).(string)
+	if !ok || adminID == "" {
+		httputils.Unauthorized(w)
+		return
+	}
+
+	resp, err := h.svc.Impersonate(ctx, h.token, adminID, id)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			httputils.Error(w, r, http.StatusNotFound, map[string]string{"error": "user not found"})
+			return
+		}
+
+		if errors.Is(err, ErrUserDisabled) || errors.Is(err, ErrCannotImpersonateAdmin) {
+			httputils.Error(w, r, http.StatusConflict, map[string]string{"error": err.Error()})
+			return
+		}
+
+		httputils.Error(w, r, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = httputils.EncodeJson(w, r, http.StatusOK, resp)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Repository interface {
@@ -17,6 +18,18 @@ type Repository interface {
 	GetAllUser(ctx context.Context) ([]*db.User, error)
 	Update(ctx context.Context, arg db.UpdateUserParams) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	Disable(ctx context.Context, id uuid.UUID) error
+	Enable(ctx context.Context, id uuid.UUID) error
+	CreateSession(ctx context.Context, arg db.CreateSessionParams) (uuid.UUID, error)
+	IsSessionRevoked(ctx context.Context, sessionID string) (bool, error)
+	RevokeSession(ctx context.Context, id uuid.UUID) error
+	RevokeUserSessions(ctx context.Context, userID uuid.UUID) error
+	RevokeImpersonatorSessions(ctx context.Context, impersonatorID uuid.UUID) error
+	RevokeSessionByRefreshToken(ctx context.Context, tokenHash string) error
+	CreateRefreshToken(ctx context.Context, arg db.CreateRefreshTokenParams) error
+	GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (*db.GetRefreshTokenForUpdateRow, error)
+	MarkRefreshTokenUsed(ctx context.Context, id uuid.UUID) error
+	WithTx(q *db.Queries) Repository
 }
 
 type userRepository struct {
@@ -32,6 +45,7 @@ func NewUserRepository(db *db.Queries) Repository {
 var ErrDuplicatedCredential = errors.New("credential already exist")
 var ErrUserNotFound = errors.New("user not found")
 var ErrNoUsersFound = errors.New("no users found")
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
 
 func (r *userRepository) Create(ctx context.Context, arg db.CreateUserParams) error {
 	var pgErr *pgconn.PgError
@@ -109,3 +123,83 @@ func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 	return r.db.DeleteUser(ctx, id)
 }
+
+func (r *userRepository) Disable(ctx context.Context, id uuid.UUID) error {
+	return r.db.DisableUser(ctx, id)
+}
+
+func (r *userRepository) Enable(ctx context.Context, id uuid.UUID) error {
+	return r.db.EnableUser(ctx, id)
+}
+
+func (r *userRepository) CreateSession(ctx context.Context, arg db.CreateSessionParams) (uuid.UUID, error) {
+	return r.db.CreateSession(ctx, arg)
+}
+
+// IsSessionRevoked implements token.RevocationList. Unknown sessions count as
+// revoked.
+func (r *userRepository) IsSessionRevoked(ctx context.Context, sessionID string) (bool, error) {
+	id, err := uuid.Parse(sessionID)
+	if err != nil {
+		return true, nil
+	}
+
+	revoked, err := r.db.IsSessionRevoked(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	return revoked, nil
+}
+
+func (r *userRepository) RevokeSession(ctx context.Context, id uuid.UUID) error {
+	return r.db.RevokeSession(ctx, id)
+}
+
+func (r *userRepository) RevokeUserSessions(ctx context.Context, userID uuid.UUID) error {
+	return r.db.RevokeUserSessions(ctx, userID)
+}
+
+func (r *userRepository) RevokeImpersonatorSessions(ctx context.Context, impersonatorID uuid.UUID) error {
+	return r.db.RevokeImpersonatorSessions(ctx, pgtype.UUID{Bytes: impersonatorID, Valid: true})
+}
+
+func (r *userRepository) RevokeSessionByRefreshToken(ctx context.Context, tokenHash string) error {
+	rows, err := r.db.RevokeSessionByRefreshToken(ctx, tokenHash)
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrInvalidRefreshToken
+	}
+
+	return nil
+}
+
+func (r *userRepository) CreateRefreshToken(ctx context.Context, arg db.CreateRefreshTokenParams) error {
+	return r.db.CreateRefreshToken(ctx, arg)
+}
+
+func (r *userRepository) GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (*db.GetRefreshTokenForUpdateRow, error) {
+	record, err := r.db.GetRefreshTokenForUpdate(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (r *userRepository) MarkRefreshTokenUsed(ctx context.Context, id uuid.UUID) error {
+	return r.db.MarkRefreshTokenUsed(ctx, id)
+}
+
+func (r *userRepository) WithTx(q *db.Queries) Repository {
+	return &userRepository{db: q}
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/EduardoMark/my-finance-api/internal/store/pgstore"
 	"github.com/EduardoMark/my-finance-api/internal/store/pgstore/db"
 	"github.com/EduardoMark/my-finance-api/internal/validator"
 	"github.com/EduardoMark/my-finance-api/pkg/hash"
 	"github.com/EduardoMark/my-finance-api/pkg/token"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Service interface {
@@ -19,17 +22,27 @@ type Service interface {
 	GetAllUsers(ctx context.Context) ([]*db.User, error)
 	Update(ctx context.Context, id string, arg UserUpdateRequest) error
 	Delete(ctx context.Context, id string) error
-	Login(ctx context.Context, tm *token.TokenManager, dto UserLoginRequest) (string, error)
+	Login(ctx context.Context, tm *token.TokenManager, dto UserLoginRequest) (*UserLoginResponse, error)
+	Refresh(ctx context.Context, tm *token.TokenManager, refreshToken string) (*UserLoginResponse, error)
+	Logout(ctx context.Context, refreshToken string) error
+	SetDisabled(ctx context.Context, adminID, id string, disabled bool) error
+	Impersonate(ctx context.Context, tm *token.TokenManager, adminID, id string) (*UserLoginResponse, error)
 }
 
 type userService struct {
 	repo Repository
+	uow  pgstore.UnitOfWork
 }
 
-func NewUserService(repo Repository) Service {
-	return &userService{repo: repo}
+func NewUserService(repo Repository, uow pgstore.UnitOfWork) Service {
+	return &userService{repo: repo, uow: uow}
 }
 
+var ErrRefreshTokenReused = errors.New("refresh token already used, session revoked")
+var ErrUserDisabled = errors.New("user is disabled")
+var ErrCannotDisableSelf = errors.New("admins cannot disable themselves")
+var ErrCannotImpersonateAdmin = errors.New("admins cannot be impersonated")
+
 func (s *userService) Create(ctx context.Context, dto UserCreateRequest) error {
 	password, err := hash.HashPassword(dto.Password)
 	if err != nil {
@@ -94,10 +107,11 @@ func (s *userService) Update(ctx context.Context, id string, arg UserUpdateReque
 	}
 
 	updatedParams := db.UpdateUserParams{
-		ID:       idUUID,
-		Name:     record.Name,
-		Email:    record.Email,
-		Password: record.Password,
+		ID:           idUUID,
+		Name:         record.Name,
+		Email:        record.Email,
+		Password:     record.Password,
+		BaseCurrency: record.BaseCurrency,
 	}
 
 	if validator.NotBlank(arg.Name) {
@@ -116,10 +130,21 @@ func (s *userService) Update(ctx context.Context, id string, arg UserUpdateReque
 		updatedParams.Password = hashPassword
 	}
 
+	if validator.NotBlank(arg.BaseCurrency) {
+		updatedParams.BaseCurrency = arg.BaseCurrency
+	}
+
 	if err := s.repo.Update(ctx, updatedParams); err != nil {
 		return err
 	}
 
+	// A new password logs out every session, in case the old one leaked.
+	if validator.NotBlank(arg.Password) {
+		if err := s.repo.RevokeUserSessions(ctx, idUUID); err != nil {
+			return fmt.Errorf("error on revoke sessions: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -133,23 +158,237 @@ func (s *userService) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (s *userService) Login(ctx context.Context, tm *token.TokenManager, dto UserLoginRequest) (string, error) {
+// Login starts a new session and returns its first token pair.
+func (s *userService) Login(ctx context.Context, tm *token.TokenManager, dto UserLoginRequest) (*UserLoginResponse, error) {
 	record, err := s.repo.GetUserByEmail(ctx, dto.Email)
 	if err != nil {
 		if errors.Is(err, ErrUserNotFound) {
-			return "", ErrUserNotFound
+			return nil, ErrUserNotFound
 		}
-		return "", fmt.Errorf("error on search user: %w", err)
+		return nil, fmt.Errorf("error on search user: %w", err)
 	}
 
 	if err := hash.ComparePassword(dto.Password, record.Password); err != nil {
-		return "", errors.New("invalid credentials")
+		return nil, errors.New("invalid credentials")
+	}
+
+	if record.DisabledAt.Valid {
+		return nil, ErrUserDisabled
 	}
 
-	token, err := tm.GenerateToken(record.ID.String(), record.Name)
+	var sessionID uuid.UUID
+	var refreshToken string
+
+	err = s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+
+		sessionID, err = repo.CreateSession(ctx, db.CreateSessionParams{UserID: record.ID})
+		if err != nil {
+			return err
+		}
+
+		refreshToken, err = createRefreshToken(ctx, repo, sessionID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error on create session: %w", err)
+	}
+
+	identity := token.Identity{
+		UserID:    record.ID.String(),
+		Name:      record.Name,
+		Role:      string(record.Role),
+		SessionID: sessionID.String(),
+	}
+
+	return tokenPair(tm, identity, refreshToken)
+}
+
+// Refresh rotates a refresh token: the presented token is marked as used and
+// a new pair is issued in the same session. Presenting a used token again
+// means it leaked, so the whole session is revoked and ErrRefreshTokenReused
+// is returned.
+func (s *userService) Refresh(ctx context.Context, tm *token.TokenManager, refreshToken string) (*UserLoginResponse, error) {
+	var record *db.GetRefreshTokenForUpdateRow
+	var newToken string
+	reused := false
+
+	err := s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+
+		var err error
+		record, err = repo.GetRefreshTokenForUpdate(ctx, token.HashRefreshToken(refreshToken))
+		if err != nil {
+			return err
+		}
+
+		if record.RevokedAt.Valid || !record.ExpiresAt.Time.After(time.Now()) {
+			return ErrInvalidRefreshToken
+		}
+
+		// The revocation must be committed, so this is not returned as an
+		// error from the transaction.
+		if record.UsedAt.Valid {
+			reused = true
+			return repo.RevokeSession(ctx, record.SessionID)
+		}
+
+		if err := repo.MarkRefreshTokenUsed(ctx, record.ID); err != nil {
+			return err
+		}
+
+		newToken, err = createRefreshToken(ctx, repo, record.SessionID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidRefreshToken) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("error on refresh token: %w", err)
+	}
+
+	if reused {
+		return nil, ErrRefreshTokenReused
+	}
+
+	identity := token.Identity{
+		UserID:    record.UserID.String(),
+		Name:      record.Name,
+		Role:      string(record.Role),
+		SessionID: record.SessionID.String(),
+	}
+
+	return tokenPair(tm, identity, newToken)
+}
+
+// Logout revokes the session of the refresh token, which also invalidates
+// the access tokens issued in it.
+func (s *userService) Logout(ctx context.Context, refreshToken string) error {
+	if err := s.repo.RevokeSessionByRefreshToken(ctx, token.HashRefreshToken(refreshToken)); err != nil {
+		if errors.Is(err, ErrInvalidRefreshToken) {
+			return ErrInvalidRefreshToken
+		}
+		return fmt.Errorf("error on logout: %w", err)
+	}
+
+	return nil
+}
+
+// createRefreshToken stores a new refresh token for the session and returns
+// it in plain text.
+func createRefreshToken(ctx context.Context, repo Repository, sessionID uuid.UUID) (string, error) {
+	refreshToken, tokenHash, err := token.GenerateRefreshToken()
 	if err != nil {
 		return "", err
 	}
 
-	return token, err
+	arg := db.CreateRefreshTokenParams{
+		SessionID: sessionID,
+		TokenHash: tokenHash,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(token.RefreshTokenTTL), Valid: true},
+	}
+
+	if err := repo.CreateRefreshToken(ctx, arg); err != nil {
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+// SetDisabled disables or re-enables a user. Disabling also revokes every
+// session of the user, so their tokens stop working at once.
+func (s *userService) SetDisabled(ctx context.Context, adminID, id string, disabled bool) error {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if disabled && id == adminID {
+		return ErrCannotDisableSelf
+	}
+
+	if _, err := s.repo.GetUser(ctx, idUUID); err != nil {
+		return err
+	}
+
+	if !disabled {
+		return s.repo.Enable(ctx, idUUID)
+	}
+
+	err = s.uow.ExecTx(ctx, func(q *db.Queries) error {
+		repo := s.repo.WithTx(q)
+
+		if err := repo.Disable(ctx, idUUID); err != nil {
+			return err
+		}
+
+		if err := repo.RevokeUserSessions(ctx, idUUID); err != nil {
+			return err
+		}
+
+		// A disabled admin also loses the sessions opened impersonating
+		// other users.
+		return repo.RevokeImpersonatorSessions(ctx, idUUID)
+	})
+	if err != nil {
+		return fmt.Errorf("error on disable user: %w", err)
+	}
+
+	return nil
+}
+
+// Impersonate lets an admin act as a user. It only returns an access token,
+// so the session ends when the token expires; the token records the admin
+// as impersonator.
+func (s *userService) Impersonate(ctx context.Context, tm *token.TokenManager, adminID, id string) (*UserLoginResponse, error) {
+	idUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	record, err := s.repo.GetUser(ctx, idUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.DisabledAt.Valid {
+		return nil, ErrUserDisabled
+	}
+
+	if record.Role == db.UserRoleAdmin {
+		return nil, ErrCannotImpersonateAdmin
+	}
+
+	arg := db.CreateSessionParams{
+		UserID:         record.ID,
+		ImpersonatorID: pgtype.UUID{Bytes: uuid.MustParse(adminID), Valid: true},
+	}
+
+	sessionID, err := s.repo.CreateSession(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("error on create session: %w", err)
+	}
+
+	identity := token.Identity{
+		UserID:         record.ID.String(),
+		Name:           record.Name,
+		Role:           string(record.Role),
+		SessionID:      sessionID.String(),
+		ImpersonatorID: adminID,
+	}
+
+	return tokenPair(tm, identity, "")
+}
+
+func tokenPair(tm *token.TokenManager, identity token.Identity, refreshToken string) (*UserLoginResponse, error) {
+	accessToken, expiresAt, err := tm.GenerateToken(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserLoginResponse{
+		Token:        accessToken,
+		ExpiresAt:    expiresAt,
+		RefreshToken: refreshToken,
+	}, nil
 }
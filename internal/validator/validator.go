@@ -4,7 +4,11 @@ import (
 	"context"
 	"regexp"
 	"strings"
+	"time"
 	"unicode/utf8"
+
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+	"github.com/google/uuid"
 )
 
 type Validator interface {
@@ -31,6 +35,8 @@ func (e *Evaluator) CheckField(ok bool, key, message string) {
 
 var EmailRX = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
 
+var CurrencyRX = regexp.MustCompile("^[A-Z]{3}$")
+
 func NotBlank(value string) bool {
 	return strings.TrimSpace(value) != ""
 }
@@ -52,6 +58,27 @@ func TransactionType(value string) bool {
 	return value == "income" || value == "expense"
 }
 
-func CheckBalance(value float64) bool {
+func UUID(value string) bool {
+	_, err := uuid.Parse(value)
+	return err == nil
+}
+
+func Date(value string) bool {
+	_, err := time.Parse("2006-01-02", value)
+	return err == nil
+}
+
+// Period checks a month in the format YYYY-MM.
+func Period(value string) bool {
+	_, err := time.Parse("2006-01", value)
+	return err == nil
+}
+
+// Currency checks the format of an ISO 4217 code such as "BRL".
+func Currency(value string) bool {
+	return CurrencyRX.MatchString(value)
+}
+
+func CheckBalance(value money.Money) bool {
 	return value >= 0
 }
@@ -29,6 +29,10 @@ func Unauthorized(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusUnauthorized)
 }
 
+func Forbidden(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusForbidden)
+}
+
 func NotFound(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusNotFound)
 }
@@ -0,0 +1,144 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Money is an exact amount stored in minor units (cents). It is persisted as
+// BIGINT and travels over JSON as a decimal number with two places.
+type Money int64
+
+const scale = 100
+
+var ErrInvalidAmount = errors.New("invalid monetary amount")
+
+func FromCents(cents int64) Money {
+	return Money(cents)
+}
+
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// Parse reads a decimal string such as "150.5" or "-3.05". More than two
+// decimal places are rejected instead of rounded.
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidAmount
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, ErrInvalidAmount
+	}
+
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("%w: more than 2 decimal places", ErrInvalidAmount)
+	}
+
+	if !isDigits(whole) || !isDigits(frac) {
+		return 0, ErrInvalidAmount
+	}
+
+	frac += strings.Repeat("0", 2-len(frac))
+	if whole == "" {
+		whole = "0"
+	}
+
+	cents, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidAmount, err)
+	}
+
+	if negative {
+		cents = -cents
+	}
+
+	return Money(cents), nil
+}
+
+func (m Money) String() string {
+	cents := int64(m)
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/scale, cents%scale)
+}
+
+func (m Money) Neg() Money {
+	return -m
+}
+
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts both JSON numbers and quoted decimal strings.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// ScanInt64 implements the pgtype.Int64Scanner interface.
+func (m *Money) ScanInt64(v pgtype.Int8) error {
+	if !v.Valid {
+		*m = 0
+		return nil
+	}
+
+	*m = Money(v.Int64)
+	return nil
+}
+
+// Int64Value implements the pgtype.Int64Valuer interface.
+func (m Money) Int64Value() (pgtype.Int8, error) {
+	return pgtype.Int8{Int64: int64(m), Valid: true}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
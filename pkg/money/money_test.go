@@ -0,0 +1,115 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+	}{
+		{"150", 15000},
+		{"150.5", 15050},
+		{"1.5", 150},
+		{"3.05", 305},
+		{"-3.05", -305},
+		{"-0.5", -50},
+		{"+2.10", 210},
+		{".75", 75},
+		{"12.", 1200},
+		{" 7.25 ", 725},
+		{"0", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, in := range []string{"", "-", ".", "1.234", "0.001", "1,50", "abc", "1.5.0", "--1", "1e3", "99999999999999999999"} {
+		t.Run(in, func(t *testing.T) {
+			if _, err := Parse(in); !errors.Is(err, ErrInvalidAmount) {
+				t.Errorf("Parse(%q) error = %v, want ErrInvalidAmount", in, err)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{150, "1.50"},
+		{15050, "150.50"},
+		{-5, "-0.05"},
+		{-305, "-3.05"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type body struct {
+		Amount Money `json:"amount"`
+	}
+
+	tests := []struct {
+		in      string
+		want    Money
+		encoded string
+	}{
+		{`{"amount": 1.5}`, 150, `{"amount":1.50}`},
+		{`{"amount": "1.5"}`, 150, `{"amount":1.50}`},
+		{`{"amount": -3.05}`, -305, `{"amount":-3.05}`},
+		{`{"amount": 100}`, 10000, `{"amount":100.00}`},
+		{`{"amount": null}`, 0, `{"amount":0.00}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			var got body
+			if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+				t.Fatalf("Unmarshal error = %v", err)
+			}
+			if got.Amount != tt.want {
+				t.Errorf("Amount = %d, want %d", got.Amount, tt.want)
+			}
+
+			encoded, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("Marshal error = %v", err)
+			}
+			if string(encoded) != tt.encoded {
+				t.Errorf("Marshal = %s, want %s", encoded, tt.encoded)
+			}
+		})
+	}
+}
+
+func TestJSONInvalid(t *testing.T) {
+	var m Money
+	for _, in := range []string{`1.234`, `"1.234"`, `"abc"`, `true`} {
+		if err := json.Unmarshal([]byte(in), &m); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("Unmarshal(%s) error = %v, want ErrInvalidAmount", in, err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package ofx reads bank and credit card statements from OFX/QFX files. Both
+// OFX 1.x (SGML, where leaf elements are usually left unclosed) and OFX 2.x
+// (XML) are supported.
+package ofx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+)
+
+var ErrInvalidFile = errors.New("invalid OFX file")
+
+type Statement struct {
+	Currency      string
+	AccountID     string
+	Transactions  []Transaction
+	LedgerBalance *Balance
+}
+
+type Transaction struct {
+	FITID  string
+	Type   string // TRNTYPE, e.g. DEBIT, CREDIT, POS
+	Posted time.Time
+	Amount money.Money // negative for money leaving the account
+	Name   string
+	Memo   string
+}
+
+type Balance struct {
+	Amount money.Money
+	AsOf   time.Time
+}
+
+// Description is the text that best describes the transaction: NAME, or MEMO
+// when the bank leaves NAME empty.
+func (t Transaction) Description() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.Memo
+}
+
+// Parse reads every bank (STMTRS) and credit card (CCSTMTRS) statement of an
+// OFX file.
+func Parse(r io.Reader) ([]Statement, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parseTree(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []Statement
+	for _, node := range root.findAll("STMTRS", "CCSTMTRS") {
+		statement, err := readStatement(node)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, statement)
+	}
+
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("%w: no statement found", ErrInvalidFile)
+	}
+
+	return statements, nil
+}
+
+func readStatement(node *element) (Statement, error) {
+	statement := Statement{
+		Currency:  node.value("CURDEF"),
+		AccountID: node.path("BANKACCTFROM", "ACCTID"),
+	}
+
+	if statement.AccountID == "" {
+		statement.AccountID = node.path("CCACCTFROM", "ACCTID")
+	}
+
+	if list := node.child("BANKTRANLIST"); list != nil {
+		for _, trn := range list.findAll("STMTTRN") {
+			transaction, err := readTransaction(trn)
+			if err != nil {
+				return Statement{}, err
+			}
+			statement.Transactions = append(statement.Transactions, transaction)
+		}
+	}
+
+	if ledger := node.child("LEDGERBAL"); ledger != nil {
+		amount, err := parseAmount(ledger.value("BALAMT"))
+		if err != nil {
+			return Statement{}, fmt.Errorf("%w: LEDGERBAL: %v", ErrInvalidFile, err)
+		}
+
+		asOf, err := parseDate(ledger.value("DTASOF"))
+		if err != nil {
+			return Statement{}, fmt.Errorf("%w: LEDGERBAL: %v", ErrInvalidFile, err)
+		}
+
+		statement.LedgerBalance = &Balance{Amount: amount, AsOf: asOf}
+	}
+
+	return statement, nil
+}
+
+func readTransaction(node *element) (Transaction, error) {
+	transaction := Transaction{
+		FITID: node.value("FITID"),
+		Type:  node.value("TRNTYPE"),
+		Name:  node.value("NAME"),
+		Memo:  node.value("MEMO"),
+	}
+
+	if transaction.FITID == "" {
+		return Transaction{}, fmt.Errorf("%w: STMTTRN without FITID", ErrInvalidFile)
+	}
+
+	posted, err := parseDate(node.value("DTPOSTED"))
+	if err != nil {
+		return Transaction{}, fmt.Errorf("%w: STMTTRN %s: %v", ErrInvalidFile, transaction.FITID, err)
+	}
+	transaction.Posted = posted
+
+	amount, err := parseAmount(node.value("TRNAMT"))
+	if err != nil {
+		return Transaction{}, fmt.Errorf("%w: STMTTRN %s: %v", ErrInvalidFile, transaction.FITID, err)
+	}
+	transaction.Amount = amount
+
+	return transaction, nil
+}
+
+// parseDate reads the date part of an OFX datetime such as
+// 20240115120000.000[-3:BRT]. The time of day is dropped.
+func parseDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+
+	return date, nil
+}
+
+// parseAmount accepts both "." and "," as decimal separator, as some banks
+// export the latter, and extra decimal places when they are zeros.
+func parseAmount(value string) (money.Money, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, ".") {
+		value = strings.Replace(value, ",", ".", 1)
+	}
+
+	if whole, frac, ok := strings.Cut(value, "."); ok && len(frac) > 2 {
+		value = whole + "." + strings.TrimRight(frac, "0")
+	}
+
+	amount, err := money.Parse(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+
+	return amount, nil
+}
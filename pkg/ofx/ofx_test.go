@@ -0,0 +1,127 @@
+package ofx
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EduardoMark/my-finance-api/pkg/money"
+)
+
+func parseFixture(t *testing.T, name string) []Statement {
+	t.Helper()
+
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	statements, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Parse(%s): %v", name, err)
+	}
+	return statements
+}
+
+func date(s string) time.Time {
+	d, _ := time.Parse("2006-01-02", s)
+	return d
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		fixture      string
+		currency     string
+		accountID    string
+		transactions []Transaction
+		ledger       Balance
+	}{
+		{
+			fixture:   "bank_v1.ofx",
+			currency:  "BRL",
+			accountID: "12345-6",
+			transactions: []Transaction{
+				{FITID: "202401050001", Type: "CREDIT", Posted: date("2024-01-05"), Amount: money.FromCents(500000), Name: "SALARIO"},
+				{FITID: "202401100002", Type: "DEBIT", Posted: date("2024-01-10"), Amount: money.FromCents(-15050), Memo: "MERCADO & CIA"},
+				{FITID: "202401150003", Type: "POS", Posted: date("2024-01-15"), Amount: money.FromCents(-4990), Name: "PADARIA", Memo: "COMPRA CARTAO"},
+			},
+			ledger: Balance{Amount: money.FromCents(479960), AsOf: date("2024-01-31")},
+		},
+		{
+			fixture:   "creditcard_v2.ofx",
+			currency:  "USD",
+			accountID: "4111111111111111",
+			transactions: []Transaction{
+				{FITID: "CC-0001", Type: "DEBIT", Posted: date("2024-02-03"), Amount: money.FromCents(-2345), Name: "COFFEE <DOWNTOWN>"},
+				{FITID: "CC-0002", Type: "CREDIT", Posted: date("2024-02-20"), Amount: money.FromCents(10000), Name: "PAYMENT THANK YOU"},
+			},
+			ledger: Balance{Amount: money.FromCents(-51230), AsOf: date("2024-02-29")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			statements := parseFixture(t, tt.fixture)
+			if len(statements) != 1 {
+				t.Fatalf("got %d statements, want 1", len(statements))
+			}
+
+			got := statements[0]
+			if got.Currency != tt.currency {
+				t.Errorf("Currency = %q, want %q", got.Currency, tt.currency)
+			}
+			if got.AccountID != tt.accountID {
+				t.Errorf("AccountID = %q, want %q", got.AccountID, tt.accountID)
+			}
+
+			if len(got.Transactions) != len(tt.transactions) {
+				t.Fatalf("got %d transactions, want %d", len(got.Transactions), len(tt.transactions))
+			}
+			for i, want := range tt.transactions {
+				if got.Transactions[i] != want {
+					t.Errorf("transaction %d = %+v, want %+v", i, got.Transactions[i], want)
+				}
+			}
+
+			if got.LedgerBalance == nil {
+				t.Fatal("LedgerBalance is nil")
+			}
+			if *got.LedgerBalance != tt.ledger {
+				t.Errorf("LedgerBalance = %+v, want %+v", *got.LedgerBalance, tt.ledger)
+			}
+		})
+	}
+}
+
+func TestTransactionDescription(t *testing.T) {
+	statements := parseFixture(t, "bank_v1.ofx")
+
+	want := []string{"SALARIO", "MERCADO & CIA", "PADARIA"}
+	for i, transaction := range statements[0].Transactions {
+		if got := transaction.Description(); got != want[i] {
+			t.Errorf("transaction %d: Description() = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := map[string]string{
+		"not ofx":       "date,description,amount\n2024-01-01,x,1\n",
+		"no statement":  "<OFX><SIGNONMSGSRSV1></SIGNONMSGSRSV1></OFX>",
+		"missing fitid": "<OFX><STMTRS><BANKTRANLIST><STMTTRN><DTPOSTED>20240101<TRNAMT>1.00</STMTTRN></BANKTRANLIST></STMTRS></OFX>",
+		"bad amount":    "<OFX><STMTRS><BANKTRANLIST><STMTTRN><FITID>1<DTPOSTED>20240101<TRNAMT>abc</STMTTRN></BANKTRANLIST></STMTRS></OFX>",
+		"bad date":      "<OFX><STMTRS><BANKTRANLIST><STMTTRN><FITID>1<DTPOSTED>2024<TRNAMT>1.00</STMTTRN></BANKTRANLIST></STMTRS></OFX>",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(input))
+			if !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("Parse() error = %v, want ErrInvalidFile", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package ofx
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// element is a node of the OFX document. Aggregates have children, leaf
+// elements have text.
+type element struct {
+	name     string
+	text     string
+	children []*element
+}
+
+// parseTree builds the element tree of the <OFX> root. The SGML headers of
+// OFX 1.x and the XML prolog of OFX 2.x are skipped.
+//
+// A leaf is an element whose start tag is followed by text. Its end tag is
+// optional, as in SGML. An end tag closes the innermost open aggregate with
+// that name, which also closes anything left open inside it.
+func parseTree(data string) (*element, error) {
+	start := strings.Index(strings.ToUpper(data), "<OFX>")
+	if start < 0 {
+		return nil, fmt.Errorf("%w: missing <OFX> root", ErrInvalidFile)
+	}
+	data = data[start:]
+
+	root := &element{}
+	stack := []*element{root}
+
+	for len(data) > 0 {
+		open := strings.IndexByte(data, '<')
+		if open < 0 {
+			break
+		}
+
+		end := strings.IndexByte(data[open:], '>')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unterminated tag", ErrInvalidFile)
+		}
+		end += open
+
+		tag := strings.TrimSpace(data[open+1 : end])
+		data = data[end+1:]
+
+		switch {
+		case tag == "" || strings.HasPrefix(tag, "?") || strings.HasPrefix(tag, "!"):
+			continue
+		case strings.HasPrefix(tag, "/"):
+			name := strings.ToUpper(strings.TrimSpace(tag[1:]))
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].name == name {
+					stack = stack[:i]
+					break
+				}
+			}
+			continue
+		}
+
+		// Self-closing tags (<TAG/>) only appear in XML and carry no value.
+		selfClosing := strings.HasSuffix(tag, "/")
+		name := strings.ToUpper(strings.Fields(strings.TrimSuffix(tag, "/"))[0])
+		node := &element{name: name}
+		parent := stack[len(stack)-1]
+		parent.children = append(parent.children, node)
+
+		if selfClosing {
+			continue
+		}
+
+		text := data
+		if next := strings.IndexByte(data, '<'); next >= 0 {
+			text = data[:next]
+		}
+
+		if value := strings.TrimSpace(text); value != "" {
+			node.text = html.UnescapeString(value)
+			data = data[len(text):]
+
+			// Skip the optional end tag of the leaf.
+			closing := "</" + name + ">"
+			if len(data) >= len(closing) && strings.EqualFold(data[:len(closing)], closing) {
+				data = data[len(closing):]
+			}
+			continue
+		}
+
+		stack = append(stack, node)
+	}
+
+	return root, nil
+}
+
+// child returns the first direct child with the given name.
+func (e *element) child(name string) *element {
+	for _, c := range e.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// value returns the value of the first direct child with the given name.
+func (e *element) value(name string) string {
+	if c := e.child(name); c != nil {
+		return c.text
+	}
+	return ""
+}
+
+// path follows children by name and returns the value at the end.
+func (e *element) path(names ...string) string {
+	node := e
+	for _, name := range names[:len(names)-1] {
+		if node = node.child(name); node == nil {
+			return ""
+		}
+	}
+	return node.value(names[len(names)-1])
+}
+
+// findAll returns every descendant with one of the given names, in document
+// order. Matching elements are not searched further.
+func (e *element) findAll(names ...string) []*element {
+	var found []*element
+	for _, c := range e.children {
+		matched := false
+		for _, name := range names {
+			if c.name == name {
+				matched = true
+				break
+			}
+		}
+
+		if matched {
+			found = append(found, c)
+			continue
+		}
+		found = append(found, c.findAll(names...)...)
+	}
+	return found
+}
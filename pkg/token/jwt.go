@@ -1,34 +1,83 @@
 package token
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
 
 	"github.com/EduardoMark/my-finance-api/pkg/config"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// AccessTokenTTL is short so a leaked access token is useful only briefly;
+// clients renew it with a refresh token.
+const AccessTokenTTL = 15 * time.Minute
+
+// RefreshTokenTTL is how long a refresh token can be used. Every refresh
+// issues a new one, so an active client stays logged in.
+const RefreshTokenTTL = 30 * 24 * time.Hour
+
 type claims struct {
-	UserID string `json:"user_id"`
-	Name   string `json:"name"`
+	UserID         string `json:"user_id"`
+	Name           string `json:"name"`
+	Role           string `json:"role"`
+	SessionID      string `json:"sid"`
+	ImpersonatorID string `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// Identity is who an access token is issued to. ImpersonatorID is set when
+// an admin acts as the user.
+type Identity struct {
+	UserID         string
+	Name           string
+	Role           string
+	SessionID      string
+	ImpersonatorID string
+}
+
+// RevocationList tells whether a session was revoked, e.g. by a logout.
+type RevocationList interface {
+	IsSessionRevoked(ctx context.Context, sessionID string) (bool, error)
+}
+
 type TokenManager struct {
-	secret string
+	secret      string
+	revocations RevocationList
 }
 
 func NewTokenManager(cfg config.Env) *TokenManager {
 	return &TokenManager{secret: cfg.JWTSecret}
 }
 
-func (s *TokenManager) GenerateToken(userID, name string) (string, error) {
+// UseRevocationList makes IsRevoked check sessions against list. It must be
+// called before the manager is used by any request.
+func (s *TokenManager) UseRevocationList(list RevocationList) {
+	s.revocations = list
+}
+
+// GenerateToken issues an access token for the session. Each token gets a
+// unique ID (jti).
+func (s *TokenManager) GenerateToken(id Identity) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(AccessTokenTTL)
+
 	claims := claims{
-		UserID: userID,
-		Name:   name,
+		UserID:         id.UserID,
+		Name:           id.Name,
+		Role:           id.Role,
+		SessionID:      id.SessionID,
+		ImpersonatorID: id.ImpersonatorID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 		},
 	}
 
@@ -36,10 +85,10 @@ func (s *TokenManager) GenerateToken(userID, name string) (string, error) {
 
 	tokenStr, err := token.SignedString([]byte(s.secret))
 	if err != nil {
-		return "", fmt.Errorf("error on parse token to string: %w", err)
+		return "", time.Time{}, fmt.Errorf("error on parse token to string: %w", err)
 	}
 
-	return tokenStr, nil
+	return tokenStr, expiresAt, nil
 }
 
 func (s *TokenManager) VerifyToken(tokenStr string) (*claims, error) {
@@ -62,3 +111,36 @@ func (s *TokenManager) VerifyToken(tokenStr string) (*claims, error) {
 
 	return claims, nil
 }
+
+// IsRevoked reports whether the session of a verified token was revoked.
+// Tokens issued before sessions existed have no session and are revoked.
+func (s *TokenManager) IsRevoked(ctx context.Context, c *claims) (bool, error) {
+	if c.SessionID == "" {
+		return true, nil
+	}
+
+	if s.revocations == nil {
+		return false, nil
+	}
+
+	return s.revocations.IsSessionRevoked(ctx, c.SessionID)
+}
+
+// GenerateRefreshToken returns a random opaque refresh token and the hash to
+// store in its place.
+func GenerateRefreshToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("error on generate refresh token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken returns the SHA-256 of a refresh token. A fast hash is
+// enough since the token is random, unlike a password.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}